@@ -0,0 +1,24 @@
+package ecs
+
+import "github.com/jtbonhomme/ebiten-ecs/entity"
+
+// SetActive enables or disables an entity.
+// Inactive entities keep their components and system associations, but are skipped by Update and Draw,
+// which is useful for object pooling or for hiding off-screen rooms without losing their state.
+// Entities are active by default.
+func (ecs *ECS) SetActive(id entity.ID, active bool) {
+	if active {
+		delete(ecs.inactiveEntities, id)
+		return
+	}
+
+	ecs.inactiveEntities[id] = struct{}{}
+}
+
+// IsActive returns true if the entity is active. Dead entities, see Despawn, are not active.
+func (ecs *ECS) IsActive(id entity.ID) bool {
+	_, inactive := ecs.inactiveEntities[id]
+	_, dead := ecs.dying[id]
+
+	return !inactive && !dead
+}
@@ -0,0 +1,108 @@
+package ecs
+
+import (
+	"fmt"
+
+	"github.com/jtbonhomme/ebiten-ecs/component"
+)
+
+// QueriesPerFrameThreshold is the number of QueryComponents calls in a single frame
+// above which the analyzer reports reflection queries used inside tight loops.
+const QueriesPerFrameThreshold uint64 = 4096
+
+// Warning is an anti-pattern detected by the analyzer, with the number of times it has been observed.
+type Warning struct {
+	Kind    string
+	Count   int
+	Message string
+}
+
+// String returns the string representation of the warning.
+func (w Warning) String() string {
+	return fmt.Sprintf("[%s] x%d: %s", w.Kind, w.Count, w.Message)
+}
+
+type analyzer struct {
+	started     bool
+	lastQueried uint64
+	// added is the number of components added to already registered entities during the frame,
+	// the creation of new entities (spawning, batches, pools...) being the expected place to create components.
+	added       uint64
+	allocFrames int
+	allocs      uint64
+	queryFrames int
+	maxQueries  uint64
+}
+
+// EnableAnalyzer turns on the debug-mode analyzer, which detects slow usage patterns at runtime.
+// It measures every frame, from one call to Update to the next one, so it has a small cost
+// and should only be enabled during development.
+func (ecs *ECS) EnableAnalyzer() {
+	if ecs.analyzer == nil {
+		ecs.analyzer = &analyzer{}
+	}
+}
+
+// componentsAdded records components added to an already registered entity.
+func (a *analyzer) componentsAdded(n int) {
+	a.added += uint64(n)
+}
+
+// frame collects the counters of the frame that just ended.
+func (a *analyzer) frame() {
+	_, queried := component.Counters()
+	added := a.added
+	a.added = 0
+
+	if !a.started {
+		a.started = true
+		a.lastQueried = queried
+
+		return
+	}
+
+	if added > 0 {
+		a.allocFrames++
+		a.allocs += added
+	}
+
+	if queries := queried - a.lastQueried; queries > QueriesPerFrameThreshold {
+		a.queryFrames++
+		if queries > a.maxQueries {
+			a.maxQueries = queries
+		}
+	}
+
+	a.lastQueried = queried
+}
+
+// AnalyzerWarnings returns the anti-patterns detected since the analyzer was enabled.
+// It returns nil if the analyzer is not enabled.
+func (ecs *ECS) AnalyzerWarnings() []Warning {
+	a := ecs.analyzer
+	if a == nil {
+		return nil
+	}
+
+	warnings := []Warning{}
+
+	if a.allocFrames > 0 {
+		warnings = append(warnings, Warning{
+			Kind:  "per-frame-allocation",
+			Count: a.allocFrames,
+			Message: fmt.Sprintf("%d components were added to existing entities during frames, "+
+				"create the components of an entity when spawning it and mutate them afterwards", a.allocs),
+		})
+	}
+
+	if a.queryFrames > 0 {
+		warnings = append(warnings, Warning{
+			Kind:  "reflection-query-loop",
+			Count: a.queryFrames,
+			Message: fmt.Sprintf("up to %d QueryComponents calls in a single frame, "+
+				"use component.Type.Get, which does not rely on reflection, instead of QueryComponents inside loops", a.maxQueries),
+		})
+	}
+
+	return warnings
+}
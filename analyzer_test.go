@@ -0,0 +1,56 @@
+package ecs_test
+
+import (
+	"testing"
+
+	ecs "github.com/jtbonhomme/ebiten-ecs"
+	"github.com/jtbonhomme/ebiten-ecs/component"
+	"github.com/jtbonhomme/ebiten-ecs/entity"
+)
+
+type velocity struct {
+	X float64
+}
+
+// frames runs n frames of a world, calling fn during each of them.
+func frames(t *testing.T, world *ecs.ECS, n int, fn func()) {
+	t.Helper()
+
+	for i := 0; i < n; i++ {
+		fn()
+
+		if err := world.Update(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestAnalyzerIgnoresSpawning(t *testing.T) {
+	world, other := ecs.New(), ecs.New()
+	world.EnableAnalyzer()
+
+	frames(t, world, 3, func() {
+		world.Spawn(&position{})
+		world.SpawnBatch(4, newPosition)
+		world.RegisterEntity(entity.New(), component.New(&position{}))
+		other.Spawn(&position{})
+	})
+
+	if w := world.AnalyzerWarnings(); len(w) != 0 {
+		t.Errorf("warnings = %v, want none", w)
+	}
+}
+
+func TestAnalyzerReportsAddedComponents(t *testing.T) {
+	world := ecs.New()
+	world.EnableAnalyzer()
+
+	e := world.Spawn(&position{})
+	frames(t, world, 1, func() {})
+	frames(t, world, 1, func() { world.AddComponents(e, &velocity{}) })
+
+	w := world.AnalyzerWarnings()
+	if len(w) != 1 || w[0].Kind != "per-frame-allocation" || w[0].Count != 1 {
+		t.Errorf("warnings = %v, want one per-frame-allocation warning", w)
+	}
+}
@@ -0,0 +1,63 @@
+package ecs
+
+import (
+	"reflect"
+)
+
+// frameArena is implemented by the per-type arenas of the world, reset at the beginning of each frame.
+type frameArena interface {
+	reset()
+}
+
+// typedArena is the frame arena of the values of type T.
+type typedArena[T any] struct {
+	buf  []T
+	used int
+}
+
+// alloc returns a slice of length 0 and capacity n from the arena, growing it if needed.
+// The slices returned before growing keep their memory until the arena is reset.
+func (a *typedArena[T]) alloc(n int) []T {
+	if a.used+n > len(a.buf) {
+		a.buf = make([]T, max(2*len(a.buf), n, 64))
+		a.used = 0
+	}
+
+	s := a.buf[a.used : a.used : a.used+n]
+	a.used += n
+
+	return s
+}
+
+// reset makes the memory of the arena available again, and clears the values it holds so that
+// they do not keep references alive.
+func (a *typedArena[T]) reset() {
+	clear(a.buf[:a.used])
+	a.used = 0
+}
+
+// FrameAlloc returns a slice of length 0 and capacity n whose memory is taken from an arena of the world,
+// for the temporary slices of the systems (collision pairs, render commands...) without per-frame GC pressure.
+// The arenas are reset at the beginning of each frame, i.e. each call to Update, whether it runs steps or not,
+// so that slices allocated by the updaters and by the drawers are both reclaimed: the slice MUST NOT be kept
+// after the current frame, and appending beyond its capacity allocates a regular slice.
+//
+//	pairs := ecs.FrameAlloc[Pair](world, 256)
+func FrameAlloc[T any](world *ECS, n int) []T {
+	t := reflect.TypeOf((*T)(nil))
+
+	a, ok := world.arenas[t].(*typedArena[T])
+	if !ok {
+		a = &typedArena[T]{}
+		world.arenas[t] = a
+	}
+
+	return a.alloc(n)
+}
+
+// resetArenas resets the frame arenas at the beginning of a frame.
+func (ecs *ECS) resetArenas() {
+	for _, a := range ecs.arenas {
+		a.reset()
+	}
+}
@@ -0,0 +1,25 @@
+package ecs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFrameArenaResetWhilePaused(t *testing.T) {
+	world := New(WithDeterministic(1))
+	world.Pause()
+
+	for frame := 0; frame < 100; frame++ {
+		if err := world.Update(); err != nil {
+			t.Fatal(err)
+		}
+
+		// a drawer allocating its render commands, while no step runs
+		FrameAlloc[int](world, 100)
+	}
+
+	a := world.arenas[reflect.TypeOf((*int)(nil))].(*typedArena[int])
+	if len(a.buf) > 128 {
+		t.Errorf("the arena grew to %d values while 100 values are allocated per frame", len(a.buf))
+	}
+}
@@ -0,0 +1,161 @@
+// Package attach provides an Attach component and the system making an entity follow another one,
+// e.g. a health bar above a head or a weapon in a hand, detaching or despawning it when the target dies.
+package attach
+
+import (
+	"math"
+
+	ecs "github.com/jtbonhomme/ebiten-ecs"
+	"github.com/jtbonhomme/ebiten-ecs/component"
+	"github.com/jtbonhomme/ebiten-ecs/entity"
+	"github.com/jtbonhomme/ebiten-ecs/health"
+	"github.com/jtbonhomme/ebiten-ecs/physics"
+	"github.com/jtbonhomme/ebiten-ecs/sprite"
+	"github.com/jtbonhomme/ebiten-ecs/system"
+)
+
+// Policy is what happens to an attached entity when its target dies.
+type Policy int
+
+// Policies of the attached entities: a target dies when it is unregistered, or when its health.Health is dead.
+const (
+	// Detach leaves the entity where it is, no longer following its target.
+	Detach Policy = iota
+	// Despawn unregisters the entity at the beginning of the next update.
+	Despawn
+)
+
+// Attach is the component of the entities following a target entity.
+type Attach struct {
+	Target entity.ID
+	// OffsetX and OffsetY are the position of the entity relative to its target.
+	OffsetX float64
+	OffsetY float64
+	// InheritRotation rotates the offset with the target, and gives the entity the rotation of its target.
+	InheritRotation bool
+	OnTargetDeath   Policy
+	detached        bool
+}
+
+// Detached returns true once the target of the entity has died and the entity stopped following it.
+func (a *Attach) Detached() bool {
+	return a.detached
+}
+
+// Reattach makes the entity follow a new target.
+func (a *Attach) Reattach(target entity.ID) {
+	a.Target = target
+	a.detached = false
+}
+
+// GetFunc returns the position and rotation of an entity, and false if it has none.
+type GetFunc func(c []component.Component) (x, y, rotation float64, ok bool)
+
+// SetFunc sets the position and rotation of an entity.
+type SetFunc func(c []component.Component, x, y, rotation float64)
+
+var (
+	attachType = component.NewType[Attach]()
+	healthType = component.NewType[health.Health]()
+	bodyType   = component.NewType[physics.RigidBody]()
+	spriteType = component.NewType[sprite.Sprite]()
+)
+
+// DefaultGet returns the position and angle of the rigid body of an entity, or the position and rotation of its sprite.
+func DefaultGet(c []component.Component) (float64, float64, float64, bool) {
+	if b := bodyType.Get(c); b != nil {
+		return b.X, b.Y, b.Angle, true
+	}
+
+	if s := spriteType.Get(c); s != nil {
+		return s.X, s.Y, s.Rotation, true
+	}
+
+	return 0, 0, 0, false
+}
+
+// DefaultSet sets the position and angle of the rigid body of an entity, waking it, and the position and rotation of its sprite.
+func DefaultSet(c []component.Component, x, y, rotation float64) {
+	if b := bodyType.Get(c); b != nil {
+		b.X, b.Y, b.Angle = x, y, rotation
+		b.Wake()
+	}
+
+	if s := spriteType.Get(c); s != nil {
+		s.X, s.Y, s.Rotation = x, y, rotation
+	}
+}
+
+// System is the updater moving the attached entities to their targets. It is meant to run after the systems
+// moving the targets, e.g. after the physics system, so that attached entities do not lag one step behind.
+type System struct {
+	id    system.ID
+	world *ecs.ECS
+	// Get and Set access the position and rotation of the entities, DefaultGet and DefaultSet if nil.
+	Get GetFunc
+	Set SetFunc
+}
+
+// NewSystem creates the updater of the attached entities of the world.
+func NewSystem(world *ecs.ECS) *System {
+	return &System{
+		id:    system.AssignID(),
+		world: world,
+	}
+}
+
+// ID returns the unique ID of the system.
+func (s *System) ID() system.ID {
+	return s.id
+}
+
+// Update moves the entity to its target, or detaches or despawns it if its target died.
+func (s *System) Update(id entity.ID, c []component.Component, r map[entity.ID][]component.Component) error {
+	a := attachType.Get(c)
+	if a == nil || a.detached {
+		return nil
+	}
+
+	target, ok := r[a.Target]
+	if h := healthType.Get(target); !ok || (h != nil && h.Dead()) {
+		s.lose(id, a)
+		return nil
+	}
+
+	get, set := s.Get, s.Set
+	if get == nil {
+		get = DefaultGet
+	}
+
+	if set == nil {
+		set = DefaultSet
+	}
+
+	x, y, rotation, ok := get(target)
+	if !ok {
+		return nil
+	}
+
+	dx, dy := a.OffsetX, a.OffsetY
+	if !a.InheritRotation {
+		_, _, rotation, _ = get(c)
+	} else if rotation != 0 {
+		sin, cos := math.Sincos(rotation)
+		dx, dy = dx*cos-dy*sin, dx*sin+dy*cos
+	}
+
+	set(c, x+dx, y+dy, rotation)
+
+	return nil
+}
+
+// lose applies the policy of the entity once its target died.
+func (s *System) lose(id entity.ID, a *Attach) {
+	a.detached = true
+
+	if a.OnTargetDeath == Despawn {
+		s.world.Defer(func(world *ecs.ECS) {
+			world.UnregisterEntity(id)
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package ecs
+
+import (
+	"fmt"
+
+	"github.com/jtbonhomme/ebiten-ecs/component"
+	"github.com/jtbonhomme/ebiten-ecs/entity"
+	"github.com/jtbonhomme/ebiten-ecs/system"
+)
+
+// RegisterEntities registers entities and their components in one pass: entities[i] gets components[i].
+// The slices of components are kept by the world, and MUST NOT be reused by the caller.
+// The method panics if the two slices do not have the same length, or for the reasons RegisterEntity does.
+func (ecs *ECS) RegisterEntities(entities []entity.Entity, components [][]component.Component) {
+	if len(entities) != len(components) {
+		panic(fmt.Sprintf("%d entities MUST be registered with %d slices of components, got %d", len(entities), len(entities), len(components)))
+	}
+
+	added := 0
+	for _, e := range entities {
+		if _, ok := ecs.componentsRegistry[e.ID()]; !ok {
+			added++
+		}
+	}
+
+	ecs.reserveEntities(added)
+	ecs.structure++
+
+	for i, e := range entities {
+		ecs.scope(e)
+
+		existing, ok := ecs.componentsRegistry[e.ID()]
+		checkComponents(e.ID(), existing, components[i])
+		ecs.markRegistered(e.ID(), components[i])
+
+		if ok {
+			ecs.componentsRegistry[e.ID()] = append(existing, components[i]...)
+			ecs.count(components[i], 1)
+
+			if ecs.analyzer != nil {
+				ecs.analyzer.componentsAdded(len(components[i]))
+			}
+
+			continue
+		}
+
+		// counted once registered, for the peak of entities to include it
+		ecs.componentsRegistry[e.ID()] = components[i]
+		ecs.count(components[i], 1)
+		ecs.spawned(e.ID())
+	}
+}
+
+// SpawnBatch creates n entities from the prefab, e.g. the tiles of a level, and associates them with the given systems.
+// The IDs of the entities are assigned in one pass, and the storage of the systems is grown once,
+// which avoids the hitch of creating thousands of entities one by one.
+func (ecs *ECS) SpawnBatch(n int, prefab Prefab, systems ...system.System) []entity.Entity {
+	entities := entity.NewBatch(n)
+
+	components := make([][]component.Component, len(entities))
+	for i := range components {
+		components[i] = prefab()
+	}
+
+	ecs.RegisterEntities(entities, components)
+
+	for _, s := range systems {
+		ecs.Associate(s, entities...)
+	}
+
+	return entities
+}
@@ -0,0 +1,16 @@
+package ecs_test
+
+import (
+	"testing"
+
+	ecs "github.com/jtbonhomme/ebiten-ecs"
+)
+
+func TestSpawnBatchPeakEntities(t *testing.T) {
+	world := ecs.New()
+	world.SpawnBatch(10, newPosition)
+
+	if s := world.Stats(); s.Entities != 10 || s.PeakEntities != 10 {
+		t.Errorf("entities = %d, peak = %d, want 10 and 10", s.Entities, s.PeakEntities)
+	}
+}
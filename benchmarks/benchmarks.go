@@ -0,0 +1,259 @@
+// Package benchmarks compares the ways of storing and accessing components on typical workloads
+// (iteration, random access, adding and removing entities), so that users measure, on their own hardware
+// and with their own entity counts, which storage fits their game:
+//
+//	results := benchmarks.Run(10000)
+//	benchmarks.Print(os.Stdout, results)
+//
+// The backends are the world registry accessed per entity, the world registry iterated with a typed query,
+// and the struct-of-arrays stores of ecs.NewStore. The world has no archetype storage to compare with.
+package benchmarks
+
+import (
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"testing"
+
+	ecs "github.com/jtbonhomme/ebiten-ecs"
+	"github.com/jtbonhomme/ebiten-ecs/component"
+	"github.com/jtbonhomme/ebiten-ecs/entity"
+)
+
+// Position is the position component of the benchmarked entities.
+type Position struct {
+	X, Y float64
+}
+
+// Velocity is the velocity component of the benchmarked entities.
+type Velocity struct {
+	X, Y float64
+}
+
+var (
+	positionType = component.NewType[Position]()
+	velocityType = component.NewType[Velocity]()
+)
+
+// Backends.
+const (
+	// Registry stores the components in the world registry, one slice of components per entity,
+	// accessed with EntityComponents.
+	Registry = "registry"
+	// Query stores the components in the world registry, iterated with a typed query.
+	Query = "query"
+	// Store stores the components in one struct-of-arrays store per component type.
+	Store = "store"
+)
+
+// Workloads.
+const (
+	// Iterate moves all the entities once: Position += Velocity.
+	Iterate = "iterate"
+	// RandomAccess reads the position of entities picked at random.
+	RandomAccess = "random access"
+	// AddRemove adds an entity with a position and a velocity, then removes it.
+	AddRemove = "add/remove"
+)
+
+// Result is the measure of a workload on a backend.
+type Result struct {
+	Backend  string
+	Workload string
+	Entities int
+	testing.BenchmarkResult
+}
+
+// Benchmark is a workload on a backend, for a number of entities.
+type Benchmark struct {
+	Backend  string
+	Workload string
+	Fn       func(b *testing.B, entities int)
+}
+
+// Benchmarks returns all the benchmarks, grouped by workload.
+func Benchmarks() []Benchmark {
+	return []Benchmark{
+		{Registry, Iterate, registryIterate},
+		{Query, Iterate, queryIterate},
+		{Store, Iterate, storeIterate},
+		{Registry, RandomAccess, registryRandomAccess},
+		{Store, RandomAccess, storeRandomAccess},
+		{Registry, AddRemove, registryAddRemove},
+		{Store, AddRemove, storeAddRemove},
+	}
+}
+
+// Run runs all the benchmarks with the given number of entities.
+func Run(entities int) []Result {
+	results := []Result{}
+
+	for _, bench := range Benchmarks() {
+		result := testing.Benchmark(func(b *testing.B) {
+			bench.Fn(b, entities)
+		})
+
+		results = append(results, Result{
+			Backend:         bench.Backend,
+			Workload:        bench.Workload,
+			Entities:        entities,
+			BenchmarkResult: result,
+		})
+	}
+
+	return results
+}
+
+// Print writes the results as a table.
+func Print(w io.Writer, results []Result) {
+	for _, r := range results {
+		fmt.Fprintf(w, "%-14s %-10s %8d entities %s\t%s\n", r.Workload, r.Backend, r.Entities, r.BenchmarkResult, r.MemString())
+	}
+}
+
+// newWorld creates a world with the given number of moving entities.
+func newWorld(entities int) (*ecs.ECS, []entity.ID) {
+	world := ecs.New(ecs.WithMaxEntities(entities))
+	ids := make([]entity.ID, 0, entities)
+
+	for i := 0; i < entities; i++ {
+		ids = append(ids, world.Spawn(&Position{}, &Velocity{X: 1, Y: 1}).ID())
+	}
+
+	return world, ids
+}
+
+// newStores creates stores with the given number of moving entities.
+func newStores(entities int) (*ecs.Store[Position], *ecs.Store[Velocity], []entity.ID) {
+	world := ecs.New(ecs.WithMaxEntities(entities))
+	positions := ecs.NewStore[Position](world)
+	velocities := ecs.NewStore[Velocity](world)
+	ids := make([]entity.ID, 0, entities)
+
+	for i := 0; i < entities; i++ {
+		e := entity.New()
+		positions.Add(e.ID(), Position{})
+		velocities.Add(e.ID(), Velocity{X: 1, Y: 1})
+		ids = append(ids, e.ID())
+	}
+
+	return positions, velocities, ids
+}
+
+// picks returns the IDs of the entities read by the random access workload, in a reproducible order.
+func picks(ids []entity.ID) []entity.ID {
+	rnd := rand.New(rand.NewPCG(1, 2))
+	picked := make([]entity.ID, len(ids))
+
+	for i := range picked {
+		picked[i] = ids[rnd.IntN(len(ids))]
+	}
+
+	return picked
+}
+
+func registryIterate(b *testing.B, entities int) {
+	world, ids := newWorld(entities)
+
+	b.ResetTimer()
+
+	for n := 0; n < b.N; n++ {
+		for _, id := range ids {
+			c := world.EntityComponents(id)
+			p, v := positionType.Get(c), velocityType.Get(c)
+			p.X += v.X
+			p.Y += v.Y
+		}
+	}
+}
+
+func queryIterate(b *testing.B, entities int) {
+	world, _ := newWorld(entities)
+	q := ecs.NewQuery2[Position, Velocity](world)
+
+	move := func(_ entity.ID, p *Position, v *Velocity) {
+		p.X += v.X
+		p.Y += v.Y
+	}
+
+	b.ResetTimer()
+
+	for n := 0; n < b.N; n++ {
+		q.Each(move)
+	}
+}
+
+func storeIterate(b *testing.B, entities int) {
+	positions, velocities, _ := newStores(entities)
+
+	b.ResetTimer()
+
+	// both stores were filled in the same order and never removed from,
+	// so values at the same index belong to the same entity
+	for n := 0; n < b.N; n++ {
+		p, v := positions.Values(), velocities.Values()
+		for i := range p {
+			p[i].X += v[i].X
+			p[i].Y += v[i].Y
+		}
+	}
+}
+
+func registryRandomAccess(b *testing.B, entities int) {
+	world, ids := newWorld(entities)
+	picked := picks(ids)
+	sum := 0.0
+
+	b.ResetTimer()
+
+	for n := 0; n < b.N; n++ {
+		for _, id := range picked {
+			sum += positionType.Get(world.EntityComponents(id)).X
+		}
+	}
+
+	_ = sum
+}
+
+func storeRandomAccess(b *testing.B, entities int) {
+	positions, _, ids := newStores(entities)
+	picked := picks(ids)
+	sum := 0.0
+
+	b.ResetTimer()
+
+	for n := 0; n < b.N; n++ {
+		for _, id := range picked {
+			sum += positions.Get(id).X
+		}
+	}
+
+	_ = sum
+}
+
+func registryAddRemove(b *testing.B, entities int) {
+	world, _ := newWorld(entities)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for n := 0; n < b.N; n++ {
+		e := world.Spawn(&Position{}, &Velocity{X: 1, Y: 1})
+		world.UnregisterEntity(e.ID())
+	}
+}
+
+func storeAddRemove(b *testing.B, entities int) {
+	positions, velocities, _ := newStores(entities)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for n := 0; n < b.N; n++ {
+		e := entity.New()
+		positions.Add(e.ID(), Position{})
+		velocities.Add(e.ID(), Velocity{X: 1, Y: 1})
+		positions.Remove(e.ID())
+		velocities.Remove(e.ID())
+	}
+}
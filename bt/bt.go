@@ -0,0 +1,78 @@
+// Package bt provides behavior trees for AI: composite nodes (Sequence, Selector), decorators
+// (Inverter, Succeeder, Repeat) and leaf actions operating on the components of the entity.
+// A tree is built once and shared by every entity using it, while each entity holds the state of
+// the running nodes in its own Agent component, ticked by the System at each update.
+package bt
+
+import (
+	"github.com/jtbonhomme/ebiten-ecs/component"
+	"github.com/jtbonhomme/ebiten-ecs/entity"
+)
+
+// Status is the result of ticking a node.
+type Status int
+
+// Results of ticking a node.
+const (
+	// Running means the node has not finished yet, and is ticked again at the next update.
+	Running Status = iota
+	// Success means the node has finished successfully.
+	Success
+	// Failure means the node has failed.
+	Failure
+)
+
+// String returns the name of the status.
+func (s Status) String() string {
+	switch s {
+	case Running:
+		return "running"
+	case Success:
+		return "success"
+	case Failure:
+		return "failure"
+	default:
+		return "unknown"
+	}
+}
+
+// Context is what a node operates on when it is ticked: the entity, its components and its agent.
+type Context struct {
+	ID         entity.ID
+	Components []component.Component
+	Registry   map[entity.ID][]component.Component
+	Agent      *Agent
+}
+
+// Node is a node of a behavior tree.
+type Node interface {
+	Tick(ctx *Context) Status
+}
+
+// Agent is the component of the entities driven by a behavior tree.
+// It holds the state of the running nodes, and a blackboard for the data shared by the nodes.
+type Agent struct {
+	root       Node
+	running    map[Node]int
+	status     Status
+	Blackboard map[string]interface{}
+}
+
+// NewAgent creates an agent driven by the tree with the given root.
+func NewAgent(root Node) *Agent {
+	return &Agent{
+		root:       root,
+		running:    make(map[Node]int),
+		Blackboard: make(map[string]interface{}),
+	}
+}
+
+// Status returns the status of the tree at the last tick.
+func (a *Agent) Status() Status {
+	return a.status
+}
+
+// Reset forgets the running nodes, so that the tree starts again from its root at the next tick.
+func (a *Agent) Reset() {
+	clear(a.running)
+}
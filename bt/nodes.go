@@ -0,0 +1,160 @@
+package bt
+
+// sequence ticks its children in order while they succeed.
+type sequence struct {
+	children []Node
+}
+
+// Sequence returns a node ticking its children in order: it fails as soon as a child fails,
+// and succeeds when every child has succeeded. A running child is resumed at the next tick.
+func Sequence(children ...Node) Node {
+	return &sequence{children: children}
+}
+
+// Tick ticks the children of the sequence.
+func (n *sequence) Tick(ctx *Context) Status {
+	return tickChildren(n, n.children, ctx, Success)
+}
+
+// selector ticks its children in order until one succeeds.
+type selector struct {
+	children []Node
+}
+
+// Selector returns a node ticking its children in order: it succeeds as soon as a child succeeds,
+// and fails when every child has failed. A running child is resumed at the next tick.
+func Selector(children ...Node) Node {
+	return &selector{children: children}
+}
+
+// Tick ticks the children of the selector.
+func (n *selector) Tick(ctx *Context) Status {
+	return tickChildren(n, n.children, ctx, Failure)
+}
+
+// tickChildren ticks the children of a composite node from the running one, as long as they return
+// the status carrying on to the next child, which is also the status of the node once all children have run.
+func tickChildren(n Node, children []Node, ctx *Context, next Status) Status {
+	for i := ctx.Agent.running[n]; i < len(children); i++ {
+		status := children[i].Tick(ctx)
+
+		if status == Running {
+			ctx.Agent.running[n] = i
+			return Running
+		}
+
+		if status != next {
+			delete(ctx.Agent.running, n)
+			return status
+		}
+	}
+
+	delete(ctx.Agent.running, n)
+
+	return next
+}
+
+// inverter turns the success of its child into a failure, and conversely.
+type inverter struct {
+	child Node
+}
+
+// Inverter returns a decorator turning the success of its child into a failure, and conversely.
+func Inverter(child Node) Node {
+	return &inverter{child: child}
+}
+
+// Tick ticks the child of the inverter.
+func (n *inverter) Tick(ctx *Context) Status {
+	switch n.child.Tick(ctx) {
+	case Success:
+		return Failure
+	case Failure:
+		return Success
+	default:
+		return Running
+	}
+}
+
+// succeeder succeeds whatever its child returns, once it has finished.
+type succeeder struct {
+	child Node
+}
+
+// Succeeder returns a decorator succeeding whatever its child returns, once it has finished.
+func Succeeder(child Node) Node {
+	return &succeeder{child: child}
+}
+
+// Tick ticks the child of the succeeder.
+func (n *succeeder) Tick(ctx *Context) Status {
+	if n.child.Tick(ctx) == Running {
+		return Running
+	}
+
+	return Success
+}
+
+// repeat ticks its child a number of times.
+type repeat struct {
+	times int
+	child Node
+}
+
+// Repeat returns a decorator ticking its child until it has succeeded the given number of times,
+// one tick of the child per update, or forever if times is 0. It fails as soon as its child fails.
+func Repeat(times int, child Node) Node {
+	return &repeat{times: times, child: child}
+}
+
+// Tick ticks the child of the repeater.
+func (n *repeat) Tick(ctx *Context) Status {
+	switch n.child.Tick(ctx) {
+	case Running:
+		return Running
+	case Failure:
+		delete(ctx.Agent.running, n)
+		return Failure
+	}
+
+	if n.times <= 0 {
+		return Running
+	}
+
+	done := ctx.Agent.running[n] + 1
+	if done < n.times {
+		ctx.Agent.running[n] = done
+		return Running
+	}
+
+	delete(ctx.Agent.running, n)
+
+	return Success
+}
+
+// Action is a leaf node running a function on the entity.
+type Action func(ctx *Context) Status
+
+// Tick runs the action.
+func (a Action) Tick(ctx *Context) Status {
+	return a(ctx)
+}
+
+// condition is a leaf node checking a predicate on the entity.
+type condition struct {
+	predicate func(ctx *Context) bool
+}
+
+// Condition returns a leaf node succeeding if the predicate is true, and failing otherwise.
+func Condition(predicate func(ctx *Context) bool) Node {
+	return &condition{predicate: predicate}
+}
+
+// Tick checks the predicate of the condition.
+func (n *condition) Tick(ctx *Context) Status {
+	if n.predicate(ctx) {
+		return Success
+	}
+
+	return Failure
+}
@@ -0,0 +1,49 @@
+package bt
+
+import (
+	"github.com/jtbonhomme/ebiten-ecs/component"
+	"github.com/jtbonhomme/ebiten-ecs/entity"
+	"github.com/jtbonhomme/ebiten-ecs/system"
+)
+
+// agentType is the handle used to find the Agent component.
+var agentType = component.NewType[Agent]()
+
+// System is the updater ticking the behavior tree of the Agent component of its entities, once per update.
+// A tree which has finished starts again from its root at the next update.
+type System struct {
+	id  system.ID
+	ctx Context
+}
+
+// NewSystem creates the updater ticking the behavior trees of its entities.
+func NewSystem() *System {
+	return &System{
+		id: system.AssignID(),
+	}
+}
+
+// ID returns the unique ID of the system.
+func (s *System) ID() system.ID {
+	return s.id
+}
+
+// Update ticks the behavior tree of the entity, if it has an agent.
+func (s *System) Update(id entity.ID, c []component.Component, r map[entity.ID][]component.Component) error {
+	a := agentType.Get(c)
+	if a == nil {
+		return nil
+	}
+
+	s.ctx = Context{
+		ID:         id,
+		Components: c,
+		Registry:   r,
+		Agent:      a,
+	}
+
+	a.status = a.root.Tick(&s.ctx)
+	s.ctx = Context{}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package ecs
+
+import (
+	"fmt"
+
+	"github.com/jtbonhomme/ebiten-ecs/entity"
+	"github.com/jtbonhomme/ebiten-ecs/system"
+)
+
+// EntityBuilder builds an entity step by step, and registers it in the world with Build:
+//
+//	world.NewEntity().With(&Position{}).With(&Sprite{}).Tag("enemy").Build()
+type EntityBuilder struct {
+	world   *ECS
+	data    []interface{}
+	tags    []string
+	name    string
+	systems []system.System
+}
+
+// NewEntity starts building a new entity.
+func (ecs *ECS) NewEntity() *EntityBuilder {
+	return &EntityBuilder{
+		world: ecs,
+	}
+}
+
+// With adds components to the entity. Each data argument MUST be a pointer to the component struct.
+func (b *EntityBuilder) With(data ...interface{}) *EntityBuilder {
+	b.data = append(b.data, data...)
+
+	return b
+}
+
+// Tag adds tags to the entity.
+func (b *EntityBuilder) Tag(tags ...string) *EntityBuilder {
+	b.tags = append(b.tags, tags...)
+	return b
+}
+
+// Name gives a unique name to the entity.
+func (b *EntityBuilder) Name(name string) *EntityBuilder {
+	b.name = name
+	return b
+}
+
+// Systems associates the entity with already registered updaters or drawers.
+func (b *EntityBuilder) Systems(s ...system.System) *EntityBuilder {
+	b.systems = append(b.systems, s...)
+	return b
+}
+
+// Build creates the entity and registers it in the world with its components, tags, name and systems.
+// The method panics if a component is not a pointer, or if the name is already used by another entity,
+// in which case no entity is registered.
+func (b *EntityBuilder) Build() entity.Entity {
+	if named, ok := b.world.namedEntities[b.name]; ok && b.name != "" {
+		panic(fmt.Sprintf("the name %q is already used by entity %s", b.name, named.ID()))
+	}
+
+	e := entity.New()
+
+	b.world.RegisterEntity(e, b.data...)
+	b.world.Tag(e, b.tags...)
+
+	if b.name != "" {
+		// the name was checked above, it cannot fail
+		_ = b.world.SetName(e, b.name)
+	}
+
+	for _, s := range b.systems {
+		b.world.Associate(s, e)
+	}
+
+	return e
+}
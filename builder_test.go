@@ -0,0 +1,41 @@
+package ecs_test
+
+import (
+	"testing"
+
+	ecs "github.com/jtbonhomme/ebiten-ecs"
+	"github.com/jtbonhomme/ebiten-ecs/component"
+	"github.com/jtbonhomme/ebiten-ecs/entity"
+)
+
+func TestBuildDuplicateNameRegistersNothing(t *testing.T) {
+	world := ecs.New()
+	world.NewEntity().With(&position{}).Tag("enemy").Name("boss").Build()
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("Build did not panic for a duplicate name")
+			}
+		}()
+
+		world.NewEntity().With(&position{}).Tag("enemy").Name("boss").Build()
+	}()
+
+	if n := len(world.EntitiesWithTag("enemy")); n != 1 {
+		t.Errorf("%d entities are tagged, want 1", n)
+	}
+}
+
+func TestRegisterEntityWithPlainPointers(t *testing.T) {
+	world := ecs.New()
+	p := &position{}
+	e := entity.New()
+
+	world.RegisterEntity(e, p, component.New(&velocity{}))
+
+	c := world.EntityComponents(e.ID())
+	if len(c) != 2 || c[0].Data() != p || c[1].TypeID() != component.TypeIDOf(&velocity{}) {
+		t.Errorf("components = %v, want the position and the velocity", c)
+	}
+}
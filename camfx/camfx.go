@@ -0,0 +1,242 @@
+// Package camfx provides camera effects: smooth follow with a deadzone, screen shake with trauma decay,
+// zoom punch and screen flash. The effects are components of a camera entity, combined each step
+// by the System into the camera of the world.
+package camfx
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+
+	"github.com/jtbonhomme/ebiten-ecs/component"
+	"github.com/jtbonhomme/ebiten-ecs/ebitenecs"
+	"github.com/jtbonhomme/ebiten-ecs/entity"
+	"github.com/jtbonhomme/ebiten-ecs/system"
+)
+
+// Rig is the component holding the camera before the effects are applied: the shake and the zoom punch
+// are added to it each step to compute the camera of the world, so that they never drift it.
+type Rig struct {
+	X        float64
+	Y        float64
+	Zoom     float64
+	Rotation float64
+}
+
+// Follow is the component making the camera follow a target entity. The camera only moves when the target
+// leaves the deadzone, a box centered on the camera, and then catches up by Smoothing (between 0 and 1,
+// 1 meaning instantly) of the distance each step.
+type Follow struct {
+	Target         entity.ID
+	DeadzoneWidth  float64
+	DeadzoneHeight float64
+	Smoothing      float64
+}
+
+// Shake is the component shaking the camera. Trauma, between 0 and 1, is added by events such as explosions
+// and decays each step; the shake grows with the square of the trauma.
+type Shake struct {
+	Trauma float64
+	// Decay is the trauma lost per step.
+	Decay float64
+	// MaxOffset is the offset of the camera at full trauma, in pixels, and MaxAngle its rotation, in radians.
+	MaxOffset float64
+	MaxAngle  float64
+}
+
+// AddTrauma adds trauma to the shake, up to 1.
+func (s *Shake) AddTrauma(trauma float64) {
+	s.Trauma = math.Min(1, s.Trauma+trauma)
+}
+
+// ZoomPunch is the component briefly zooming the camera in, e.g. on a hit.
+type ZoomPunch struct {
+	amount   float64
+	duration int
+	elapsed  int
+}
+
+// Punch zooms the camera in by the given amount (0.1 for 10%), returning to normal over the given number of steps.
+func (z *ZoomPunch) Punch(amount float64, steps int) {
+	z.amount, z.duration, z.elapsed = amount, steps, 0
+}
+
+// zoom returns the current zoom factor of the punch.
+func (z *ZoomPunch) zoom() float64 {
+	if z.elapsed >= z.duration {
+		return 1
+	}
+
+	return 1 + z.amount*(1-float64(z.elapsed)/float64(z.duration))
+}
+
+// Flash is the component flashing the screen with a color, fading out, drawn by the FlashDrawer.
+type Flash struct {
+	color    color.Color
+	duration int
+	elapsed  int
+}
+
+// Start flashes the screen with the color, fading out over the given number of steps.
+func (f *Flash) Start(clr color.Color, steps int) {
+	f.color, f.duration, f.elapsed = clr, steps, 0
+}
+
+// alpha returns the opacity of the flash.
+func (f *Flash) alpha() float32 {
+	if f.color == nil || f.elapsed >= f.duration {
+		return 0
+	}
+
+	return 1 - float32(f.elapsed)/float32(f.duration)
+}
+
+// PositionFunc returns the position of an entity in the world, and false if it has none.
+type PositionFunc func(c []component.Component) (float64, float64, bool)
+
+var (
+	rigType    = component.NewType[Rig]()
+	followType = component.NewType[Follow]()
+	shakeType  = component.NewType[Shake]()
+	punchType  = component.NewType[ZoomPunch]()
+	flashType  = component.NewType[Flash]()
+)
+
+// System is the updater applying the effects of the camera entity to the camera of the world.
+// It uses the random number generator of the world, so that shakes are replayed identically.
+type System struct {
+	id       system.ID
+	world    *ebitenecs.World
+	position PositionFunc
+}
+
+// NewSystem creates the updater driving the camera of the world, locating the followed entities with position.
+func NewSystem(world *ebitenecs.World, position PositionFunc) *System {
+	return &System{
+		id:       system.AssignID(),
+		world:    world,
+		position: position,
+	}
+}
+
+// ID returns the unique ID of the system.
+func (s *System) ID() system.ID {
+	return s.id
+}
+
+// Update moves the rig of the camera entity, advances its effects and sets the camera of the world.
+func (s *System) Update(_ entity.ID, c []component.Component, r map[entity.ID][]component.Component) error {
+	rig := rigType.Get(c)
+	if rig == nil {
+		return nil
+	}
+
+	if f := followType.Get(c); f != nil && s.position != nil {
+		s.follow(rig, f, r[f.Target])
+	}
+
+	camera := s.world.Camera()
+	camera.X, camera.Y, camera.Zoom, camera.Rotation = rig.X, rig.Y, rig.Zoom, rig.Rotation
+
+	if camera.Zoom == 0 {
+		camera.Zoom = 1
+	}
+
+	if sh := shakeType.Get(c); sh != nil && sh.Trauma > 0 {
+		shake := sh.Trauma * sh.Trauma
+		rnd := s.world.SystemRand(s)
+
+		camera.X += sh.MaxOffset * shake * (2*rnd.Float64() - 1)
+		camera.Y += sh.MaxOffset * shake * (2*rnd.Float64() - 1)
+		camera.Rotation += sh.MaxAngle * shake * (2*rnd.Float64() - 1)
+		sh.Trauma = math.Max(0, sh.Trauma-sh.Decay)
+	}
+
+	if z := punchType.Get(c); z != nil {
+		camera.Zoom *= z.zoom()
+		z.elapsed++
+	}
+
+	if f := flashType.Get(c); f != nil {
+		f.elapsed++
+	}
+
+	return nil
+}
+
+// follow moves the rig toward the target when it is out of the deadzone.
+func (s *System) follow(rig *Rig, f *Follow, target []component.Component) {
+	x, y, ok := s.position(target)
+	if !ok {
+		return
+	}
+
+	smoothing := f.Smoothing
+	if smoothing <= 0 || smoothing > 1 {
+		smoothing = 1
+	}
+
+	rig.X += smoothing * outside(x-rig.X, f.DeadzoneWidth/2)
+	rig.Y += smoothing * outside(y-rig.Y, f.DeadzoneHeight/2)
+}
+
+// outside returns how far d is beyond the half size of the deadzone, 0 if it is inside.
+func outside(d, half float64) float64 {
+	switch {
+	case d > half:
+		return d - half
+	case d < -half:
+		return d + half
+	default:
+		return 0
+	}
+}
+
+// FlashDrawer draws the flash of the camera entity over the screen. It is meant to be registered in the screen pass.
+type FlashDrawer struct {
+	id system.ID
+}
+
+// NewFlashDrawer creates the drawer of the flash.
+func NewFlashDrawer() *FlashDrawer {
+	return &FlashDrawer{
+		id: system.AssignID(),
+	}
+}
+
+// ID returns the unique ID of the drawer.
+func (d *FlashDrawer) ID() system.ID {
+	return d.id
+}
+
+// Draw draws the flash of the entity, if one is running.
+func (d *FlashDrawer) Draw(screen *ebiten.Image, c []component.Component) {
+	f := flashType.Get(c)
+	if f == nil {
+		return
+	}
+
+	alpha := f.alpha()
+	if alpha <= 0 {
+		return
+	}
+
+	var cs ebiten.ColorScale
+	cs.ScaleWithColor(f.color)
+	cs.ScaleAlpha(alpha)
+
+	b := screen.Bounds()
+	vector.DrawFilledRect(screen, float32(b.Min.X), float32(b.Min.Y), float32(b.Dx()), float32(b.Dy()), colorOf(cs), false)
+}
+
+// colorOf converts a color scale of a white color to a premultiplied color.
+func colorOf(cs ebiten.ColorScale) color.Color {
+	return color.RGBA64{
+		R: uint16(cs.R() * 0xffff),
+		G: uint16(cs.G() * 0xffff),
+		B: uint16(cs.B() * 0xffff),
+		A: uint16(cs.A() * 0xffff),
+	}
+}
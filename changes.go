@@ -0,0 +1,79 @@
+package ecs
+
+import (
+	"slices"
+
+	"github.com/jtbonhomme/ebiten-ecs/component"
+	"github.com/jtbonhomme/ebiten-ecs/entity"
+)
+
+// TrackChanges enables change detection for the given component types. Systems mutating a component
+// of a tracked type report it with MarkChanged, and other systems (network sync, render caches...) only process
+// the entities whose component changed since they last ran, with Changed or AppendChanged.
+// Registering an entity with a component of a tracked type counts as a change.
+func (ecs *ECS) TrackChanges(types ...component.TypeID) {
+	for _, t := range types {
+		if _, ok := ecs.changes[t]; !ok {
+			ecs.changes[t] = make(map[entity.ID]uint64)
+		}
+	}
+}
+
+// MarkChanged reports that components of the given types of an entity were mutated.
+// Types which are not tracked are ignored.
+func (ecs *ECS) MarkChanged(id entity.ID, types ...component.TypeID) {
+	for _, t := range types {
+		versions, ok := ecs.changes[t]
+		if !ok {
+			continue
+		}
+
+		ecs.changeVersion++
+		versions[id] = ecs.changeVersion
+	}
+}
+
+// ChangeVersion returns the version of the last change reported. A system stores it once it has processed
+// the changes, and passes it as the since argument of Changed and AppendChanged the next time it runs.
+func (ecs *ECS) ChangeVersion() uint64 {
+	return ecs.changeVersion
+}
+
+// Changed returns true if the component of the given type of an entity changed after the given version.
+func (ecs *ECS) Changed(id entity.ID, t component.TypeID, since uint64) bool {
+	return ecs.changes[t][id] > since
+}
+
+// AppendChanged appends to dst the IDs of the entities whose component of the given type changed
+// after the given version, by increasing ID, and returns the extended slice.
+func (ecs *ECS) AppendChanged(dst []entity.ID, t component.TypeID, since uint64) []entity.ID {
+	n := len(dst)
+
+	for id, version := range ecs.changes[t] {
+		if version > since {
+			dst = append(dst, id)
+		}
+	}
+
+	slices.Sort(dst[n:])
+
+	return dst
+}
+
+// markRegistered reports the components of the tracked types of a newly registered entity as changed.
+func (ecs *ECS) markRegistered(id entity.ID, components []component.Component) {
+	if len(ecs.changes) == 0 {
+		return
+	}
+
+	for _, c := range components {
+		ecs.MarkChanged(id, c.TypeID())
+	}
+}
+
+// forgetChanges removes an entity from the change detection.
+func (ecs *ECS) forgetChanges(id entity.ID) {
+	for _, versions := range ecs.changes {
+		delete(versions, id)
+	}
+}
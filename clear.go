@@ -0,0 +1,50 @@
+package ecs
+
+import (
+	"reflect"
+)
+
+// Clear despawns all the entities of the world, so that a level can be restarted without creating a new world
+// and registering every system again. The systems stay registered, but lose their associated entities.
+// The tags, names, groups (which stay usable, empty), stores, tracked changes and references are emptied as well.
+// If resetResources is true, all the resources are removed too, except the input state, the time and the random number generator of the world.
+// To despawn only part of the world, e.g. the entities of a level, use Group.Despawn.
+// The despawn hooks are called for every entity first.
+func (ecs *ECS) Clear(resetResources bool) {
+	ecs.despawnedAll()
+
+	clear(ecs.entitiesRegistry)
+	clear(ecs.componentsRegistry)
+	ecs.resetCounts()
+	ecs.structure++
+	clear(ecs.taggedEntities)
+	clear(ecs.sharedTags)
+	clear(ecs.entityTags)
+	clear(ecs.namedEntities)
+	clear(ecs.entityNames)
+	clear(ecs.inactiveEntities)
+	clear(ecs.refsTo)
+	clear(ecs.refsHeld)
+	clear(ecs.dying)
+	ecs.dyingOrder = ecs.dyingOrder[:0]
+
+	for _, g := range ecs.groups {
+		g.entities = nil
+		clear(g.members)
+	}
+
+	for _, versions := range ecs.changes {
+		clear(versions)
+	}
+
+	for _, s := range ecs.stores {
+		s.Clear()
+	}
+
+	if resetResources {
+		clear(ecs.resources)
+		ecs.resources[reflect.TypeOf(ecs.input)] = ecs.input
+		ecs.resources[reflect.TypeOf(ecs.time)] = ecs.time
+		ecs.resources[reflect.TypeOf(ecs.rand)] = ecs.rand
+	}
+}
@@ -0,0 +1,45 @@
+package ecs
+
+import (
+	"time"
+)
+
+// Time is the world resource holding the simulated time, maintained by the world at the beginning of each step
+// and readable by all the systems, so that slow motion and pause affect every time-dependent system consistently.
+type Time struct {
+	// Tick is the number of steps run since the world was created.
+	Tick uint64
+	// Delta is the simulated duration of the current step, scaled by Scale, and Elapsed the sum of the deltas.
+	Delta   time.Duration
+	Elapsed time.Duration
+	// UnscaledDelta and UnscaledElapsed ignore the time scale, e.g. for UI animations during slow motion.
+	UnscaledDelta   time.Duration
+	UnscaledElapsed time.Duration
+	// Scale is the factor applied to the duration of the steps: 0.5 for slow motion, 0 to freeze time.
+	// It is set by the game, and defaults to 1.
+	Scale float64
+	// Speed is the speed of the simulation relative to real time, see ECS.SetTimeScale.
+	Speed float64
+}
+
+// DeltaSeconds returns the scaled duration of the current step, in seconds.
+func (t *Time) DeltaSeconds() float64 {
+	return t.Delta.Seconds()
+}
+
+// Time returns the time resource of the world.
+func (ecs *ECS) Time() *Time {
+	return ecs.time
+}
+
+// updateTime updates the time resource at the beginning of a step.
+func (ecs *ECS) updateTime() {
+	t := ecs.time
+	scale := max(t.Scale, 0)
+
+	t.Tick = ecs.tick
+	t.UnscaledDelta = ecs.dt
+	t.UnscaledElapsed = ecs.elapsed
+	t.Delta = time.Duration(float64(ecs.dt) * scale)
+	t.Elapsed += t.Delta
+}
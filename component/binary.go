@@ -0,0 +1,74 @@
+package component
+
+import (
+	"encoding"
+	"fmt"
+)
+
+// BinaryCodec encodes and decodes the data of a component type in a compact binary form, e.g. with the marshalers
+// generated by protoc for a protobuf message mirroring the component, so that snapshots can be read by tools
+// written in other languages. Unmarshal decodes into data, a pointer to a zero value of the component type.
+type BinaryCodec struct {
+	Marshal   func(data interface{}) ([]byte, error)
+	Unmarshal func(b []byte, data interface{}) error
+}
+
+var binaryCodecs = make(map[string]BinaryCodec)
+
+// RegisterBinary registers the binary codec of a registered component type.
+// Types implementing encoding.BinaryMarshaler and encoding.BinaryUnmarshaler don't need a codec.
+// The function panics if no component type is registered with this name, or if it already has a codec.
+func RegisterBinary(name string, codec BinaryCodec) {
+	if _, ok := typesByName[name]; !ok {
+		panic(fmt.Sprintf("the component type %q you are trying to add a binary codec to is not registered", name))
+	}
+
+	if _, ok := binaryCodecs[name]; ok {
+		panic(fmt.Sprintf("the binary codec of component type %q is already registered", name))
+	}
+
+	binaryCodecs[name] = codec
+}
+
+// MarshalBinary encodes the data of a component with the binary codec of its type, and returns the name
+// of the type. It returns an error if the type is not registered or has no binary codec.
+func MarshalBinary(data interface{}) (string, []byte, error) {
+	name, ok := TypeName(data)
+	if !ok {
+		return "", nil, fmt.Errorf("unregistered component type %T", data)
+	}
+
+	if codec, ok := binaryCodecs[name]; ok {
+		b, err := codec.Marshal(data)
+		return name, b, err
+	}
+
+	m, ok := data.(encoding.BinaryMarshaler)
+	if !ok {
+		return name, nil, fmt.Errorf("component type %q has no binary codec", name)
+	}
+
+	b, err := m.MarshalBinary()
+
+	return name, b, err
+}
+
+// UnmarshalBinary decodes the data of a component of the type registered under the given name,
+// encoded with MarshalBinary. It returns an error if the type is not registered or has no binary codec.
+func UnmarshalBinary(name string, b []byte) (interface{}, error) {
+	data, err := NewData(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if codec, ok := binaryCodecs[name]; ok {
+		return data, codec.Unmarshal(b, data)
+	}
+
+	u, ok := data.(encoding.BinaryUnmarshaler)
+	if !ok {
+		return nil, fmt.Errorf("component type %q has no binary codec", name)
+	}
+
+	return data, u.UnmarshalBinary(b)
+}
@@ -4,32 +4,70 @@ package component
 import (
 	"fmt"
 	"reflect"
+	"sync/atomic"
 )
 
-// Component is an interface that represents a component in the ECS architecture.
-type Component interface {
-	Data() interface{}
-}
+var (
+	created atomic.Uint64
+	queried atomic.Uint64
+)
 
-type component struct {
-	data interface{}
+// Component is a component of an entity: its data, a pointer to a component struct, with the ID of its type,
+// used by the reflection-free lookups of Type.Get. It is a small value, held by the worlds in the slice of
+// components of each entity without any allocation of its own, so entities are usually created directly from
+// their data (see ecs.ECS.RegisterEntity). The zero Component has no data.
+type Component struct {
+	data   interface{}
+	typeID TypeID
 }
 
 // New creates a new component with the given data.
 func New(data interface{}) Component {
 	// todo: add a check to control data is a pointer to a struct
-	return &component{
-		data: data,
+	created.Add(1)
+
+	return Component{
+		data:   data,
+		typeID: TypeIDOf(data),
+	}
+}
+
+// NewSlice creates the components of the given data, pointers to component structs, in a single allocation.
+// Components already created with New are kept as they are.
+func NewSlice(data ...interface{}) []Component {
+	components := make([]Component, len(data))
+
+	for i, d := range data {
+		if c, ok := d.(Component); ok {
+			components[i] = c
+			continue
+		}
+
+		created.Add(1)
+		components[i] = Component{
+			data:   d,
+			typeID: TypeIDOf(d),
+		}
 	}
+
+	return components
 }
 
 // Data returns the data of the component.
-func (c *component) Data() interface{} {
+func (c Component) Data() interface{} {
 	return c.data
 }
 
+// TypeID returns the type ID of the component data.
+func (c Component) TypeID() TypeID {
+	return c.typeID
+}
+
 // QueryComponents is a function that queries components matching the given component types from the ECS architecture.
+// It relies on reflection for the requested components: in the hot path, prefer Type.Get which does not.
 func QueryComponents(c []Component, components ...interface{}) {
+	queried.Add(1)
+
 	for _, component := range components {
 		componentValue := reflect.ValueOf(component)
 		// the component to be assigned needs to be a reference to a concrete object
@@ -42,21 +80,19 @@ func QueryComponents(c []Component, components ...interface{}) {
 			panic(fmt.Sprintf("received entity component %s must be a pointer to pointer", componentValue.Type().Name()))
 		}
 
-		componentValueType := componentValueElem.Type()
+		componentTypeID := typeIDOf(componentValueElem.Type())
 
 		for _, rComponent := range c {
-			rComponentValue := reflect.ValueOf(rComponent.Data())
-
-			if rComponentValue.Kind() != reflect.Ptr {
-				panic(fmt.Sprintf("registered entity component %s must be a pointer", rComponentValue.Type().Name()))
-			}
-
-			rComponentValueType := rComponentValue.Type()
-
-			if rComponentValueType == componentValueType {
-				componentValueElem.Set(rComponentValue)
+			if rComponent.TypeID() == componentTypeID {
+				componentValueElem.Set(reflect.ValueOf(rComponent.Data()))
 				break
 			}
 		}
 	}
 }
+
+// Counters returns the number of components created with New and the number of calls to QueryComponents
+// since the program started. It is used by the world analyzer to detect slow usage patterns.
+func Counters() (uint64, uint64) {
+	return created.Load(), queried.Load()
+}
@@ -0,0 +1,39 @@
+package component_test
+
+import (
+	"testing"
+
+	"github.com/jtbonhomme/ebiten-ecs/component"
+)
+
+type position struct {
+	X float64
+}
+
+type velocity struct {
+	X float64
+}
+
+var positionType = component.NewType[position]()
+
+func TestComponentsDoNotAllocate(t *testing.T) {
+	p, v := &position{}, &velocity{}
+	component.NewSlice(p, v)
+
+	if n := testing.AllocsPerRun(100, func() { component.New(p) }); n != 0 {
+		t.Errorf("New allocates %v times, want 0", n)
+	}
+
+	if n := testing.AllocsPerRun(100, func() { component.NewSlice(p, v) }); n != 1 {
+		t.Errorf("NewSlice allocates %v times, want 1", n)
+	}
+}
+
+func TestNewSliceKeepsComponents(t *testing.T) {
+	p := &position{}
+	c := component.NewSlice(component.New(p), &velocity{})
+
+	if len(c) != 2 || positionType.Get(c) != p {
+		t.Errorf("the component created with New was not kept: %v", c)
+	}
+}
@@ -0,0 +1,86 @@
+package component
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Migration upgrades the fields of a serialized component from a version of its type to the next one,
+// e.g. renaming, converting or removing fields. Fields hold plain JSON values: numbers are float64.
+type Migration func(fields map[string]interface{}) error
+
+var (
+	versions   = make(map[string]int)
+	migrations = make(map[string]map[int]Migration)
+)
+
+// SetVersion sets the current version of a registered component type, which is 0 by default.
+// The version is bumped whenever the fields of the type change in a way old saves cannot be loaded with,
+// along with the registration of the migration from the previous version.
+// The function panics if no component type is registered with this name.
+func SetVersion(name string, version int) {
+	if _, ok := typesByName[name]; !ok {
+		panic(fmt.Sprintf("the component type %q you are trying to version is not registered", name))
+	}
+
+	versions[name] = version
+}
+
+// Version returns the current version of a registered component type.
+func Version(name string) int {
+	return versions[name]
+}
+
+// RegisterMigration registers the migration of a component type from a version to the next one.
+// The function panics if no component type is registered with this name, or if the migration is already registered.
+func RegisterMigration(name string, from int, m Migration) {
+	if _, ok := typesByName[name]; !ok {
+		panic(fmt.Sprintf("the component type %q you are trying to migrate is not registered", name))
+	}
+
+	if migrations[name] == nil {
+		migrations[name] = make(map[int]Migration)
+	}
+
+	if _, ok := migrations[name][from]; ok {
+		panic(fmt.Sprintf("the migration of component type %q from version %d is already registered", name, from))
+	}
+
+	migrations[name][from] = m
+}
+
+// Migrate upgrades a component serialized as JSON with a version of its type to its current version,
+// applying the migrations in order. It returns an error if a migration is missing or fails,
+// or if the version is newer than the current one, e.g. for a save of a later version of the game.
+func Migrate(name string, version int, data json.RawMessage) (json.RawMessage, error) {
+	current := versions[name]
+
+	if version > current {
+		return nil, fmt.Errorf("component %q: version %d is newer than the current version %d", name, version, current)
+	}
+
+	if version == current {
+		return data, nil
+	}
+
+	fields := make(map[string]interface{})
+
+	err := json.Unmarshal(data, &fields)
+	if err != nil {
+		return nil, fmt.Errorf("component %q: %w", name, err)
+	}
+
+	for v := version; v < current; v++ {
+		m, ok := migrations[name][v]
+		if !ok {
+			return nil, fmt.Errorf("component %q: no migration from version %d", name, v)
+		}
+
+		err = m(fields)
+		if err != nil {
+			return nil, fmt.Errorf("component %q: migration from version %d: %w", name, v, err)
+		}
+	}
+
+	return json.Marshal(fields)
+}
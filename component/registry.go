@@ -0,0 +1,50 @@
+package component
+
+import (
+	"fmt"
+	"reflect"
+)
+
+var (
+	typesByName = make(map[string]reflect.Type)
+	namesByType = make(map[reflect.Type]string)
+)
+
+// Register registers a component type under a name, so that components of this type can be serialized
+// and deserialized. The data argument is a pointer to a value of the component type, e.g. &Position{}.
+// The function panics if data is not a pointer to a struct, or if the name or the type is already registered.
+func Register(name string, data interface{}) {
+	t := reflect.TypeOf(data)
+	if t == nil || t.Kind() != reflect.Ptr || t.Elem().Kind() != reflect.Struct {
+		panic(fmt.Sprintf("the component type %q you are trying to register MUST be a pointer to a struct", name))
+	}
+
+	if _, ok := typesByName[name]; ok {
+		panic(fmt.Sprintf("the component name %q is already registered", name))
+	}
+
+	if registered, ok := namesByType[t]; ok {
+		panic(fmt.Sprintf("the component type %s is already registered as %q", t, registered))
+	}
+
+	typesByName[name] = t
+	namesByType[t] = name
+}
+
+// TypeName returns the name under which the type of the given component data has been registered.
+// The boolean is false if the type is not registered.
+func TypeName(data interface{}) (string, bool) {
+	name, ok := namesByType[reflect.TypeOf(data)]
+	return name, ok
+}
+
+// NewData returns a pointer to a new zero value of the component type registered under the given name.
+// It returns an error if no component type is registered with this name.
+func NewData(name string) (interface{}, error) {
+	t, ok := typesByName[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown component type %q", name)
+	}
+
+	return reflect.New(t.Elem()).Interface(), nil
+}
@@ -0,0 +1,86 @@
+package component
+
+import (
+	"reflect"
+	"sync"
+)
+
+// TypeID is a cheap identifier of a component type.
+// Type IDs are assigned once per component type, so that finding a component of a given type
+// only compares integers instead of using reflection.
+type TypeID int
+
+var (
+	typesMutex sync.RWMutex
+	typeIDs    = make(map[reflect.Type]TypeID)
+)
+
+// typeIDOf returns the type ID of the given reflect type, assigning a new one the first time the type is seen.
+func typeIDOf(t reflect.Type) TypeID {
+	typesMutex.RLock()
+	id, ok := typeIDs[t]
+	typesMutex.RUnlock()
+
+	if ok {
+		return id
+	}
+
+	typesMutex.Lock()
+	defer typesMutex.Unlock()
+
+	id, ok = typeIDs[t]
+	if !ok {
+		id = TypeID(len(typeIDs) + 1)
+		typeIDs[t] = id
+	}
+
+	return id
+}
+
+// TypeIDOf returns the type ID of the given component data.
+// It uses reflection and is meant to be called once, not in the hot path.
+func TypeIDOf(data interface{}) TypeID {
+	return typeIDOf(reflect.TypeOf(data))
+}
+
+// Type is a handle on a component type, used to find components of type *T without reflection.
+// It is meant to be created once per component type, e.g. in a package-level variable:
+//
+//	var PositionType = component.NewType[Position]()
+type Type[T any] struct {
+	id TypeID
+}
+
+// NewType returns the handle on the component type *T.
+func NewType[T any]() Type[T] {
+	return Type[T]{
+		id: typeIDOf(reflect.TypeOf((*T)(nil))),
+	}
+}
+
+// ID returns the type ID of the component type.
+func (t Type[T]) ID() TypeID {
+	return t.id
+}
+
+// Get returns the data of the first component of type *T among the given components, or nil if there is none.
+func (t Type[T]) Get(c []Component) *T {
+	for _, rComponent := range c {
+		if rComponent.TypeID() == t.id {
+			return rComponent.Data().(*T)
+		}
+	}
+
+	return nil
+}
+
+// Has returns true if there is a component of type *T among the given components.
+func (t Type[T]) Has(c []Component) bool {
+	for _, rComponent := range c {
+		if rComponent.TypeID() == t.id {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,52 @@
+package ecs
+
+// Defer queues a function to be run on the world at the beginning of the next Update, from the goroutine
+// running the game loop. It is safe to call Defer from any goroutine, e.g. from asset-loading or network
+// goroutines spawning entities, whether or not the world is thread-safe.
+func (ecs *ECS) Defer(fn func(world *ECS)) {
+	ecs.deferredMutex.Lock()
+	ecs.deferred = append(ecs.deferred, fn)
+	ecs.deferredMutex.Unlock()
+}
+
+// runDeferred runs the functions queued with Defer, in the order they were queued.
+func (ecs *ECS) runDeferred() {
+	ecs.deferredMutex.Lock()
+	deferred := ecs.deferred
+	ecs.deferred = ecs.spare[:0]
+	ecs.deferredMutex.Unlock()
+
+	for i, fn := range deferred {
+		fn(ecs)
+		deferred[i] = nil
+	}
+
+	// keep the drained slice to avoid allocating a new queue every frame
+	ecs.spare = deferred[:0]
+}
+
+// Do runs a function on the world immediately and returns once it is done.
+// In a thread-safe world, Do, Update and Draw are mutually exclusive, so Do can be called from any goroutine,
+// e.g. to read the state of the world. In a world created without WithThreadSafe, Do MUST only be called
+// from the goroutine running the game loop: use Defer from other goroutines.
+// Do MUST NOT be called from a system or a deferred function, as the world is already locked while they run.
+func (ecs *ECS) Do(fn func(world *ECS)) {
+	ecs.lock()
+	defer ecs.unlock()
+
+	fn(ecs)
+}
+
+// lock locks the world if it is thread-safe.
+func (ecs *ECS) lock() {
+	if ecs.config.threadSafe {
+		ecs.mutex.Lock()
+	}
+}
+
+// unlock unlocks the world if it is thread-safe.
+func (ecs *ECS) unlock() {
+	if ecs.config.threadSafe {
+		ecs.mutex.Unlock()
+	}
+}
@@ -0,0 +1,47 @@
+package ecs
+
+import (
+	"github.com/jtbonhomme/ebiten-ecs/system"
+)
+
+// Condition is a predicate on the world deciding whether a system runs during a step.
+type Condition func(world *ECS) bool
+
+// RunIf attaches run conditions to an updater: it only runs during the steps where all its conditions hold,
+// e.g. only while the game state is "playing". Conditions are checked in the order they were attached.
+func (ecs *ECS) RunIf(id system.ID, conditions ...Condition) {
+	ecs.conditions[id] = append(ecs.conditions[id], conditions...)
+}
+
+// ClearConditions removes the run conditions of an updater.
+func (ecs *ECS) ClearConditions(id system.ID) {
+	delete(ecs.conditions, id)
+}
+
+// ResourceIs returns a condition holding while the resource of type *T is registered and equal to value:
+//
+//	world.RunIf(movement.ID(), ecs.ResourceIs(Playing))
+func ResourceIs[T comparable](value T) Condition {
+	return func(world *ECS) bool {
+		r := Resource[T](world)
+		return r != nil && *r == value
+	}
+}
+
+// Not returns a condition holding when the given condition does not.
+func Not(condition Condition) Condition {
+	return func(world *ECS) bool {
+		return !condition(world)
+	}
+}
+
+// conditionsHold returns true if all the run conditions of an updater hold.
+func (ecs *ECS) conditionsHold(id system.ID) bool {
+	for _, condition := range ecs.conditions[id] {
+		if !condition(ecs) {
+			return false
+		}
+	}
+
+	return true
+}
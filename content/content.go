@@ -0,0 +1,264 @@
+// Package content provides data-driven spawning: entity archetypes and level contents are loaded at runtime
+// from JSON files, instead of being hard-coded in Go. Components are referred to by the names they are
+// registered with (see component.Register), and systems by the names they are bound with (see Library.BindSystem).
+//
+// A content file holds archetypes and entities, each entity being spawned from an archetype,
+// with optional overrides of its component values:
+//
+//	{
+//		"archetypes": {
+//			"goblin": {
+//				"components": {"Position": {}, "Health": {"Max": 10, "Current": 10}},
+//				"tags": ["enemy"],
+//				"systems": ["movement"]
+//			}
+//		},
+//		"entities": [
+//			{"archetype": "goblin", "name": "boss", "components": {"Position": {"X": 100}, "Health": {"Max": 50}}}
+//		]
+//	}
+package content
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	ecs "github.com/jtbonhomme/ebiten-ecs"
+	"github.com/jtbonhomme/ebiten-ecs/component"
+	"github.com/jtbonhomme/ebiten-ecs/entity"
+	"github.com/jtbonhomme/ebiten-ecs/system"
+)
+
+// Archetype is the definition of a kind of entity: the initial values of its components, its tags and its systems.
+type Archetype struct {
+	Components map[string]json.RawMessage `json:"components"`
+	Tags       []string                   `json:"tags,omitempty"`
+	Systems    []string                   `json:"systems,omitempty"`
+}
+
+// Entity is an entity of a content file, spawned from an archetype, with optional overrides of its component values
+// and additional components.
+type Entity struct {
+	Archetype  string                     `json:"archetype"`
+	Name       string                     `json:"name,omitempty"`
+	Tags       []string                   `json:"tags,omitempty"`
+	Components map[string]json.RawMessage `json:"components,omitempty"`
+}
+
+// File is the content of a content file.
+type File struct {
+	Archetypes map[string]Archetype `json:"archetypes,omitempty"`
+	Entities   []Entity             `json:"entities,omitempty"`
+}
+
+// Library holds the archetypes loaded from content files, and the systems the spawned entities are associated with.
+type Library struct {
+	archetypes map[string]Archetype
+	systems    map[string]system.System
+	// live holds the definitions of the entities spawned by the library, to reload them.
+	live map[entity.ID]Entity
+}
+
+// NewLibrary creates an empty library.
+func NewLibrary() *Library {
+	return &Library{
+		archetypes: make(map[string]Archetype),
+		systems:    make(map[string]system.System),
+		live:       make(map[entity.ID]Entity),
+	}
+}
+
+// BindSystem binds a system to a name, so that archetypes can refer to it.
+func (l *Library) BindSystem(name string, s system.System) {
+	l.systems[name] = s
+}
+
+// Archetype returns the archetype with the given name.
+func (l *Library) Archetype(name string) (Archetype, bool) {
+	a, ok := l.archetypes[name]
+	return a, ok
+}
+
+// Load reads a content file, adds its archetypes to the library, replacing the archetypes of the same name,
+// and returns its contents. It returns an error if the file is invalid, refers to an unknown component type
+// or system, or holds a value which does not fit its component, in which case the library is left unchanged.
+// The archetypes of the entities are checked when they are spawned, as they may be loaded from another file.
+func (l *Library) Load(r io.Reader) (*File, error) {
+	f, err := l.read(r)
+	if err != nil {
+		return nil, err
+	}
+
+	for name, a := range f.Archetypes {
+		l.archetypes[name] = a
+	}
+
+	return f, nil
+}
+
+// read reads a content file and checks its archetypes and the components of its entities,
+// without changing the library.
+func (l *Library) read(r io.Reader) (*File, error) {
+	f := &File{}
+
+	err := json.NewDecoder(r).Decode(f)
+	if err != nil {
+		return nil, err
+	}
+
+	for name, a := range f.Archetypes {
+		err := l.check(a)
+		if err != nil {
+			return nil, fmt.Errorf("archetype %q: %w", name, err)
+		}
+	}
+
+	for i, e := range f.Entities {
+		_, err := decode(nil, e.Components)
+		if err != nil {
+			return nil, fmt.Errorf("entity %d: %w", i, err)
+		}
+	}
+
+	return f, nil
+}
+
+// LoadFile reads the content file at the given path, see Load.
+func (l *Library) LoadFile(path string) (*File, error) {
+	r, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	return l.Load(r)
+}
+
+// check returns an error if an archetype refers to an unknown component type or system,
+// or if a value does not fit its component.
+func (l *Library) check(a Archetype) error {
+	_, err := decode(a.Components, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range a.Systems {
+		if _, ok := l.systems[name]; !ok {
+			return fmt.Errorf("unknown system %q", name)
+		}
+	}
+
+	return nil
+}
+
+// Prefab returns a prefab creating the components of an archetype, e.g. to fill an ecs.Pool.
+// It returns an error if there is no such archetype, or if its components cannot be created.
+func (l *Library) Prefab(archetype string) (ecs.Prefab, error) {
+	a, ok := l.archetypes[archetype]
+	if !ok {
+		return nil, fmt.Errorf("unknown archetype %q", archetype)
+	}
+
+	_, err := decode(a.Components, nil)
+	if err != nil {
+		return nil, fmt.Errorf("archetype %q: %w", archetype, err)
+	}
+
+	// the values were decoded above, and the archetype cannot change, decoding them again cannot fail
+	return func() []component.Component {
+		data, _ := decode(a.Components, nil)
+		return component.NewSlice(data...)
+	}, nil
+}
+
+// Spawn creates an entity of the world from a content file entity: the components of its archetype, overridden
+// and completed by its own, with the tags of both, its name, and associated with the systems of its archetype.
+// The library keeps track of the entity, so that Reload applies the changes of its definition.
+// It returns an error, and creates no entity, if the archetype or a component type is unknown,
+// if a value does not fit its field, or if the name is already used by another entity.
+func (l *Library) Spawn(world *ecs.ECS, e Entity) (entity.Entity, error) {
+	a, ok := l.archetypes[e.Archetype]
+	if !ok {
+		return entity.Entity{}, fmt.Errorf("unknown archetype %q", e.Archetype)
+	}
+
+	data, err := decode(a.Components, e.Components)
+	if err != nil {
+		return entity.Entity{}, err
+	}
+
+	if named, ok := world.EntityByName(e.Name); ok && e.Name != "" {
+		return entity.Entity{}, fmt.Errorf("the name %q is already used by entity %s", e.Name, named.ID())
+	}
+
+	b := world.NewEntity().With(data...).Tag(a.Tags...).Tag(e.Tags...).Name(e.Name)
+	for _, name := range a.Systems {
+		b.Systems(l.systems[name])
+	}
+
+	spawned := b.Build()
+	l.live[spawned.ID()] = e
+
+	return spawned, nil
+}
+
+// SpawnAll spawns the entities of a content file, in order, e.g. the contents of a level.
+// It stops at the first error, returning the entities spawned so far.
+func (l *Library) SpawnAll(world *ecs.ECS, f *File) ([]entity.Entity, error) {
+	entities := make([]entity.Entity, 0, len(f.Entities))
+
+	for i, e := range f.Entities {
+		spawned, err := l.Spawn(world, e)
+		if err != nil {
+			return entities, fmt.Errorf("entity %d: %w", i, err)
+		}
+
+		entities = append(entities, spawned)
+	}
+
+	return entities, nil
+}
+
+// decode creates the data of the components of an archetype, overridden and completed by other values,
+// in the order of their names.
+func decode(base, overrides map[string]json.RawMessage) ([]interface{}, error) {
+	names := make([]string, 0, len(base)+len(overrides))
+	for name := range base {
+		names = append(names, name)
+	}
+
+	for name := range overrides {
+		if _, ok := base[name]; !ok {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	data := make([]interface{}, 0, len(names))
+
+	for _, name := range names {
+		d, err := component.NewData(name)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, raw := range []json.RawMessage{base[name], overrides[name]} {
+			if len(raw) == 0 {
+				continue
+			}
+
+			err = json.Unmarshal(raw, d)
+			if err != nil {
+				return nil, fmt.Errorf("component %q: %w", name, err)
+			}
+		}
+
+		data = append(data, d)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,86 @@
+package content_test
+
+import (
+	"strings"
+	"testing"
+
+	ecs "github.com/jtbonhomme/ebiten-ecs"
+	"github.com/jtbonhomme/ebiten-ecs/component"
+	"github.com/jtbonhomme/ebiten-ecs/content"
+)
+
+type health struct {
+	Max     int
+	Current int
+}
+
+func init() {
+	component.Register("content_test.health", &health{})
+}
+
+// load loads a content file into a new library.
+func load(t *testing.T, data string) (*content.Library, *content.File) {
+	t.Helper()
+
+	l := content.NewLibrary()
+
+	f, err := l.Load(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return l, f
+}
+
+const goblins = `{
+	"archetypes": {"goblin": {"components": {"content_test.health": {"Max": 10, "Current": 10}}, "tags": ["enemy"]}},
+	"entities": [{"archetype": "goblin", "name": "boss", "components": {"content_test.health": {"Max": 50}}}]
+}`
+
+func TestSpawnDuplicateName(t *testing.T) {
+	l, f := load(t, goblins)
+	world := ecs.New()
+
+	if _, err := l.Spawn(world, f.Entities[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := l.Spawn(world, f.Entities[0]); err == nil {
+		t.Error("Spawn did not fail for a duplicate name")
+	}
+
+	if n := len(world.EntitiesWithTag("enemy")); n != 1 {
+		t.Errorf("%d entities were spawned, want 1", n)
+	}
+}
+
+func TestLoadChecksValues(t *testing.T) {
+	l := content.NewLibrary()
+
+	_, err := l.Load(strings.NewReader(`{"archetypes": {"goblin": {"components": {"content_test.health": {"Max": "ten"}}}}}`))
+	if err == nil {
+		t.Fatal("Load did not fail for a value which does not fit its component")
+	}
+
+	if _, err := l.Prefab("goblin"); err == nil {
+		t.Error("Prefab did not fail for an archetype which was not loaded")
+	}
+}
+
+func TestPrefab(t *testing.T) {
+	l, _ := load(t, goblins)
+
+	prefab, err := l.Prefab("goblin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := prefab()
+	if len(c) != 1 {
+		t.Fatalf("the prefab created %d components, want 1", len(c))
+	}
+
+	if h := c[0].Data().(*health); h.Max != 10 || h.Current != 10 {
+		t.Errorf("health = %+v, want 10/10", *h)
+	}
+}
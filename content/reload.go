@@ -0,0 +1,257 @@
+package content
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"slices"
+	"sort"
+	"time"
+
+	ecs "github.com/jtbonhomme/ebiten-ecs"
+	"github.com/jtbonhomme/ebiten-ecs/component"
+	"github.com/jtbonhomme/ebiten-ecs/entity"
+)
+
+// Reload reads a content file again, like Load, and applies the changes of the definitions to the live entities
+// spawned by the library: when the value of a component of its archetype, or of a named entity of the file,
+// changed, the fields given by the file are set again, the other fields keeping their current value.
+// Components added to a definition are added to the entities, components removed from it are kept.
+// A named entity whose archetype changed in the file keeps its archetype.
+// It returns the IDs of the entities which changed, by increasing ID, their components being reported
+// with MarkChanged. It returns an error if the file is invalid, in which case neither the library
+// nor any entity is changed.
+func (l *Library) Reload(world *ecs.ECS, r io.Reader) ([]entity.ID, error) {
+	f, err := l.read(r)
+	if err != nil {
+		return nil, err
+	}
+
+	archetypes := make(map[string]Archetype, len(l.archetypes)+len(f.Archetypes))
+	for name, a := range l.archetypes {
+		archetypes[name] = a
+	}
+
+	for name, a := range f.Archetypes {
+		archetypes[name] = a
+	}
+
+	named := make(map[string]Entity)
+	for _, e := range f.Entities {
+		if e.Name != "" {
+			named[e.Name] = e
+		}
+	}
+
+	ids := make([]entity.ID, 0, len(l.live))
+	for id := range l.live {
+		ids = append(ids, id)
+	}
+
+	slices.Sort(ids)
+
+	// the changes are decoded into new values first, and only applied once all of them are valid
+	updates := make([]update, 0, len(ids))
+
+	for _, id := range ids {
+		def := l.live[id]
+
+		if !world.Registered(id) {
+			continue
+		}
+
+		next := def
+		if e, ok := named[def.Name]; ok && def.Name != "" && e.Archetype == def.Archetype {
+			next = e
+		}
+
+		u, err := prepare(world, id, l.archetypes[def.Archetype], archetypes[next.Archetype], def, next)
+		if err != nil {
+			return nil, fmt.Errorf("entity %s: %w", id, err)
+		}
+
+		updates = append(updates, u)
+	}
+
+	l.archetypes = archetypes
+	changed := []entity.ID{}
+
+	for _, id := range ids {
+		if !world.Registered(id) {
+			delete(l.live, id)
+		}
+	}
+
+	for _, u := range updates {
+		l.live[u.id] = u.def
+
+		if u.apply(world) {
+			changed = append(changed, u.id)
+		}
+	}
+
+	return changed, nil
+}
+
+// ReloadFile reads the content file at the given path again, see Reload.
+func (l *Library) ReloadFile(world *ecs.ECS, path string) ([]entity.ID, error) {
+	r, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	return l.Reload(world, r)
+}
+
+// update holds the changes of the components of a live entity, decoded from its new definition.
+type update struct {
+	id    entity.ID
+	def   Entity
+	set   []change
+	added []interface{}
+}
+
+// change is the new value of an existing component, decoded into a copy of it.
+type change struct {
+	t       component.TypeID
+	target  interface{}
+	decoded interface{}
+}
+
+// prepare decodes the changes of the components of an entity whose definition changed, without changing them:
+// the values of the existing components are copied, then set again from the file, and the new ones are created.
+func prepare(world *ecs.ECS, id entity.ID, oldA, newA Archetype, oldE, newE Entity) (update, error) {
+	u := update{
+		id:  id,
+		def: newE,
+	}
+
+	names := make([]string, 0, len(newA.Components)+len(newE.Components))
+	for name := range newA.Components {
+		names = append(names, name)
+	}
+
+	for name := range newE.Components {
+		if _, ok := newA.Components[name]; !ok {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	c := world.EntityComponents(id)
+
+	for _, name := range names {
+		if equal(oldA.Components[name], newA.Components[name]) && equal(oldE.Components[name], newE.Components[name]) {
+			continue
+		}
+
+		d, err := component.NewData(name)
+		if err != nil {
+			return u, err
+		}
+
+		t := component.TypeIDOf(d)
+		var current interface{}
+
+		for _, existing := range c {
+			if existing.TypeID() == t {
+				current = existing.Data()
+				reflect.ValueOf(d).Elem().Set(reflect.ValueOf(current).Elem())
+
+				break
+			}
+		}
+
+		for _, raw := range []json.RawMessage{newA.Components[name], newE.Components[name]} {
+			if len(raw) == 0 {
+				continue
+			}
+
+			err = json.Unmarshal(raw, d)
+			if err != nil {
+				return u, fmt.Errorf("component %q: %w", name, err)
+			}
+		}
+
+		if current == nil {
+			u.added = append(u.added, d)
+			continue
+		}
+
+		u.set = append(u.set, change{t: t, target: current, decoded: d})
+	}
+
+	return u, nil
+}
+
+// apply sets the decoded values to the components of the entity, and adds the new ones.
+// It returns true if a component changed.
+func (u update) apply(world *ecs.ECS) bool {
+	for _, c := range u.set {
+		reflect.ValueOf(c.target).Elem().Set(reflect.ValueOf(c.decoded).Elem())
+		world.MarkChanged(u.id, c.t)
+	}
+
+	if len(u.added) > 0 {
+		world.AddComponents(entity.FromID(u.id), u.added...)
+	}
+
+	return len(u.set) > 0 || len(u.added) > 0
+}
+
+// equal returns true if two JSON values are the same, regardless of their formatting.
+func equal(a, b json.RawMessage) bool {
+	var ca, cb bytes.Buffer
+
+	if json.Compact(&ca, a) != nil || json.Compact(&cb, b) != nil {
+		return bytes.Equal(a, b)
+	}
+
+	return bytes.Equal(ca.Bytes(), cb.Bytes())
+}
+
+// Watcher reloads a content file when it is modified, so that designers see their changes in the running game.
+type Watcher struct {
+	library *Library
+	world   *ecs.ECS
+	path    string
+	modTime time.Time
+}
+
+// NewWatcher creates a watcher of the content file at the given path, already loaded by the library.
+func NewWatcher(library *Library, world *ecs.ECS, path string) *Watcher {
+	w := &Watcher{
+		library: library,
+		world:   world,
+		path:    path,
+	}
+
+	if info, err := os.Stat(path); err == nil {
+		w.modTime = info.ModTime()
+	}
+
+	return w
+}
+
+// Poll reloads the file if it was modified since the last call, and returns the IDs of the entities which changed.
+// It is meant to be called between updates, e.g. every second, or when the player presses a reload key.
+// A file which cannot be read or is invalid, e.g. while it is being saved, is reloaded at the next modification.
+func (w *Watcher) Poll() ([]entity.ID, error) {
+	info, err := os.Stat(w.path)
+	if err != nil {
+		return nil, err
+	}
+
+	if info.ModTime().Equal(w.modTime) {
+		return nil, nil
+	}
+
+	w.modTime = info.ModTime()
+
+	return w.library.ReloadFile(w.world, w.path)
+}
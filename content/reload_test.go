@@ -0,0 +1,86 @@
+package content_test
+
+import (
+	"strings"
+	"testing"
+
+	ecs "github.com/jtbonhomme/ebiten-ecs"
+	"github.com/jtbonhomme/ebiten-ecs/content"
+	"github.com/jtbonhomme/ebiten-ecs/entity"
+)
+
+// healthOf returns the health component of an entity.
+func healthOf(t *testing.T, world *ecs.ECS, id entity.ID) *health {
+	t.Helper()
+
+	for _, c := range world.EntityComponents(id) {
+		if h, ok := c.Data().(*health); ok {
+			return h
+		}
+	}
+
+	t.Fatalf("entity %s has no health", id)
+
+	return nil
+}
+
+func TestReload(t *testing.T) {
+	l, f := load(t, goblins)
+	world := ecs.New()
+
+	boss, err := l.Spawn(world, f.Entities[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	healthOf(t, world, boss.ID()).Current = 3
+
+	changed, err := l.Reload(world, strings.NewReader(strings.Replace(goblins, `"Max": 50`, `"Max": 60`, 1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(changed) != 1 || changed[0] != boss.ID() {
+		t.Errorf("changed = %v, want [%s]", changed, boss.ID())
+	}
+
+	// the fields given by the file are set again, the other ones keep their value
+	if h := healthOf(t, world, boss.ID()); h.Max != 60 || h.Current != 10 {
+		t.Errorf("health = %+v, want 60/10", *h)
+	}
+}
+
+func TestReloadInvalidChangesNothing(t *testing.T) {
+	l, f := load(t, goblins)
+	world := ecs.New()
+
+	grunt, err := l.Spawn(world, content.Entity{Archetype: "goblin"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	boss, err := l.Spawn(world, f.Entities[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the archetype is valid and changes the first entity, the named entity is not
+	invalid := strings.Replace(goblins, `"Max": 10`, `"Max": 20`, 1)
+	invalid = strings.Replace(invalid, `"Max": 50`, `"Max": "fifty"`, 1)
+
+	if _, err := l.Reload(world, strings.NewReader(invalid)); err == nil {
+		t.Fatal("Reload did not fail for an invalid value")
+	}
+
+	if h := healthOf(t, world, grunt.ID()); h.Max != 10 {
+		t.Errorf("first entity Max = %d, want 10", h.Max)
+	}
+
+	if h := healthOf(t, world, boss.ID()); h.Max != 50 {
+		t.Errorf("named entity Max = %d, want 50", h.Max)
+	}
+
+	if a, _ := l.Archetype("goblin"); !strings.Contains(string(a.Components["content_test.health"]), `"Max": 10`) {
+		t.Errorf("the archetype was replaced: %s", a.Components["content_test.health"])
+	}
+}
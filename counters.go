@@ -0,0 +1,73 @@
+package ecs
+
+import (
+	"reflect"
+
+	"github.com/jtbonhomme/ebiten-ecs/component"
+)
+
+// componentSize is the size of a component value, its slot in the slice of an entity.
+var componentSize = int64(reflect.TypeFor[component.Component]().Size())
+
+// counters keeps the number of components per type and an estimate of their memory up to date
+// as entities are registered and unregistered, so that Stats is cheap enough for a debug overlay.
+type counters struct {
+	components   map[component.TypeID]int
+	names        map[component.TypeID]string
+	sizes        map[reflect.Type]int64
+	bytes        int64
+	storeBytes   int64
+	peakEntities int
+	peakBytes    int64
+}
+
+// newCounters creates empty counters.
+func newCounters() counters {
+	return counters{
+		components: make(map[component.TypeID]int),
+		names:      make(map[component.TypeID]string),
+		sizes:      make(map[reflect.Type]int64),
+	}
+}
+
+// sizeOf returns the estimated memory of a component: its slot in the slice of the entity and its data.
+func (c *counters) sizeOf(comp component.Component) int64 {
+	t := reflect.TypeOf(comp.Data())
+
+	s, ok := c.sizes[t]
+	if !ok {
+		if t.Kind() == reflect.Ptr {
+			s = int64(t.Elem().Size())
+		} else {
+			s = int64(t.Size())
+		}
+
+		c.sizes[t] = s
+	}
+
+	return componentSize + s
+}
+
+// count adds (sign 1) or removes (sign -1) components from the counters.
+func (ecs *ECS) count(components []component.Component, sign int) {
+	c := &ecs.counters
+
+	for _, comp := range components {
+		t := comp.TypeID()
+		c.components[t] += sign
+		c.bytes += int64(sign) * c.sizeOf(comp)
+
+		if _, ok := c.names[t]; !ok {
+			c.names[t] = debugTypeName(comp.Data())
+		}
+	}
+
+	c.peakEntities = max(c.peakEntities, len(ecs.componentsRegistry))
+	c.peakBytes = max(c.peakBytes, c.bytes+c.storeBytes)
+}
+
+// resetCounts empties the counters, keeping the peak values.
+func (ecs *ECS) resetCounts() {
+	clear(ecs.counters.components)
+	ecs.counters.bytes = 0
+}
@@ -0,0 +1,185 @@
+package ecs
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"slices"
+	"time"
+
+	"github.com/jtbonhomme/ebiten-ecs/entity"
+	"github.com/jtbonhomme/ebiten-ecs/system"
+)
+
+// CrashError is the error reported when a system panics, in a world created with WithCrashDump.
+type CrashError struct {
+	// System is the ID of the system which panicked.
+	System system.ID
+	// SystemName is the Go type of the system.
+	SystemName string
+	// Entity is the ID of the entity the system was running on.
+	Entity entity.ID
+	// Phase is the phase of the frame the system was running in.
+	Phase Phase
+	// Tick is the tick of the world when the system panicked.
+	Tick uint64
+	// Value is the value the system panicked with.
+	Value interface{}
+	// Stack is the stack trace of the goroutine when the system panicked.
+	Stack []byte
+	// Dump is the path of the crash dump, empty if it could not be written.
+	Dump string
+	// DumpErr is the error which prevented the crash dump from being written, if any.
+	DumpErr error
+}
+
+// Error returns the description of the crash.
+func (e *CrashError) Error() string {
+	msg := fmt.Sprintf("system %d (%s) panicked on entity %s at tick %d: %v", e.System, e.SystemName, e.Entity, e.Tick, e.Value)
+
+	if e.Dump != "" {
+		return msg + ", crash dump written to " + e.Dump
+	}
+
+	return fmt.Sprintf("%s, crash dump not written: %v", msg, e.DumpErr)
+}
+
+// Unwrap returns the value the system panicked with, if it is an error.
+func (e *CrashError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// WithCrashDump recovers from the panics of the systems, during Update as well as during the draw phase
+// of a rendering package, and writes a crash dump to the given directory: the system and the entity
+// which panicked, the stack trace and the state of all the entities, for the post-mortem debugging
+// of player crash reports. The world then panics again with a *CrashError if repanic is true,
+// or Update returns the *CrashError, a panic of a drawer being returned by the next Update.
+func WithCrashDump(dir string, repanic bool) Option {
+	return func(c *config) {
+		c.crashDir = dir
+		c.crashRepanic = repanic
+	}
+}
+
+// crashDump is the content of a crash dump file.
+type crashDump struct {
+	Time       time.Time      `json:"time"`
+	Tick       uint64         `json:"tick"`
+	Phase      Phase          `json:"phase"`
+	System     system.ID      `json:"system"`
+	SystemName string         `json:"systemName"`
+	Entity     entity.ID      `json:"entity"`
+	Panic      string         `json:"panic"`
+	Stack      string         `json:"stack"`
+	Entities   []dumpedEntity `json:"entities"`
+}
+
+// dumpedEntity is the representation of an entity in a crash dump.
+type dumpedEntity struct {
+	ID entity.ID `json:"id"`
+	exportedEntity
+}
+
+// recoverCrash is deferred by the functions running systems: when crash dumps are enabled, it recovers
+// from a panic of the system running on the given entity, writes the crash dump,
+// then panics again or sets err, depending on the configuration.
+func (ecs *ECS) recoverCrash(s system.System, phase Phase, id *entity.ID, err *error) {
+	if ecs.config.crashDir == "" {
+		return
+	}
+
+	v := recover()
+	if v == nil {
+		return
+	}
+
+	crash := &CrashError{
+		System:     s.ID(),
+		SystemName: fmt.Sprintf("%T", unwrap(s)),
+		Entity:     *id,
+		Phase:      phase,
+		Tick:       ecs.tick,
+		Value:      v,
+		Stack:      debug.Stack(),
+	}
+
+	crash.Dump, crash.DumpErr = ecs.writeCrashDump(crash)
+
+	if ecs.config.crashRepanic {
+		panic(crash)
+	}
+
+	*err = crash
+}
+
+// writeCrashDump writes the crash dump of a crash, and returns its path.
+func (ecs *ECS) writeCrashDump(crash *CrashError) (string, error) {
+	dump := crashDump{
+		Time:       time.Now(),
+		Tick:       crash.Tick,
+		Phase:      crash.Phase,
+		System:     crash.System,
+		SystemName: crash.SystemName,
+		Entity:     crash.Entity,
+		Panic:      fmt.Sprint(crash.Value),
+		Stack:      string(crash.Stack),
+		Entities:   ecs.dumpEntities(),
+	}
+
+	data, err := json.MarshalIndent(dump, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	err = os.MkdirAll(ecs.config.crashDir, 0o755)
+	if err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(ecs.config.crashDir, fmt.Sprintf("crash-%s.json", dump.Time.Format("20060102-150405.000000000")))
+
+	err = os.WriteFile(path, data, 0o644)
+	if err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
+
+// dumpEntities returns the representations of all the entities, by increasing ID. Unlike ExportEntities,
+// it never fails: components of unregistered types are named after their Go type, and components
+// which cannot be serialized to JSON are formatted with fmt.
+func (ecs *ECS) dumpEntities() []dumpedEntity {
+	ids := make([]entity.ID, 0, len(ecs.componentsRegistry))
+	for id := range ecs.componentsRegistry {
+		ids = append(ids, id)
+	}
+
+	slices.Sort(ids)
+
+	dumped := make([]dumpedEntity, 0, len(ids))
+
+	for _, id := range ids {
+		ee := exportedEntity{
+			Name:       ecs.Name(id),
+			Tags:       ecs.Tags(id),
+			Components: make(map[string]json.RawMessage, len(ecs.componentsRegistry[id])),
+		}
+
+		for _, c := range ecs.componentsRegistry[id] {
+			data, err := json.Marshal(c.Data())
+			if err != nil {
+				data, _ = json.Marshal(fmt.Sprintf("%+v", c.Data()))
+			}
+
+			ee.Components[debugTypeName(c.Data())] = data
+		}
+
+		dumped = append(dumped, dumpedEntity{ID: id, exportedEntity: ee})
+	}
+
+	return dumped
+}
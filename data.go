@@ -0,0 +1,23 @@
+package ecs
+
+import (
+	"github.com/jtbonhomme/ebiten-ecs/entity"
+)
+
+// Spawn creates and registers an entity with the given data as components, plain pointers to component structs:
+//
+//	player := world.Spawn(&Position{}, &Velocity{})
+//
+// The components are stored in a single allocation. The method panics for the reasons RegisterEntity does.
+func (ecs *ECS) Spawn(data ...interface{}) entity.Entity {
+	e := entity.New()
+	ecs.AddComponents(e, data...)
+
+	return e
+}
+
+// AddComponents adds the given data as components, plain pointers to component structs, to an already registered
+// entity, or registers it. It is RegisterEntity, named for entities which already exist.
+func (ecs *ECS) AddComponents(e entity.Entity, data ...interface{}) {
+	ecs.RegisterEntity(e, data...)
+}
@@ -0,0 +1,351 @@
+package ecs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jtbonhomme/ebiten-ecs/component"
+	"github.com/jtbonhomme/ebiten-ecs/entity"
+)
+
+// debugEvents is the number of events kept by the debug server.
+const debugEvents = 256
+
+// debugTimeout is the time a debug request waits for the game loop to run an update.
+const debugTimeout = 2 * time.Second
+
+// DebugEvent is an event of the world traced by the debug server.
+type DebugEvent struct {
+	Tick   uint64      `json:"tick"`
+	Time   time.Time   `json:"time"`
+	Kind   string      `json:"kind"`
+	Entity entity.ID   `json:"entity,omitempty"`
+	Data   interface{} `json:"data,omitempty"`
+}
+
+// DebugServer is an HTTP server exposing the state of a running world as JSON, with a small web UI,
+// so that a game can be inspected from a browser, including on mobile or WASM builds during development:
+//
+//	GET /                the web UI
+//	GET /entities        the entities, with their names, tags and component types
+//	GET /entities/{id}   the components of an entity
+//	GET /systems         the measures of the systems, see Stats
+//	GET /events          the last events: spawns, despawns and the events traced with Event
+//
+// Requests are served from the game loop, at the beginning of the next update, so that the world is never read
+// while it changes. A request fails when the game loop does not update the world in time.
+type DebugServer struct {
+	world    *ECS
+	server   *http.Server
+	listener net.Listener
+	hooks    [2]HookID
+
+	mutex  sync.Mutex
+	events []DebugEvent
+	next   int
+}
+
+// ServeDebug starts serving the state of the world over HTTP at the given address, e.g. "localhost:6060",
+// and enables profiling. The server MUST NOT be exposed in release builds.
+// It returns an error if the address cannot be listened on.
+func (ecs *ECS) ServeDebug(addr string) (*DebugServer, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &DebugServer{
+		world:    ecs,
+		listener: listener,
+		events:   make([]DebugEvent, 0, debugEvents),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", s.serveUI)
+	mux.HandleFunc("GET /entities", s.serveEntities)
+	mux.HandleFunc("GET /entities/{id}", s.serveEntity)
+	mux.HandleFunc("GET /systems", s.serveSystems)
+	mux.HandleFunc("GET /events", s.serveEvents)
+
+	s.server = &http.Server{Handler: mux, ReadHeaderTimeout: debugTimeout}
+
+	ecs.EnableProfiling()
+	s.hooks[0] = ecs.OnSpawn(func(id entity.ID, _ []component.Component) {
+		s.Event("spawn", id, nil)
+	})
+	s.hooks[1] = ecs.OnDespawn(func(id entity.ID, _ []component.Component) {
+		s.Event("despawn", id, nil)
+	})
+
+	go func() {
+		_ = s.server.Serve(listener)
+	}()
+
+	return s, nil
+}
+
+// Addr returns the address the server listens on.
+func (s *DebugServer) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// Close stops the server, and stops tracing the spawns and despawns of the world.
+// It MUST be called from the goroutine running the game loop.
+func (s *DebugServer) Close() error {
+	for _, id := range s.hooks {
+		s.world.RemoveHook(id)
+	}
+
+	return s.server.Close()
+}
+
+// Event traces an event of the game, e.g. a collision or a published message, about an entity or not (ID 0).
+// The data MUST be serializable to JSON. Event MUST be called from the goroutine running the game loop.
+func (s *DebugServer) Event(kind string, id entity.ID, data interface{}) {
+	e := DebugEvent{
+		Tick:   s.world.tick,
+		Time:   time.Now(),
+		Kind:   kind,
+		Entity: id,
+		Data:   data,
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if len(s.events) < debugEvents {
+		s.events = append(s.events, e)
+		return
+	}
+
+	s.events[s.next] = e
+	s.next = (s.next + 1) % debugEvents
+}
+
+// debugEntity is the JSON representation of an entity in the list of entities.
+type debugEntity struct {
+	ID         entity.ID `json:"id"`
+	Name       string    `json:"name,omitempty"`
+	Tags       []string  `json:"tags,omitempty"`
+	Active     bool      `json:"active"`
+	Components []string  `json:"components"`
+}
+
+// serveEntities serves the list of the entities, by increasing ID.
+func (s *DebugServer) serveEntities(w http.ResponseWriter, r *http.Request) {
+	s.serve(w, func(world *ECS) (interface{}, error) {
+		ids := make([]entity.ID, 0, len(world.componentsRegistry))
+		for id := range world.componentsRegistry {
+			ids = append(ids, id)
+		}
+
+		slices.Sort(ids)
+
+		entities := make([]debugEntity, 0, len(ids))
+
+		for _, id := range ids {
+			de := debugEntity{
+				ID:     id,
+				Name:   world.Name(id),
+				Tags:   world.Tags(id),
+				Active: world.IsActive(id),
+			}
+
+			for _, c := range world.componentsRegistry[id] {
+				de.Components = append(de.Components, debugTypeName(c.Data()))
+			}
+
+			entities = append(entities, de)
+		}
+
+		return entities, nil
+	})
+}
+
+// serveEntity serves the components of an entity, by type name.
+func (s *DebugServer) serveEntity(w http.ResponseWriter, r *http.Request) {
+	n, err := strconv.ParseUint(r.PathValue("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid entity ID", http.StatusBadRequest)
+		return
+	}
+
+	id := entity.ID(n)
+
+	s.serve(w, func(world *ECS) (interface{}, error) {
+		c, ok := world.componentsRegistry[id]
+		if !ok {
+			return nil, errNotFound
+		}
+
+		components := make(map[string]json.RawMessage, len(c))
+
+		for _, c := range c {
+			data, err := json.Marshal(c.Data())
+			if err != nil {
+				data, _ = json.Marshal(fmt.Sprintf("%+v", c.Data()))
+			}
+
+			components[debugTypeName(c.Data())] = data
+		}
+
+		return components, nil
+	})
+}
+
+// serveSystems serves the measures of the systems during the last frame.
+func (s *DebugServer) serveSystems(w http.ResponseWriter, r *http.Request) {
+	s.serve(w, func(world *ECS) (interface{}, error) {
+		return world.Stats().Systems, nil
+	})
+}
+
+// serveEvents serves the last events, oldest first.
+func (s *DebugServer) serveEvents(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	events := make([]DebugEvent, 0, len(s.events))
+	events = append(events, s.events[s.next:]...)
+	events = append(events, s.events[:s.next]...)
+	s.mutex.Unlock()
+
+	writeJSON(w, events)
+}
+
+// serveUI serves the web UI.
+func (s *DebugServer) serveUI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = w.Write([]byte(debugUI))
+}
+
+// errNotFound is returned by a debug request about an entity which does not exist.
+var errNotFound = errors.New("not found")
+
+// serve runs a function reading the world from the game loop, and writes its result as JSON.
+func (s *DebugServer) serve(w http.ResponseWriter, read func(world *ECS) (interface{}, error)) {
+	type result struct {
+		value interface{}
+		err   error
+	}
+
+	done := make(chan result, 1)
+
+	s.world.Defer(func(world *ECS) {
+		v, err := read(world)
+		done <- result{value: v, err: err}
+	})
+
+	select {
+	case res := <-done:
+		switch {
+		case errors.Is(res.err, errNotFound):
+			http.Error(w, res.err.Error(), http.StatusNotFound)
+		case res.err != nil:
+			http.Error(w, res.err.Error(), http.StatusInternalServerError)
+		default:
+			writeJSON(w, res.value)
+		}
+	case <-time.After(debugTimeout):
+		http.Error(w, "the game loop did not update the world in time", http.StatusServiceUnavailable)
+	}
+}
+
+// writeJSON writes a value as an indented JSON response.
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(data)
+}
+
+// debugTypeName returns the registered name of the type of component data, or its Go type.
+func debugTypeName(data interface{}) string {
+	if name, ok := component.TypeName(data); ok {
+		return name
+	}
+
+	return fmt.Sprintf("%T", data)
+}
+
+// debugUI is the web UI of the debug server, polling the JSON endpoints.
+const debugUI = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>ECS inspector</title>
+<style>
+body { font-family: monospace; margin: 1em; }
+table { border-collapse: collapse; margin-bottom: 1em; }
+td, th { border: 1px solid #ccc; padding: 2px 6px; text-align: left; vertical-align: top; }
+tr.entity { cursor: pointer; }
+pre { background: #f4f4f4; padding: 0.5em; }
+</style>
+</head>
+<body>
+<h2>Systems</h2>
+<table id="systems"></table>
+<h2>Entities</h2>
+<table id="entities"></table>
+<pre id="components">click an entity to inspect its components</pre>
+<h2>Events</h2>
+<table id="events"></table>
+<script>
+function row(cells, tag) {
+  var tr = document.createElement("tr");
+  cells.forEach(function (c) {
+    var td = document.createElement(tag || "td");
+    td.textContent = c;
+    tr.appendChild(td);
+  });
+  return tr;
+}
+function fill(id, header, rows) {
+  var table = document.getElementById(id);
+  table.replaceChildren(row(header, "th"));
+  rows.forEach(function (r) { table.appendChild(r); });
+}
+function get(path, fn) {
+  fetch(path).then(function (r) { return r.ok ? r.json() : Promise.reject(r.statusText); }).then(fn).catch(function () {});
+}
+function inspect(id) {
+  get("/entities/" + id, function (c) {
+    document.getElementById("components").textContent = "entity " + id + "\n" + JSON.stringify(c, null, 2);
+  });
+}
+function refresh() {
+  get("/systems", function (systems) {
+    fill("systems", ["id", "name", "update", "entities", "draw", "entities"], (systems || []).map(function (s) {
+      return row([s.ID, s.Name, s.UpdateDuration / 1000 + "µs", s.UpdateEntities, s.DrawDuration / 1000 + "µs", s.DrawEntities]);
+    }));
+  });
+  get("/entities", function (entities) {
+    fill("entities", ["id", "name", "tags", "active", "components"], entities.map(function (e) {
+      var tr = row([e.id, e.name || "", (e.tags || []).join(" "), e.active, (e.components || []).join(" ")]);
+      tr.className = "entity";
+      tr.onclick = function () { inspect(e.id); };
+      return tr;
+    }));
+  });
+  get("/events", function (events) {
+    fill("events", ["tick", "kind", "entity", "data"], events.slice(-50).reverse().map(function (e) {
+      return row([e.tick, e.kind, e.entity || "", e.data === undefined ? "" : JSON.stringify(e.data)]);
+    }));
+  });
+}
+refresh();
+setInterval(refresh, 1000);
+</script>
+</body>
+</html>
+`
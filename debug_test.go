@@ -0,0 +1,22 @@
+package ecs
+
+import (
+	"testing"
+)
+
+func TestDebugServerCloseRemovesHooks(t *testing.T) {
+	world := New()
+
+	s, err := world.ServeDebug("localhost:0")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(world.spawnHooks) != 0 || len(world.despawnHooks) != 0 {
+		t.Errorf("%d spawn and %d despawn hooks left after Close, want none", len(world.spawnHooks), len(world.despawnHooks))
+	}
+}
@@ -0,0 +1,201 @@
+// Package debugdraw provides a debug drawer visualizing, over the world pass, the colliders, velocities,
+// origins and IDs of the entities, and the cells of a grid, toggleable at runtime.
+package debugdraw
+
+import (
+	"image/color"
+	"math"
+	"strconv"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+
+	"github.com/jtbonhomme/ebiten-ecs/component"
+	"github.com/jtbonhomme/ebiten-ecs/ebitenecs"
+	"github.com/jtbonhomme/ebiten-ecs/physics"
+	"github.com/jtbonhomme/ebiten-ecs/sprite"
+	"github.com/jtbonhomme/ebiten-ecs/system"
+)
+
+// Layer is the z-index the debug drawer is meant to be registered at in the world pass, above the other drawers.
+const Layer = 1 << 20
+
+// Colors of the debug shapes.
+var (
+	ColliderColor = color.RGBA{R: 0, G: 255, B: 0, A: 255}
+	VelocityColor = color.RGBA{R: 255, G: 255, B: 0, A: 255}
+	OriginColor   = color.RGBA{R: 255, G: 0, B: 255, A: 255}
+	GridColor     = color.RGBA{R: 64, G: 64, B: 64, A: 128}
+)
+
+// PositionFunc returns the position of an entity in the world, and false if it has none.
+type PositionFunc func(c []component.Component) (float64, float64, bool)
+
+var (
+	bodyType     = component.NewType[physics.RigidBody]()
+	colliderType = component.NewType[physics.Collider]()
+	spriteType   = component.NewType[sprite.Sprite]()
+)
+
+// DefaultPosition returns the position of the rigid body of an entity, or of its sprite.
+func DefaultPosition(c []component.Component) (float64, float64, bool) {
+	if b := bodyType.Get(c); b != nil {
+		return b.X, b.Y, true
+	}
+
+	if s := spriteType.Get(c); s != nil {
+		return s.X, s.Y, true
+	}
+
+	return 0, 0, false
+}
+
+// Drawer is the debug drawer, registered in the world pass with the entities to visualize.
+// Everything it draws is drawn once all its entities have been seen, so that it is not hidden by them.
+type Drawer struct {
+	id    system.ID
+	world *ebitenecs.World
+	// Enabled shows or hides everything the drawer draws.
+	Enabled bool
+	// ToggleKey, if not -1, toggles Enabled when pressed.
+	ToggleKey  ebiten.Key
+	Colliders  bool
+	Velocities bool
+	Origins    bool
+	IDs        bool
+	// GridSize, if not 0, is the size of the cells of the grid drawn in the world, in pixels.
+	GridSize float64
+	// Position returns the position of the entities, DefaultPosition if nil.
+	Position PositionFunc
+	camera   ebiten.GeoM
+}
+
+// NewDrawer creates an enabled debug drawer showing everything but the grid.
+func NewDrawer(world *ebitenecs.World) *Drawer {
+	return &Drawer{
+		id:         system.AssignID(),
+		world:      world,
+		Enabled:    true,
+		ToggleKey:  -1,
+		Colliders:  true,
+		Velocities: true,
+		Origins:    true,
+		IDs:        true,
+	}
+}
+
+// ID returns the unique ID of the drawer.
+func (d *Drawer) ID() system.ID {
+	return d.id
+}
+
+// DrawWorld does nothing: the debug shapes of every entity are drawn by Flush.
+func (d *Drawer) DrawWorld(*ebiten.Image, ebiten.GeoM, []component.Component) {}
+
+// Flush draws the debug shapes of the active entities of the drawer.
+func (d *Drawer) Flush(screen *ebiten.Image) {
+	if d.ToggleKey >= 0 && inpututil.IsKeyJustPressed(d.ToggleKey) {
+		d.Enabled = !d.Enabled
+	}
+
+	if !d.Enabled {
+		return
+	}
+
+	d.camera = d.world.Camera().GeoM(screen.Bounds().Dx(), screen.Bounds().Dy())
+
+	if d.GridSize > 0 {
+		d.drawGrid(screen)
+	}
+
+	position := d.Position
+	if position == nil {
+		position = DefaultPosition
+	}
+
+	for _, e := range d.world.FilterEntities(d) {
+		if !d.world.IsActive(e.ID()) {
+			continue
+		}
+
+		c := d.world.EntityComponents(e.ID())
+
+		x, y, ok := position(c)
+		if !ok {
+			continue
+		}
+
+		sx, sy := d.camera.Apply(x, y)
+
+		if col := colliderType.Get(c); d.Colliders && col != nil {
+			d.drawCollider(screen, col, x, y)
+		}
+
+		if b := bodyType.Get(c); d.Velocities && b != nil {
+			vx, vy := d.camera.Apply(x+b.VelocityX, y+b.VelocityY)
+			vector.StrokeLine(screen, float32(sx), float32(sy), float32(vx), float32(vy), 1, VelocityColor, false)
+		}
+
+		if d.Origins {
+			vector.StrokeLine(screen, float32(sx-3), float32(sy), float32(sx+3), float32(sy), 1, OriginColor, false)
+			vector.StrokeLine(screen, float32(sx), float32(sy-3), float32(sx), float32(sy+3), 1, OriginColor, false)
+		}
+
+		if d.IDs {
+			ebitenutil.DebugPrintAt(screen, strconv.Itoa(int(e.ID())), int(sx)+4, int(sy)+4)
+		}
+	}
+}
+
+// drawCollider draws the outline of a collider centered on a position of the world.
+func (d *Drawer) drawCollider(screen *ebiten.Image, col *physics.Collider, x, y float64) {
+	switch col.Shape {
+	case physics.Circle:
+		const segments = 24
+
+		for i := 0; i < segments; i++ {
+			a0, a1 := 2*math.Pi*float64(i)/segments, 2*math.Pi*float64(i+1)/segments
+			d.line(screen, x+col.Radius*math.Cos(a0), y+col.Radius*math.Sin(a0),
+				x+col.Radius*math.Cos(a1), y+col.Radius*math.Sin(a1), ColliderColor)
+		}
+	case physics.Box:
+		hw, hh := col.Width/2, col.Height/2
+		d.line(screen, x-hw, y-hh, x+hw, y-hh, ColliderColor)
+		d.line(screen, x+hw, y-hh, x+hw, y+hh, ColliderColor)
+		d.line(screen, x+hw, y+hh, x-hw, y+hh, ColliderColor)
+		d.line(screen, x-hw, y+hh, x-hw, y-hh, ColliderColor)
+	}
+}
+
+// line draws a line between two positions of the world.
+func (d *Drawer) line(screen *ebiten.Image, x0, y0, x1, y1 float64, clr color.Color) {
+	sx0, sy0 := d.camera.Apply(x0, y0)
+	sx1, sy1 := d.camera.Apply(x1, y1)
+	vector.StrokeLine(screen, float32(sx0), float32(sy0), float32(sx1), float32(sy1), 1, clr, false)
+}
+
+// drawGrid draws the lines of the grid crossing the visible part of the world.
+func (d *Drawer) drawGrid(screen *ebiten.Image) {
+	inverse := d.camera
+	inverse.Invert()
+
+	w, h := float64(screen.Bounds().Dx()), float64(screen.Bounds().Dy())
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+
+	for _, p := range [4][2]float64{{0, 0}, {w, 0}, {0, h}, {w, h}} {
+		x, y := inverse.Apply(p[0], p[1])
+		minX, minY = math.Min(minX, x), math.Min(minY, y)
+		maxX, maxY = math.Max(maxX, x), math.Max(maxY, y)
+	}
+
+	for x := math.Floor(minX/d.GridSize) * d.GridSize; x <= maxX; x += d.GridSize {
+		d.line(screen, x, minY, x, maxY, GridColor)
+	}
+
+	for y := math.Floor(minY/d.GridSize) * d.GridSize; y <= maxY; y += d.GridSize {
+		d.line(screen, minX, y, maxX, y, GridColor)
+	}
+}
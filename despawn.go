@@ -0,0 +1,58 @@
+package ecs
+
+import "github.com/jtbonhomme/ebiten-ecs/entity"
+
+// Died is the event of an entity despawned with Despawn, whose removal was finalized at the end of an update.
+type Died struct {
+	Entity entity.ID
+	// Tick is the tick at the end of which the entity was removed.
+	Tick uint64
+}
+
+// Despawn marks an entity as dead: it is skipped by the updaters, the drawers and the queries from now on,
+// but it is only unregistered at the end of the current update (or of the next one, when called outside an update),
+// so that systems can despawn entities while other systems iterate over the registries.
+// Despawning an entity twice, or an entity which is not registered, has no effect.
+func (ecs *ECS) Despawn(id entity.ID) {
+	if _, ok := ecs.componentsRegistry[id]; !ok {
+		return
+	}
+
+	if _, ok := ecs.dying[id]; ok {
+		return
+	}
+
+	ecs.dying[id] = struct{}{}
+	ecs.dyingOrder = append(ecs.dyingOrder, id)
+}
+
+// IsDead returns true if the entity was despawned with Despawn and is waiting to be unregistered.
+func (ecs *ECS) IsDead(id entity.ID) bool {
+	_, ok := ecs.dying[id]
+	return ok
+}
+
+// DiedEvents returns the entities unregistered at the end of the last update, in the order they were despawned.
+// The returned slice MUST NOT be modified.
+func (ecs *ECS) DiedEvents() []Died {
+	return ecs.died
+}
+
+// finalizeDespawns unregisters the entities despawned during the update, and publishes their Died events.
+func (ecs *ECS) finalizeDespawns() {
+	ecs.died = ecs.died[:0]
+
+	// entities despawned by the despawn hooks are finalized as well
+	for i := 0; i < len(ecs.dyingOrder); i++ {
+		id := ecs.dyingOrder[i]
+		if _, ok := ecs.dying[id]; !ok {
+			// already unregistered with UnregisterEntity
+			continue
+		}
+
+		ecs.UnregisterEntity(id)
+		ecs.died = append(ecs.died, Died{Entity: id, Tick: ecs.tick})
+	}
+
+	ecs.dyingOrder = ecs.dyingOrder[:0]
+}
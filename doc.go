@@ -1,6 +1,6 @@
 /*
-Package ebitenecs implements the Entity-Component-System (ECS) architecture
-for use with the Ebiten game library (https://ebiten.org/). It allows developers to create and manage entities, components, and systems in a modular and flexible way, enabling complex behaviors through simple components and systems.
+Package ecs implements the Entity-Component-System (ECS) architecture
+for use with the Ebiten game library (https://ebiten.org/), through the bindings of the ebitenecs package. It allows developers to create and manage entities, components, and systems in a modular and flexible way, enabling complex behaviors through simple components and systems.
 
 It provides a framework for managing entities, components, and systems in a game or simulation.
 The ECS architecture allows for a flexible and modular design, enabling developers to create complex behaviors by composing simple components and systems.
@@ -19,7 +19,7 @@ Systems are the logic and behavior of the ECS architecture. They operate on enti
 
 # Ebitenecs ECS Implementation
 
-In ebitenecs, systems are represented by the Updater and Drawer interfaces, which define the methods for updating and drawing entities and their components.
+In ebitenecs, systems are represented by the system.Updater and ebitenecs.Drawer interfaces, which define the methods for updating and drawing entities and their components.
 
 The Updater interface defines the Update method, which is called every frame to update the state of the entities and their components.
 
@@ -27,21 +27,25 @@ The Drawer interface defines the Draw method, which is called every frame to dra
 
 # Usage
 
-First, create an instance of the ECS:
+First, create an instance of the ECS bound to Ebiten:
 
 	world := ebitenecs.New()
 
+Capacity and behavior can be tuned with options:
+
+	world := ebitenecs.New(ecs.WithMaxEntities(10000), ecs.WithFixedTimestep(60))
+
 Then create an entity and register it with the ECS. Don't forget to add a component to the entity:
 
 	countDown := entity.New()
-	world.RegisterEntity(
-		countDown,
-		component.New(
-			&CounterComponent{
-				Value: 1000000,
-			},
-		),
-	)
+	world.RegisterEntity(countDown, &CounterComponent{Value: 1000000})
+
+The same entity can be built in a single expression with the entity builder:
+
+	countDown := world.NewEntity().
+		With(&CounterComponent{Value: 1000000}).
+		Tag("counter").
+		Build()
 
 Then create a system and register it with the ECS:
 
@@ -62,5 +66,90 @@ Then create a system and register it with the ECS:
 		254,
 		countDown,
 	)
+
+Finally, run the world with a logical resolution of 640x480, without writing an ebiten.Game of your own:
+
+	err := ebitenecs.NewRunner(world, 640, 480).Run()
+
+# Querying components
+
+Systems receive the components of the entity they process. A component is found by its type
+with a handle created once per component type, which does not rely on reflection:
+
+	var counterType = component.NewType[CounterComponent]()
+
+	counter := counterType.Get(c)
+
+# Resources
+
+Data that is not tied to any entity (score, configuration, camera...) can be stored as a world resource,
+instead of a dummy entity holding a component:
+
+	world.SetResource(&GameConfig{Difficulty: 2})
+
+	config := ecs.Resource[GameConfig](world)
+
+# Deterministic simulation
+
+A world created with WithDeterministic runs exactly one step per Update, and seeds its random number generator
+with the given seed. Systems using world.Rand() and world.Input() instead of math/rand and Ebiten input functions
+produce the same results from the same input, which can be recorded and replayed:
+
+	world := ebitenecs.New(ecs.WithDeterministic(42))
+	err := world.Record(file)
+	// ...
+	err = world.Replay(file)
+
+A system can draw its numbers from its own stream, world.SystemRand(s) or world.RandStream(name), so that changes
+to the other systems do not shift the numbers it draws.
+
+# Headless mode
+
+The ecs package and its entity, component, system and input subpackages do not import Ebiten: everything
+related to rendering and input devices (drawers, views, debug keys...) lives in the ebitenecs package.
+Game servers and CI tests can run the same simulation code without a display, by creating the world with
+ecs.New instead of ebitenecs.New. Such a world sees no input, except the one replayed from a recording
+or provided with SetInputSource.
+
+# Time
+
+The world maintains a Time resource with the simulated duration of the current step, scaled by its time scale,
+so that slow motion and pause apply to every time-dependent system:
+
+	dt := world.Time().DeltaSeconds()
+	world.Time().Scale = 0.5 // slow motion
+
+# Tags
+
+Entities can be categorized with data-less tags, and looked up by tag:
+
+	world.Tag(enemy, "enemy")
+
+	for _, e := range world.EntitiesWithTag("enemy") {
+		// ...
+	}
+
+# Groups
+
+Entities sharing a lifetime, such as the entities of a level, can be gathered in a group
+and despawned in one call. The entities registered within the scope of a group join it:
+
+	level := world.Group("level1")
+	level.Scope(func() {
+		// load the level
+	})
+
+	// later, when the level unloads
+	level.Despawn()
+
+# Debugging
+
+During development, a running game can be inspected from a browser. ServeDebug serves the entities,
+their components, the measures of the systems and the last events as JSON, with a small web UI:
+
+	if debug {
+		server, err := world.ServeDebug("localhost:6060")
+		// ...
+	}
 */
 package ecs
@@ -0,0 +1,51 @@
+package ebitenecs
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Camera is the point of view of the world pass: the position of the world shown at the center of the screen,
+// the zoom factor, and the rotation in radians.
+type Camera struct {
+	X        float64
+	Y        float64
+	Zoom     float64
+	Rotation float64
+}
+
+// zoom returns the zoom factor of the camera, a zero zoom meaning no zoom.
+func (c *Camera) zoom() float64 {
+	if c.Zoom == 0 {
+		return 1
+	}
+
+	return c.Zoom
+}
+
+// GeoM returns the transform from world coordinates to the coordinates of a screen of the given size.
+func (c *Camera) GeoM(screenWidth, screenHeight int) ebiten.GeoM {
+	var g ebiten.GeoM
+
+	g.Translate(-c.X, -c.Y)
+	g.Rotate(-c.Rotation)
+	g.Scale(c.zoom(), c.zoom())
+	g.Translate(float64(screenWidth)/2, float64(screenHeight)/2)
+
+	return g
+}
+
+// WorldToScreen returns the position on a screen of the given size of a position in the world.
+func (c *Camera) WorldToScreen(x, y float64, screenWidth, screenHeight int) (float64, float64) {
+	g := c.GeoM(screenWidth, screenHeight)
+
+	return g.Apply(x, y)
+}
+
+// ScreenToWorld returns the position in the world of a position on a screen of the given size,
+// e.g. to find what is under the cursor.
+func (c *Camera) ScreenToWorld(x, y float64, screenWidth, screenHeight int) (float64, float64) {
+	g := c.GeoM(screenWidth, screenHeight)
+	g.Invert()
+
+	return g.Apply(x, y)
+}
@@ -0,0 +1,56 @@
+package ebitenecs
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"github.com/jtbonhomme/ebiten-ecs/component"
+	"github.com/jtbonhomme/ebiten-ecs/system"
+)
+
+// funcDrawer is a drawer of the screen pass calling a function.
+type funcDrawer struct {
+	id system.ID
+	fn func(*ebiten.Image, []component.Component)
+}
+
+// DrawerFunc returns a drawer of the screen pass, with a new unique ID, calling fn for each of its entities.
+func DrawerFunc(fn func(screen *ebiten.Image, c []component.Component)) Drawer {
+	return &funcDrawer{
+		id: system.AssignID(),
+		fn: fn,
+	}
+}
+
+// ID returns the unique ID of the drawer.
+func (d *funcDrawer) ID() system.ID {
+	return d.id
+}
+
+// Draw calls the function of the drawer.
+func (d *funcDrawer) Draw(screen *ebiten.Image, c []component.Component) {
+	d.fn(screen, c)
+}
+
+// funcWorldDrawer is a drawer of the world pass calling a function.
+type funcWorldDrawer struct {
+	id system.ID
+	fn func(*ebiten.Image, ebiten.GeoM, []component.Component)
+}
+
+// WorldDrawerFunc returns a drawer of the world pass, with a new unique ID, calling fn for each of its entities.
+func WorldDrawerFunc(fn func(screen *ebiten.Image, camera ebiten.GeoM, c []component.Component)) WorldDrawer {
+	return &funcWorldDrawer{
+		id: system.AssignID(),
+		fn: fn,
+	}
+}
+
+// ID returns the unique ID of the drawer.
+func (d *funcWorldDrawer) ID() system.ID {
+	return d.id
+}
+
+// DrawWorld calls the function of the drawer.
+func (d *funcWorldDrawer) DrawWorld(screen *ebiten.Image, camera ebiten.GeoM, c []component.Component) {
+	d.fn(screen, camera, c)
+}
@@ -0,0 +1,40 @@
+package ebitenecs
+
+import (
+	"io"
+	"sort"
+
+	"github.com/jtbonhomme/ebiten-ecs/system"
+)
+
+// DumpGraph writes a DOT graph of the architecture of the world (see ecs.ECS.DumpGraph), including the drawers
+// of the world pass, of the views and of the screen pass, in the order they draw.
+func (w *World) DumpGraph(out io.Writer) error {
+	drawers := appendDrawers(nil, w.worldDrawers, w.worldZIndexes)
+
+	names := make([]string, 0, len(w.views))
+	for name := range w.views {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		drawers = appendDrawers(drawers, w.views[name].drawers, w.views[name].zIndexes)
+	}
+
+	drawers = appendDrawers(drawers, w.drawers, w.zIndexes)
+
+	return w.ECS.DumpGraph(out, drawers...)
+}
+
+// appendDrawers appends drawers to a list of systems, by increasing z-index.
+func appendDrawers[D system.System](systems []system.System, drawers map[int][]D, zIndexes []int) []system.System {
+	for _, z := range zIndexes {
+		for _, d := range drawers[z] {
+			systems = append(systems, d)
+		}
+	}
+
+	return systems
+}
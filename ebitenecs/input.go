@@ -0,0 +1,32 @@
+package ebitenecs
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+
+	"github.com/jtbonhomme/ebiten-ecs/input"
+)
+
+// Capture is the input source of the worlds created with New: it fills the snapshot with the current state
+// of the Ebiten input devices, reusing its slices.
+func Capture(s *input.Snapshot) {
+	keys = inpututil.AppendPressedKeys(keys[:0])
+
+	s.Keys = s.Keys[:0]
+	for _, k := range keys {
+		s.Keys = append(s.Keys, input.Key(k))
+	}
+
+	s.MouseButtons = s.MouseButtons[:0]
+	for b := ebiten.MouseButton0; b <= ebiten.MouseButtonMax; b++ {
+		if ebiten.IsMouseButtonPressed(b) {
+			s.MouseButtons = append(s.MouseButtons, input.MouseButton(b))
+		}
+	}
+
+	s.CursorX, s.CursorY = ebiten.CursorPosition()
+	s.WheelX, s.WheelY = ebiten.Wheel()
+}
+
+// keys is the buffer of the pressed Ebiten keys, reused by Capture which only runs on the game loop.
+var keys []ebiten.Key
@@ -0,0 +1,110 @@
+package ebitenecs
+
+import (
+	"sort"
+
+	"github.com/jtbonhomme/ebiten-ecs/system"
+)
+
+// Prioritizer is implemented by drawers which need to be drawn before or after the other drawers of their z-index.
+// Drawers of a z-index are drawn by increasing priority, then in the order they were registered in.
+// Drawers which do not implement it have a priority of 0.
+type Prioritizer interface {
+	DrawPriority() int
+}
+
+// drawPriority returns the priority of a drawer within its z-index.
+func drawPriority(d system.System) int {
+	if p, ok := d.(Prioritizer); ok {
+		return p.DrawPriority()
+	}
+
+	return 0
+}
+
+// insertDrawer inserts a drawer after the drawers of lower or equal priority,
+// so that drawers of equal priority keep their registration order.
+func insertDrawer[D system.System](drawers []D, d D) []D {
+	p := drawPriority(d)
+	i := sort.Search(len(drawers), func(i int) bool {
+		return drawPriority(drawers[i]) > p
+	})
+
+	var zero D
+
+	drawers = append(drawers, zero)
+	copy(drawers[i+1:], drawers[i:])
+	drawers[i] = d
+
+	return drawers
+}
+
+// removeDrawer removes the drawer with the given ID, keeping the order of the other drawers.
+// It returns the removed drawer, if any.
+func removeDrawer[D system.System](drawers []D, id system.ID) ([]D, system.System, bool) {
+	for i, d := range drawers {
+		if d.ID() == id {
+			var zero D
+
+			copy(drawers[i:], drawers[i+1:])
+			drawers[len(drawers)-1] = zero
+
+			return drawers[:len(drawers)-1], d, true
+		}
+	}
+
+	return drawers, nil, false
+}
+
+// removeZIndex removes a z-index from a sorted slice of z-indexes.
+func removeZIndex(zIndexes []int, zIndex int) []int {
+	i := sort.SearchInts(zIndexes, zIndex)
+	if i == len(zIndexes) || zIndexes[i] != zIndex {
+		return zIndexes
+	}
+
+	return append(zIndexes[:i], zIndexes[i+1:]...)
+}
+
+// UnregisterDrawer removes the drawer with the given ID from the world, in either pass, and shuts it down
+// if it implements ecs.Finalizer. The other drawers of its z-index keep their order.
+// It returns false if there is no such drawer.
+func (w *World) UnregisterDrawer(id system.ID) bool {
+	for z, drawers := range w.drawers {
+		drawers, removed, ok := removeDrawer(drawers, id)
+		if !ok {
+			continue
+		}
+
+		w.drawers[z] = drawers
+		if len(drawers) == 0 {
+			delete(w.drawers, z)
+			w.zIndexes = removeZIndex(w.zIndexes, z)
+		}
+
+		w.ReleaseSystems(1)
+		w.ShutdownSystem(removed)
+
+		return true
+	}
+
+	for z, drawers := range w.worldDrawers {
+		drawers, removed, ok := removeDrawer(drawers, id)
+		if !ok {
+			continue
+		}
+
+		w.worldDrawers[z] = drawers
+		if len(drawers) == 0 {
+			delete(w.worldDrawers, z)
+			w.worldZIndexes = removeZIndex(w.worldZIndexes, z)
+		}
+
+		w.ReleaseSystems(1)
+		w.ShutdownSystem(removed)
+
+		return true
+	}
+
+	return false
+}
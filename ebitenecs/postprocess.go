@@ -0,0 +1,102 @@
+package ebitenecs
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// PostEffect is a post-processing effect (bloom, CRT, palette...) applied to the image of the world pass.
+// It draws the processed src image to dst, which is cleared and has the same size as src.
+type PostEffect interface {
+	Apply(dst, src *ebiten.Image)
+}
+
+// PostEffectFunc is a post-processing effect written as a Go function.
+type PostEffectFunc func(dst, src *ebiten.Image)
+
+// Apply calls the function.
+func (f PostEffectFunc) Apply(dst, src *ebiten.Image) {
+	f(dst, src)
+}
+
+// ShaderEffect is a post-processing effect running a Kage shader on the image of the world pass,
+// given to the shader as its first image. Its uniforms can be changed between frames.
+type ShaderEffect struct {
+	Shader   *ebiten.Shader
+	Uniforms map[string]interface{}
+	op       ebiten.DrawRectShaderOptions
+}
+
+// NewShaderEffect creates a post-processing effect running the shader with the given uniforms, which may be nil.
+func NewShaderEffect(shader *ebiten.Shader, uniforms map[string]interface{}) *ShaderEffect {
+	if uniforms == nil {
+		uniforms = make(map[string]interface{})
+	}
+
+	return &ShaderEffect{
+		Shader:   shader,
+		Uniforms: uniforms,
+	}
+}
+
+// Apply runs the shader on src, drawing the result to dst.
+func (e *ShaderEffect) Apply(dst, src *ebiten.Image) {
+	e.op.Images[0] = src
+	e.op.Uniforms = e.Uniforms
+
+	size := src.Bounds().Size()
+	dst.DrawRectShader(size.X, size.Y, e.Shader, &e.op)
+
+	e.op.Images[0] = nil
+}
+
+// AddPostEffect appends an effect to the chain of post-processing effects.
+// When there is at least one effect, the world pass is drawn to an offscreen image, processed by each effect
+// in order, then drawn to the screen before the screen pass, which is not processed.
+func (w *World) AddPostEffect(e PostEffect) {
+	w.postEffects = append(w.postEffects, e)
+}
+
+// ClearPostEffects removes every post-processing effect.
+func (w *World) ClearPostEffects() {
+	w.postEffects = w.postEffects[:0]
+}
+
+// PostEffects returns the chain of post-processing effects.
+func (w *World) PostEffects() []PostEffect {
+	return w.postEffects
+}
+
+// postBuffer returns an offscreen image of the size of the screen, reallocating it when the screen size changes.
+func postBuffer(buffer, screen *ebiten.Image) *ebiten.Image {
+	size := screen.Bounds().Size()
+	if buffer != nil && buffer.Bounds().Size() == size {
+		buffer.Clear()
+		return buffer
+	}
+
+	if buffer != nil {
+		buffer.Deallocate()
+	}
+
+	return ebiten.NewImage(size.X, size.Y)
+}
+
+// postProcess runs the chain of post-processing effects on the image of the world pass, drawn in w.post[0],
+// then draws the result to the screen.
+func (w *World) postProcess(screen *ebiten.Image) {
+	src := w.post[0]
+
+	for _, e := range w.postEffects {
+		dst := w.post[1]
+		if src == w.post[1] {
+			dst = w.post[0]
+		}
+
+		dst.Clear()
+		e.Apply(dst, src)
+		src = dst
+	}
+
+	w.postOp.GeoM.Reset()
+	screen.DrawImage(src, &w.postOp)
+}
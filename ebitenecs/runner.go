@@ -0,0 +1,123 @@
+package ebitenecs
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// LayoutFunc computes the logical resolution of the screen from the size of the window, see ebiten.Game.Layout.
+type LayoutFunc func(outsideWidth, outsideHeight int) (screenWidth, screenHeight int)
+
+// Fixed returns a layout with a fixed logical resolution: Ebiten scales the screen to the window,
+// keeping its aspect ratio.
+func Fixed(width, height int) LayoutFunc {
+	return func(int, int) (int, int) {
+		return width, height
+	}
+}
+
+// Outside returns a layout whose logical resolution is the size of the window,
+// so that the screen is never scaled and the game sees more of the world in larger windows.
+func Outside() LayoutFunc {
+	return func(outsideWidth, outsideHeight int) (int, int) {
+		return outsideWidth, outsideHeight
+	}
+}
+
+// Runner wraps a world so that it satisfies ebiten.Game, for games which do not need a Game struct of their own.
+// User code can be hooked before and after the world is updated, and after it is drawn.
+type Runner struct {
+	world        *World
+	layout       LayoutFunc
+	width        int
+	height       int
+	beforeUpdate func(world *World) error
+	afterUpdate  func(world *World) error
+	afterDraw    func(world *World, screen *ebiten.Image)
+}
+
+// NewRunner creates a runner for the world, with a fixed logical resolution of width x height pixels.
+func NewRunner(world *World, width, height int) *Runner {
+	return &Runner{
+		world:  world,
+		layout: Fixed(width, height),
+		width:  width,
+		height: height,
+	}
+}
+
+// World returns the world run by the runner.
+func (r *Runner) World() *World {
+	return r.world
+}
+
+// SetLayout changes the way the logical resolution is computed from the size of the window.
+func (r *Runner) SetLayout(layout LayoutFunc) {
+	r.layout = layout
+}
+
+// Size returns the logical resolution of the screen, as computed by the last call to Layout.
+func (r *Runner) Size() (int, int) {
+	return r.width, r.height
+}
+
+// BeforeUpdate sets a function called at each Update before the world is updated.
+// An error returned by the function stops the game.
+func (r *Runner) BeforeUpdate(fn func(world *World) error) {
+	r.beforeUpdate = fn
+}
+
+// AfterUpdate sets a function called at each Update after the world is updated.
+// An error returned by the function stops the game.
+func (r *Runner) AfterUpdate(fn func(world *World) error) {
+	r.afterUpdate = fn
+}
+
+// AfterDraw sets a function called at each Draw after the world is drawn, to draw on top of it (HUD, debug...).
+func (r *Runner) AfterDraw(fn func(world *World, screen *ebiten.Image)) {
+	r.afterDraw = fn
+}
+
+// Update implements ebiten.Game: it runs the hooks and updates the world.
+func (r *Runner) Update() error {
+	if r.beforeUpdate != nil {
+		err := r.beforeUpdate(r.world)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := r.world.Update()
+	if err != nil {
+		return err
+	}
+
+	if r.afterUpdate != nil {
+		return r.afterUpdate(r.world)
+	}
+
+	return nil
+}
+
+// Draw implements ebiten.Game: it draws the world, then runs the draw hook.
+func (r *Runner) Draw(screen *ebiten.Image) {
+	r.world.Draw(screen)
+
+	if r.afterDraw != nil {
+		r.afterDraw(r.world, screen)
+	}
+}
+
+// Layout implements ebiten.Game: it returns the logical resolution computed by the layout of the runner.
+func (r *Runner) Layout(outsideWidth, outsideHeight int) (int, int) {
+	r.width, r.height = r.layout(outsideWidth, outsideHeight)
+
+	return r.width, r.height
+}
+
+// Run runs the game loop with the runner as the game. It returns when the game ends, see ebiten.RunGame,
+// once the world is shut down.
+func (r *Runner) Run() error {
+	defer r.world.Shutdown()
+
+	return ebiten.RunGame(r)
+}
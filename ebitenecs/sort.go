@@ -0,0 +1,128 @@
+package ebitenecs
+
+import (
+	"cmp"
+	"slices"
+
+	ecs "github.com/jtbonhomme/ebiten-ecs"
+	"github.com/jtbonhomme/ebiten-ecs/component"
+	"github.com/jtbonhomme/ebiten-ecs/entity"
+)
+
+// SortKey is the component giving the draw order of an entity within a sorted layer,
+// usually the Y position of its feet in a top-down game. Entities without one are drawn first.
+type SortKey struct {
+	Value float64
+}
+
+// sortKeyType is the handle used to find the SortKey component.
+var sortKeyType = component.NewType[SortKey]()
+
+// SortFunc returns the key entities are drawn by, in increasing order, within a sorted layer.
+type SortFunc func(c []component.Component) float64
+
+// BySortKey sorts entities by their SortKey component.
+func BySortKey(c []component.Component) float64 {
+	k := sortKeyType.Get(c)
+	if k == nil {
+		return 0
+	}
+
+	return k.Value
+}
+
+// SortLayer sorts the entities of the layer at the given z-index, in both passes, by the key returned by the function
+// (BySortKey if nil) each frame, whatever drawer draws them: a character can then be drawn either in front of
+// or behind a tree drawn by another drawer. Entities with the same key are drawn in the order of the drawers.
+// Batched drawers still draw their whole batch at the end of the layer.
+func (w *World) SortLayer(zIndex int, key SortFunc) {
+	if key == nil {
+		key = BySortKey
+	}
+
+	w.sorted[zIndex] = key
+}
+
+// sortItem is an entity to draw in a sorted layer.
+type sortItem struct {
+	key         float64
+	id          entity.ID
+	drawer      Drawer
+	worldDrawer WorldDrawer
+	components  []component.Component
+}
+
+// compareSortItems orders the entities of a sorted layer by increasing key.
+func compareSortItems(a, b sortItem) int {
+	return cmp.Compare(a.key, b.key)
+}
+
+// collectOne adds an entity of the running drawer to the entities of the sorted layer.
+func (w *World) collectOne(id entity.ID, components []component.Component) {
+	w.sortItems = append(w.sortItems, sortItem{
+		key:         w.sortKey(components),
+		id:          id,
+		drawer:      w.drawer,
+		worldDrawer: w.worldDrawer,
+		components:  components,
+	})
+}
+
+// drawSorted draws the entities of a sorted layer, made of either screen drawers or world drawers, by increasing key.
+// Each call uses its own buffer of entities, so that a view with sorted layers can be drawn from a drawer of a sorted layer.
+func (w *World) drawSorted(drawers []Drawer, worldDrawers []WorldDrawer, key SortFunc) {
+	prevDrawer, prevWorldDrawer, prevKey, prevItems := w.drawer, w.worldDrawer, w.sortKey, w.sortItems
+	w.sortKey = key
+
+	if len(w.sortBuffers) == w.sortDepth {
+		w.sortBuffers = append(w.sortBuffers, nil)
+	}
+
+	w.sortItems = w.sortBuffers[w.sortDepth][:0]
+	w.sortDepth++
+
+	w.worldDrawer = nil
+	for _, d := range drawers {
+		w.drawer = d
+		w.Process(d, ecs.PhaseDraw, w.collectEntity)
+	}
+
+	w.drawer = nil
+	for _, d := range worldDrawers {
+		w.worldDrawer = d
+		w.Process(d, ecs.PhaseDraw, w.collectEntity)
+	}
+
+	items := w.sortItems
+	slices.SortStableFunc(items, compareSortItems)
+
+	for i := range items {
+		item := &items[i]
+		w.drawer, w.worldDrawer = item.drawer, item.worldDrawer
+
+		if item.worldDrawer != nil {
+			w.ProcessEntity(item.worldDrawer, ecs.PhaseDraw, item.id, item.components, w.drawWorldEntity)
+		} else {
+			w.ProcessEntity(item.drawer, ecs.PhaseDraw, item.id, item.components, w.drawEntity)
+		}
+
+		*item = sortItem{}
+	}
+
+	for _, d := range drawers {
+		if b, ok := d.(Batcher); ok {
+			b.Flush(w.screen)
+		}
+	}
+
+	for _, d := range worldDrawers {
+		if b, ok := d.(Batcher); ok {
+			b.Flush(w.screen)
+		}
+	}
+
+	w.sortDepth--
+	w.sortBuffers[w.sortDepth] = items[:0]
+	w.sortKey, w.sortItems = prevKey, prevItems
+	w.drawer, w.worldDrawer = prevDrawer, prevWorldDrawer
+}
@@ -0,0 +1,82 @@
+package ebitenecs
+
+import (
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"github.com/jtbonhomme/ebiten-ecs/entity"
+	"github.com/jtbonhomme/ebiten-ecs/system"
+)
+
+// View is an auxiliary logical surface of the world (map editor palette, debug inspector...),
+// rendered to its own image by its own set of drawers, so that tooling does not fight the game
+// for screen space. The image of a view can then be drawn anywhere on screen, or shown in a window of its own.
+type View struct {
+	name     string
+	world    *World
+	drawers  map[int][]Drawer
+	zIndexes []int
+	image    *ebiten.Image
+}
+
+// NewView creates a view of width x height pixels with no drawer.
+// The method panics if a view with the same name already exists.
+func (w *World) NewView(name string, width, height int) *View {
+	if _, ok := w.views[name]; ok {
+		panic(fmt.Sprintf("the view %q you are trying to create already exists", name))
+	}
+
+	v := &View{
+		name:    name,
+		world:   w,
+		drawers: make(map[int][]Drawer),
+		image:   ebiten.NewImage(width, height),
+	}
+	w.views[name] = v
+
+	return v
+}
+
+// View returns the view with the given name, or nil if there is none.
+func (w *World) View(name string) *View {
+	return w.views[name]
+}
+
+// Name returns the name of the view.
+func (v *View) Name() string {
+	return v.name
+}
+
+// RegisterDrawer registers a drawer drawing into this view only, with the entities it draws.
+// It returns the ID of the drawer, see system.EnsureID.
+func (v *View) RegisterDrawer(s Drawer, zIndex int, e ...entity.Entity) system.ID {
+	id := system.EnsureID(s)
+	v.world.ReserveSystems(1)
+
+	_, ok := v.drawers[zIndex]
+	if !ok {
+		v.drawers[zIndex] = []Drawer{}
+		v.zIndexes = insertZIndex(v.zIndexes, zIndex)
+	}
+
+	v.drawers[zIndex] = insertDrawer(v.drawers[zIndex], s)
+	v.world.Associate(s, e...)
+	v.world.InitSystem(s)
+
+	return id
+}
+
+// Draw clears the image of the view and draws the drawers of the view into it.
+// It returns the image of the view.
+func (v *View) Draw() *ebiten.Image {
+	v.image.Clear()
+	v.world.draw(v.image, v.drawers, v.zIndexes)
+
+	return v.image
+}
+
+// Image returns the image of the view, as rendered by the last call to Draw.
+func (v *View) Image() *ebiten.Image {
+	return v.image
+}
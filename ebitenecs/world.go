@@ -0,0 +1,377 @@
+// Package ebitenecs binds the ECS world to Ebiten: it adds the drawers, the views, the input devices
+// and the debug keys to the pure simulation provided by the ecs package.
+package ebitenecs
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+
+	ecs "github.com/jtbonhomme/ebiten-ecs"
+	"github.com/jtbonhomme/ebiten-ecs/component"
+	"github.com/jtbonhomme/ebiten-ecs/entity"
+	"github.com/jtbonhomme/ebiten-ecs/system"
+)
+
+// Drawer is an interface that represents a system that draws entities in the ECS architecture.
+type Drawer interface {
+	system.System
+	Draw(*ebiten.Image, []component.Component)
+}
+
+// WorldDrawer is a drawer of the world pass: it draws entities in world coordinates, and is given
+// the transform of the camera to apply to them, e.g. with op.GeoM.Concat(camera).
+type WorldDrawer interface {
+	system.System
+	DrawWorld(screen *ebiten.Image, camera ebiten.GeoM, c []component.Component)
+}
+
+// Batcher is implemented by drawers drawing their entities in batches instead of one by one:
+// Flush is called once all the entities of the drawer have been drawn, to draw the batch on screen.
+type Batcher interface {
+	Flush(screen *ebiten.Image)
+}
+
+// World is an ECS world drawn with Ebiten.
+// It embeds the simulation, so every method of ecs.ECS is available on it.
+//
+// The world is drawn in two passes: the world pass, in which the world drawers draw the game
+// from the point of view of the camera, then the screen pass, in which the drawers draw in screen
+// coordinates (HUD, UI...), unaffected by the camera.
+type World struct {
+	*ecs.ECS
+	drawers       map[int][]Drawer
+	zIndexes      []int
+	worldDrawers  map[int][]WorldDrawer
+	worldZIndexes []int
+	camera        Camera
+	postEffects   []PostEffect
+	post          [2]*ebiten.Image
+	postOp        ebiten.DrawImageOptions
+	sorted        map[int]SortFunc
+	views         map[string]*View
+	pauseKey      ebiten.Key
+	stepKey       ebiten.Key
+
+	// state of the running draw, kept here with the method values below so that Draw does not allocate
+	screen          *ebiten.Image
+	drawer          Drawer
+	worldDrawer     WorldDrawer
+	geoM            ebiten.GeoM
+	drawFrame       func(*ecs.ECS)
+	drawEntity      func(entity.ID, []component.Component)
+	drawWorldEntity func(entity.ID, []component.Component)
+	collectEntity   func(entity.ID, []component.Component)
+	sortItems       []sortItem
+	sortBuffers     [][]sortItem
+	sortDepth       int
+	sortKey         SortFunc
+}
+
+// New creates a new world configured by the given options, capturing its input from the Ebiten input devices.
+func New(opts ...ecs.Option) *World {
+	world := ecs.New(opts...)
+
+	w := &World{
+		ECS:          world,
+		drawers:      make(map[int][]Drawer, world.MaxDrawers()),
+		worldDrawers: make(map[int][]WorldDrawer),
+		sorted:       make(map[int]SortFunc),
+		views:        make(map[string]*View),
+		pauseKey:     -1,
+		stepKey:      -1,
+	}
+
+	w.drawFrame = w.drawScreen
+	w.drawEntity = w.drawOne
+	w.drawWorldEntity = w.drawWorldOne
+	w.collectEntity = w.collectOne
+	w.SetInputSource(Capture)
+
+	return w
+}
+
+// RegisterDrawer registers a drawer of the screen pass at the given z-index, with the entities it draws.
+// Drawers of a z-index are drawn in the order they were registered in, unless they implement Prioritizer.
+// The drawer is initialized if it implements ecs.Initializer. It returns the ID of the drawer, see system.EnsureID.
+func (w *World) RegisterDrawer(s Drawer, zIndex int, e ...entity.Entity) system.ID {
+	id := system.EnsureID(s)
+	w.ReserveSystems(1)
+
+	_, ok := w.drawers[zIndex]
+	if !ok {
+		w.drawers[zIndex] = []Drawer{}
+		w.zIndexes = insertZIndex(w.zIndexes, zIndex)
+	}
+
+	w.drawers[zIndex] = insertDrawer(w.drawers[zIndex], s)
+	w.Associate(s, e...)
+	w.InitSystem(s)
+
+	return id
+}
+
+// Drawers returns the map of registered drawers of the screen pass in the world.
+func (w *World) Drawers() map[int][]Drawer {
+	return w.drawers
+}
+
+// RegisterWorldDrawer registers a drawer of the world pass at the given z-index, with the entities it draws.
+// It returns the ID of the drawer, see system.EnsureID.
+func (w *World) RegisterWorldDrawer(s WorldDrawer, zIndex int, e ...entity.Entity) system.ID {
+	id := system.EnsureID(s)
+	w.ReserveSystems(1)
+
+	_, ok := w.worldDrawers[zIndex]
+	if !ok {
+		w.worldDrawers[zIndex] = []WorldDrawer{}
+		w.worldZIndexes = insertZIndex(w.worldZIndexes, zIndex)
+	}
+
+	w.worldDrawers[zIndex] = insertDrawer(w.worldDrawers[zIndex], s)
+	w.Associate(s, e...)
+	w.InitSystem(s)
+
+	return id
+}
+
+// WorldDrawers returns the map of registered drawers of the world pass in the world.
+func (w *World) WorldDrawers() map[int][]WorldDrawer {
+	return w.worldDrawers
+}
+
+// Shutdown shuts down the drawers of the screen pass, of the views and of the world pass, from the top z-index
+// to the bottom one, then the updaters of the world (see ecs.ECS.Shutdown).
+func (w *World) Shutdown() {
+	shutdownDrawers(w.ECS, w.drawers, w.zIndexes)
+
+	names := make([]string, 0, len(w.views))
+	for name := range w.views {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		shutdownDrawers(w.ECS, w.views[name].drawers, w.views[name].zIndexes)
+	}
+
+	shutdownDrawers(w.ECS, w.worldDrawers, w.worldZIndexes)
+	w.ECS.Shutdown()
+}
+
+// shutdownDrawers shuts drawers down in the reverse order of drawing.
+func shutdownDrawers[D system.System](world *ecs.ECS, drawers map[int][]D, zIndexes []int) {
+	for i := len(zIndexes) - 1; i >= 0; i-- {
+		layer := drawers[zIndexes[i]]
+		for j := len(layer) - 1; j >= 0; j-- {
+			world.ShutdownSystem(layer[j])
+		}
+	}
+}
+
+// Camera returns the camera of the world pass.
+func (w *World) Camera() *Camera {
+	return &w.camera
+}
+
+// Update handles the debug keys, then updates the simulation (see ecs.ECS.Update).
+func (w *World) Update() error {
+	w.handleDebugKeys()
+
+	return w.ECS.Update()
+}
+
+// Draw iterates through the registered drawers and draws the active entities associated with them:
+// the drawers of the world pass first, then the drawers of the screen pass.
+// In each pass, drawers are called by increasing z-index. Draw does not allocate memory.
+func (w *World) Draw(screen *ebiten.Image) {
+	w.screen = screen
+	w.Do(w.drawFrame)
+	w.screen = nil
+}
+
+// drawScreen draws the registered drawers on the screen, with the world locked.
+func (w *World) drawScreen(*ecs.ECS) {
+	w.BeginPhase(ecs.PhaseDraw)
+
+	screen := w.screen
+	if len(w.postEffects) > 0 {
+		w.post[0] = postBuffer(w.post[0], screen)
+		w.post[1] = postBuffer(w.post[1], screen)
+		w.screen = w.post[0]
+	}
+
+	size := w.screen.Bounds().Size()
+	w.geoM = w.camera.GeoM(size.X, size.Y)
+
+	for _, i := range w.worldZIndexes {
+		if key, ok := w.sorted[i]; ok {
+			w.drawSorted(nil, w.worldDrawers[i], key)
+			continue
+		}
+
+		for _, d := range w.worldDrawers[i] {
+			w.worldDrawer = d
+			w.Process(d, ecs.PhaseDraw, w.drawWorldEntity)
+
+			if b, ok := d.(Batcher); ok {
+				b.Flush(w.screen)
+			}
+		}
+	}
+
+	w.worldDrawer = nil
+
+	if len(w.postEffects) > 0 {
+		w.screen = screen
+		w.postProcess(screen)
+	}
+
+	w.draw(w.screen, w.drawers, w.zIndexes)
+}
+
+// drawWorldOne runs the running world drawer on an entity.
+func (w *World) drawWorldOne(_ entity.ID, components []component.Component) {
+	w.worldDrawer.DrawWorld(w.screen, w.geoM, components)
+}
+
+// insertZIndex inserts a z-index in a sorted slice of z-indexes.
+// Keeping the z-indexes sorted at registration time avoids sorting them every frame.
+func insertZIndex(zIndexes []int, zIndex int) []int {
+	i := sort.SearchInts(zIndexes, zIndex)
+	zIndexes = append(zIndexes, 0)
+	copy(zIndexes[i+1:], zIndexes[i:])
+	zIndexes[i] = zIndex
+
+	return zIndexes
+}
+
+// draw calls the given drawers by increasing z-index, with the active entities associated with them.
+// The running draw state is restored afterwards, so that a view can be drawn from a drawer.
+func (w *World) draw(screen *ebiten.Image, drawers map[int][]Drawer, zIndexes []int) {
+	prevScreen, prevDrawer := w.screen, w.drawer
+	w.screen = screen
+
+	// https://go.dev/blog/maps - Iteration order
+	for _, i := range zIndexes {
+		if key, ok := w.sorted[i]; ok {
+			w.drawSorted(drawers[i], nil, key)
+			continue
+		}
+
+		for _, d := range drawers[i] {
+			w.drawer = d
+			w.Process(d, ecs.PhaseDraw, w.drawEntity)
+
+			if b, ok := d.(Batcher); ok {
+				b.Flush(w.screen)
+			}
+		}
+	}
+
+	w.screen, w.drawer = prevScreen, prevDrawer
+}
+
+// drawOne runs the running drawer on an entity.
+func (w *World) drawOne(_ entity.ID, components []component.Component) {
+	w.drawer.Draw(w.screen, components)
+}
+
+// SetDebugKeys binds keys to toggle the pause and to step the simulation, checked at each Update.
+// Passing -1 as a key disables its binding.
+func (w *World) SetDebugKeys(pause, step ebiten.Key) {
+	w.pauseKey, w.stepKey = pause, step
+}
+
+// handleDebugKeys toggles the pause or requests a step when the debug keys are pressed.
+func (w *World) handleDebugKeys() {
+	if w.pauseKey >= 0 && inpututil.IsKeyJustPressed(w.pauseKey) {
+		if w.Paused() {
+			w.Resume()
+		} else {
+			w.Pause()
+		}
+	}
+
+	if w.stepKey >= 0 && inpututil.IsKeyJustPressed(w.stepKey) {
+		w.Step()
+	}
+}
+
+// DrawProfiler draws the systems measures of the last frame on screen, at the given position.
+func (w *World) DrawProfiler(screen *ebiten.Image, x, y int) {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%-24s %10s %6s %6s %10s %6s %6s\n", "SYSTEM", "UPDATE", "ENT", "ALLOC", "DRAW", "ENT", "ALLOC")
+
+	for _, s := range w.Stats().Systems {
+		fmt.Fprintf(&b, "%-24.24s %10s %6d %6d %10s %6d %6d\n",
+			s.Name,
+			s.UpdateDuration.Round(time.Microsecond), s.UpdateEntities, s.UpdateAllocs,
+			s.DrawDuration.Round(time.Microsecond), s.DrawEntities, s.DrawAllocs)
+	}
+
+	ebitenutil.DebugPrintAt(screen, b.String(), x, y)
+}
+
+// AnalyzerWarnings returns the warnings of the world analyzer (see ecs.ECS.AnalyzerWarnings),
+// including drawer types registered several times with a single entity each.
+// It returns nil if the analyzer is not enabled.
+func (w *World) AnalyzerWarnings() []ecs.Warning {
+	warnings := w.ECS.AnalyzerWarnings()
+	if warnings == nil {
+		return nil
+	}
+
+	return append(warnings, w.drawerWarnings()...)
+}
+
+// drawerWarnings reports drawer types registered several times with a single entity each,
+// instead of once with all their entities.
+func (w *World) drawerWarnings() []ecs.Warning {
+	instances := make(map[reflect.Type]int)
+
+	for _, drawers := range w.drawers {
+		for _, d := range drawers {
+			if len(w.FilterEntities(d)) <= 1 {
+				instances[reflect.TypeOf(d)]++
+			}
+		}
+	}
+
+	for _, drawers := range w.worldDrawers {
+		for _, d := range drawers {
+			if len(w.FilterEntities(d)) <= 1 {
+				instances[reflect.TypeOf(d)]++
+			}
+		}
+	}
+
+	warnings := []ecs.Warning{}
+
+	for t, n := range instances {
+		if n < 2 {
+			continue
+		}
+
+		warnings = append(warnings, ecs.Warning{
+			Kind:  "drawer-per-entity",
+			Count: n,
+			Message: fmt.Sprintf("drawer type %s is registered %d times with a single entity, "+
+				"register one drawer per type with all its entities", t, n),
+		})
+	}
+
+	sort.Slice(warnings, func(i, j int) bool {
+		return warnings[i].Message < warnings[j].Message
+	})
+
+	return warnings
+}
@@ -2,16 +2,21 @@ package ecs
 
 import (
 	"fmt"
+	"math/rand/v2"
+	"os"
 	"reflect"
-	"sort"
-
-	"github.com/hajimehoshi/ebiten/v2"
+	"slices"
+	"sync"
+	"time"
 
 	"github.com/jtbonhomme/ebiten-ecs/component"
 	"github.com/jtbonhomme/ebiten-ecs/entity"
+	"github.com/jtbonhomme/ebiten-ecs/input"
 	"github.com/jtbonhomme/ebiten-ecs/system"
 )
 
+// Default sizes of the registries, which can be changed with the WithMaxEntities, WithMaxSystems
+// and WithMaxDrawers options.
 const (
 	MaxEntities int = 512
 	MaxSystems  int = 256
@@ -19,38 +24,182 @@ const (
 )
 
 // ECS is the main structure for the Entity-Component-System architecture.
-// It provides methods to register and unregister entities, components, updaters, and drawers.
+// It provides methods to register and unregister entities, components, updaters and resources.
 type ECS struct {
 	updaters           []system.Updater
-	drawers            map[int][]system.Drawer
+	unsorted           bool
 	entitiesRegistry   map[system.ID][]entity.Entity
 	componentsRegistry map[entity.ID][]component.Component
+	resources          map[reflect.Type]interface{}
+	taggedEntities     map[string][]entity.Entity
+	entityTags         map[entity.ID]map[string]struct{}
+	namedEntities      map[string]entity.Entity
+	entityNames        map[entity.ID]string
+	inactiveEntities   map[entity.ID]struct{}
+	groups             map[string]*Group
+	scopes             []*Group
+	changes            map[component.TypeID]map[entity.ID]uint64
+	changeVersion      uint64
+	spawnHooks         []registeredHook
+	despawnHooks       []registeredHook
+	hookID             HookID
+	systems            int
+	initErr            error
+	crashErr           error
+	timeScale          float64
+	fastForward        int
+	repeating          bool
+	refsTo             map[entity.ID][]refLink
+	refsHeld           map[entity.ID][]refLink
+	dying              map[entity.ID]struct{}
+	iterating          int
+	sharedLists        map[system.ID]struct{}
+	sharedTags         map[string]struct{}
+	counters           counters
+	dyingOrder         []entity.ID
+	died               []Died
+	startups           []Startup
+	lastStep           time.Time
+	dt                 time.Duration
+	elapsed            time.Duration
+	conditions         map[system.ID][]Condition
+	systemGroups       map[string]*SystemGroup
+	groupOf            map[system.ID]*SystemGroup
+	structure          uint64
+	all                query
+	arenas             map[reflect.Type]frameArena
+	stores             []entityStore
+	analyzer           *analyzer
+	profiler           *profiler
+	config             config
+	lastUpdate         time.Time
+	accumulator        time.Duration
+	paused             bool
+	steps              int
+	mutex              sync.Mutex
+	deferredMutex      sync.Mutex
+	deferred           []func(*ECS)
+	spare              []func(*ECS)
+	rand               *rand.Rand
+	pcg                *rand.PCG
+	streams            map[string]*randStream
+	seed               int64
+	tick               uint64
+	history            *history
+	input              *input.State
+	time               *Time
+	snapshot           input.Snapshot
+	inputSource        input.Source
+	recorder           *input.Recorder
+	recordFile         *os.File
+	player             *input.Player
 }
 
-// New creates a new ECS instance with initialized registries for entities and components.
-// It also initializes the updaters and drawers slices.
+// New creates a new ECS instance with initialized registries for entities and components,
+// configured by the given options.
+// It also initializes the updaters slice.
 // The ECS instance is ready to be used for managing entities and their components.
 // It is important to note that the ECS instance should be used in a single-threaded context
 // to avoid concurrent access issues.
 // The ECS instance is not thread-safe, and concurrent access to its methods may lead to undefined behavior.
 // It is recommended to use a single goroutine to manage the ECS instance and its entities.
 // This ensures that the ECS instance is used in a safe and predictable manner.
-func New() *ECS {
-	return &ECS{
+// Other goroutines can still safely queue work for the game loop with Defer, or access a world created
+// with the WithThreadSafe option through Do.
+func New(opts ...Option) *ECS {
+	cfg := defaultConfig()
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	ecs := &ECS{
 		updaters:           []system.Updater{},
-		drawers:            make(map[int][]system.Drawer, MaxDrawers),
-		entitiesRegistry:   make(map[system.ID][]entity.Entity, MaxSystems),
-		componentsRegistry: make(map[entity.ID][]component.Component, MaxEntities),
+		entitiesRegistry:   make(map[system.ID][]entity.Entity, cfg.maxSystems),
+		componentsRegistry: make(map[entity.ID][]component.Component, cfg.maxEntities),
+		resources:          make(map[reflect.Type]interface{}),
+		taggedEntities:     make(map[string][]entity.Entity),
+		sharedLists:        make(map[system.ID]struct{}),
+		sharedTags:         make(map[string]struct{}),
+		entityTags:         make(map[entity.ID]map[string]struct{}),
+		namedEntities:      make(map[string]entity.Entity),
+		entityNames:        make(map[entity.ID]string),
+		inactiveEntities:   make(map[entity.ID]struct{}),
+		groups:             make(map[string]*Group),
+		changes:            make(map[component.TypeID]map[entity.ID]uint64),
+		conditions:         make(map[system.ID][]Condition),
+		systemGroups:       make(map[string]*SystemGroup),
+		groupOf:            make(map[system.ID]*SystemGroup),
+		arenas:             make(map[reflect.Type]frameArena),
+		input:              &input.State{},
+		time:               &Time{Scale: 1, Speed: 1},
+		timeScale:          1,
+		refsTo:             make(map[entity.ID][]refLink),
+		refsHeld:           make(map[entity.ID][]refLink),
+		dying:              make(map[entity.ID]struct{}),
+		streams:            make(map[string]*randStream),
+		counters:           newCounters(),
+		fastForward:        1,
+		config:             cfg,
 	}
+
+	ecs.pcg = rand.NewPCG(0, 0)
+	ecs.rand = rand.New(ecs.pcg)
+	ecs.reseed(cfg.seed)
+	ecs.SetResource(ecs.rand)
+	ecs.SetResource(ecs.input)
+	ecs.SetResource(ecs.time)
+
+	if cfg.rollback > 0 {
+		ecs.history = newHistory(cfg.rollback)
+	}
+
+	return ecs
 }
 
 // RegisterEntity registers an entity and its components in the ECS.
-// It takes an entity and a variadic number of components as arguments.
+// It takes an entity and a variadic number of components as arguments: plain pointers to component structs,
+// or components created with component.New.
+//
+//	world.RegisterEntity(player, &Position{}, &Velocity{})
+//
 // The entity is assigned a unique ID, and the components are associated with the entity.
 // The components are stored in the components registry, which maps entity IDs to their respective components.
 // The method checks if the components are pointers to structs, and panics if they are not.
-func (ecs *ECS) RegisterEntity(e entity.Entity, components ...component.Component) {
-	for _, component := range components {
+// It panics with a *LimitError if the entity limit of the world is reached (see WithEntityLimit).
+// An entity has at most one component of each type: the method also panics if two components have the same type,
+// or if the entity already has a component of the type of a new one.
+// The entity is added to the group of the current scope, if any (see Group.Scope).
+func (ecs *ECS) RegisterEntity(e entity.Entity, data ...interface{}) {
+	ecs.RegisterComponents(e, component.NewSlice(data...)...)
+}
+
+// RegisterComponents registers an entity with components already created, e.g. by a Prefab, like RegisterEntity.
+func (ecs *ECS) RegisterComponents(e entity.Entity, components ...component.Component) {
+	ecs.scope(e)
+
+	existing, ok := ecs.componentsRegistry[e.ID()]
+	if !ok {
+		ecs.reserveEntities(1)
+	}
+
+	checkComponents(e.ID(), existing, components)
+
+	ecs.componentsRegistry[e.ID()] = append(existing, components...)
+	ecs.count(components, 1)
+	ecs.structure++
+	ecs.markRegistered(e.ID(), components)
+
+	if !ok {
+		ecs.spawned(e.ID())
+	} else if ecs.analyzer != nil {
+		ecs.analyzer.componentsAdded(len(components))
+	}
+}
+
+// checkComponents panics if the data of a new component of an entity is not a pointer,
+// or if its type is the type of another new or existing component of the entity.
+func checkComponents(id entity.ID, existing, components []component.Component) {
+	for i, component := range components {
 		// check component data member is a ptr
 		componentValue := reflect.ValueOf(component.Data())
 
@@ -58,51 +207,110 @@ func (ecs *ECS) RegisterEntity(e entity.Entity, components ...component.Componen
 			panic(fmt.Sprintf("the entity component %q you are trying to register MUST be a pointer", componentValue.Type().Name()))
 		}
 
-		ecs.componentsRegistry[e.ID()] = append(ecs.componentsRegistry[e.ID()], component)
+		if hasType(existing, component.TypeID()) || hasType(components[:i], component.TypeID()) {
+			panic(fmt.Sprintf("the entity %s already has a component of type %s", id, componentValue.Type()))
+		}
+	}
+}
+
+// removeEntity removes all the occurrences of an entity from a list of entities, keeping the others in order.
+// A new list is allocated instead of modifying l if it is shared, i.e. it was returned to a caller which may still be
+// ranging over it, or while systems iterate over the lists, so that unregistering entities never makes an iteration
+// skip or repeat an entity. The boolean is true if a new list was allocated.
+func (ecs *ECS) removeEntity(l []entity.Entity, id entity.ID, shared bool) ([]entity.Entity, bool) {
+	i := slices.IndexFunc(l, func(e entity.Entity) bool {
+		return e.ID() == id
+	})
+	if i < 0 {
+		return l, false
+	}
+
+	if !shared && ecs.iterating == 0 {
+		return slices.DeleteFunc(l, func(e entity.Entity) bool {
+			return e.ID() == id
+		}), false
+	}
+
+	kept := make([]entity.Entity, i, len(l)-1)
+	copy(kept, l[:i])
+
+	for _, e := range l[i+1:] {
+		if e.ID() != id {
+			kept = append(kept, e)
+		}
 	}
+
+	return kept, true
 }
 
-func deleteFromSlice(l []entity.Entity, i int) []entity.Entity {
-	if len(l) == 0 || i >= len(l) || i < 0 {
-		return l
+// hasType returns true if one of the components has the given type.
+func hasType(components []component.Component, t component.TypeID) bool {
+	for _, c := range components {
+		if c.TypeID() == t {
+			return true
+		}
 	}
-	l[i], l[len(l)-1] = l[len(l)-1], l[i]
-	return l[:len(l)-1]
+
+	return false
 }
 
 // UnregisterEntity removes an entity and its components from the ECS.
 // It takes an entity ID as an argument and removes the entity from the entities registry.
-// It also removes the components associated with the entity from the components registry.
+// It also removes the components, the tags, the name and the groups associated with the entity,
+// including the components held by stores attached to the world.
 // The method iterates through the entities registry and removes the entity from the list of entities
-// associated with the system ID. It also deletes the components associated with the entity ID from the components registry.
+// associated with the system ID, keeping the other entities in order. It also deletes the components
+// associated with the entity ID from the components registry.
+// It is safe to call it from a system: the entities unregistered during an iteration are skipped by it,
+// and the other entities are all visited once. See also Despawn.
 func (ecs *ECS) UnregisterEntity(id entity.ID) {
+	if _, ok := ecs.componentsRegistry[id]; ok {
+		ecs.despawned(id)
+	}
+
 	for sid, entities := range ecs.entitiesRegistry {
-		for i, e := range entities {
-			if e.ID() == id {
-				ecs.entitiesRegistry[sid] = deleteFromSlice(entities, i)
-			}
+		_, shared := ecs.sharedLists[sid]
+
+		var copied bool
+		ecs.entitiesRegistry[sid], copied = ecs.removeEntity(entities, id, shared)
+
+		if copied {
+			delete(ecs.sharedLists, sid)
 		}
 	}
 
+	ecs.count(ecs.componentsRegistry[id], -1)
 	delete(ecs.componentsRegistry, id)
+	ecs.structure++
+	ecs.untagAll(id)
+	ecs.unname(id)
+	ecs.ungroup(id)
+	ecs.forgetChanges(id)
+	ecs.unlinkRefs(id)
+	delete(ecs.inactiveEntities, id)
+	delete(ecs.dying, id)
+
+	for _, s := range ecs.stores {
+		s.Remove(id)
+	}
 }
 
-// UnregisterSystem removes a system and its associated entities from the ECS.
-// It takes a system ID as an argument and removes the system from the entities registry.
-func (ecs *ECS) RegisterUpdater(s system.Updater, e ...entity.Entity) {
+// RegisterUpdater registers an updater with the entities it updates, and initializes it if it implements Initializer.
+// It returns the ID of the updater, assigned at registration if the updater embeds system.Base (see system.EnsureID).
+func (ecs *ECS) RegisterUpdater(s system.Updater, e ...entity.Entity) system.ID {
+	id := system.EnsureID(s)
+	ecs.ReserveSystems(1)
 	ecs.updaters = append(ecs.updaters, s)
-	ecs.entitiesRegistry[s.ID()] = append(ecs.entitiesRegistry[s.ID()], e...)
-}
+	ecs.unsorted = true
+	ecs.Associate(s, e...)
+	ecs.InitSystem(s)
 
-// UnregisterSystem removes a system and its associated entities from the ECS.
-// It takes a system ID as an argument and removes the system from the entities registry.
-func (ecs *ECS) RegisterDrawer(s system.Drawer, zIndex int, e ...entity.Entity) {
-	_, ok := ecs.drawers[zIndex]
-	if !ok {
-		ecs.drawers[zIndex] = []system.Drawer{}
-	}
+	return id
+}
 
-	ecs.drawers[zIndex] = append(ecs.drawers[zIndex], s)
+// Associate adds entities to the list of entities handled by a system.
+// It is used by packages running their own kind of systems, such as drawers, on the entities of the world.
+func (ecs *ECS) Associate(s system.System, e ...entity.Entity) {
 	ecs.entitiesRegistry[s.ID()] = append(ecs.entitiesRegistry[s.ID()], e...)
 }
 
@@ -113,9 +321,24 @@ func (ecs *ECS) QueryEntityComponents(e entity.Entity, components ...interface{}
 	component.QueryComponents(registeredComponents, components...)
 }
 
+// Registered returns true if an entity is registered in the world, even without components.
+func (ecs *ECS) Registered(id entity.ID) bool {
+	_, ok := ecs.componentsRegistry[id]
+	return ok
+}
+
+// EntityComponents returns the components registered for an entity, nil if it is not registered
+// or has no components (see Registered). The returned slice must not be modified.
+func (ecs *ECS) EntityComponents(id entity.ID) []component.Component {
+	return ecs.componentsRegistry[id]
+}
+
 // FilterEntities filters the entities associated with a system.
 // It takes a system as an argument and returns a slice of entities associated with the system ID.
+// The returned slice MUST NOT be modified; it is not affected by the entities unregistered afterwards,
+// so that entities can be unregistered while ranging over it.
 func (ecs *ECS) FilterEntities(s system.System) []entity.Entity {
+	ecs.sharedLists[s.ID()] = struct{}{}
 	return ecs.entitiesRegistry[s.ID()]
 }
 
@@ -124,44 +347,178 @@ func (ecs *ECS) Updaters() []system.Updater {
 	return ecs.updaters
 }
 
-// Drawers returns the map of registered drawers in the ECS.
-func (ecs *ECS) Drawers() map[int][]system.Drawer {
-	return ecs.drawers
-}
-
-// Update iterates through the registered updaters and updates the entities associated with them.
+// Update iterates through the registered updaters and updates the active entities associated with them.
+// The functions queued with Defer are run first, then the startup systems (see RegisterStartup),
+// then the updaters are sorted if needed (see SortUpdaters).
+// With a fixed timestep, the updaters are run as many times as needed to catch up with real time,
+// unless the world is deterministic.
+// While the simulation is paused, the updaters only run for the steps requested with Step.
+// The entities despawned with Despawn are unregistered at the end, whether the update succeeded or not.
+// Update itself does not allocate memory, so steady-state frames only allocate what the systems do.
 func (ecs *ECS) Update() error {
-	for _, s := range ecs.Updaters() {
-		for _, e := range ecs.FilterEntities(s) {
-			registeredComponents := ecs.componentsRegistry[e.ID()]
-			err := s.Update(e.ID(), registeredComponents, ecs.componentsRegistry)
+	ecs.lock()
+	defer ecs.unlock()
+	defer ecs.finalizeDespawns()
+
+	ecs.resetArenas()
+	ecs.runDeferred()
+
+	if ecs.initErr != nil {
+		err := ecs.initErr
+		ecs.initErr = nil
+
+		return err
+	}
+
+	if ecs.crashErr != nil {
+		err := ecs.crashErr
+		ecs.crashErr = nil
+
+		return err
+	}
+
+	err := ecs.runStartups()
+	if err != nil {
+		return err
+	}
+
+	if ecs.unsorted {
+		err := ecs.SortUpdaters()
+		if err != nil {
+			return err
+		}
+	}
+
+	if ecs.analyzer != nil {
+		ecs.analyzer.frame()
+	}
+
+	ecs.BeginPhase(PhaseUpdate)
+
+	if ecs.paused {
+		for ecs.steps > 0 {
+			ecs.steps--
+
+			err := ecs.step()
 			if err != nil {
 				return err
 			}
 		}
+
+		return nil
+	}
+
+	if ecs.config.fixedStep <= 0 || ecs.config.deterministic {
+		return ecs.fastStep()
+	}
+
+	now := time.Now()
+	if ecs.lastUpdate.IsZero() {
+		ecs.accumulator = ecs.config.fixedStep
+	} else {
+		ecs.accumulator += ecs.scaled(now.Sub(ecs.lastUpdate))
+	}
+	ecs.lastUpdate = now
+
+	for steps := 0; ecs.accumulator >= ecs.config.fixedStep; steps++ {
+		if steps == ecs.maxFixedSteps() {
+			// drop the time we cannot catch up with
+			ecs.accumulator = 0
+			break
+		}
+
+		ecs.accumulator -= ecs.config.fixedStep
+
+		err := ecs.fastStep()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// step captures the input of the step, then runs every updater once on the active entities associated with it.
+// In rollback mode, the state of the world is saved before running the updaters.
+func (ecs *ECS) step() error {
+	err := ecs.nextInput()
+	if err != nil {
+		return err
+	}
+
+	if ecs.history != nil {
+		ecs.history.save(ecs)
+	}
+
+	ecs.dt = ecs.stepDuration()
+
+	return ecs.runUpdaters()
+}
+
+// runUpdaters runs once every updater which is due (see system.Periodic and system.Timed), whose run conditions
+// hold (see RunIf) and whose system group is not paused, and advances the tick counter and the simulated time.
+func (ecs *ECS) runUpdaters() error {
+	ecs.tick++
+	ecs.advanceTime()
+	ecs.updateTime()
+
+	for _, s := range ecs.Updaters() {
+		if ecs.groupPaused(s.ID()) || !ecs.due(s) || !ecs.conditionsHold(s.ID()) {
+			continue
+		}
+
+		err := ecs.update(s)
+		if err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
-// Draw iterates through the registered drawers and draws the entities associated with them.
-func (ecs *ECS) Draw(screen *ebiten.Image) {
-	// https://go.dev/blog/maps - Iteration order
-	drawers := ecs.Drawers()
+// update runs an updater on the active entities associated with it, after its BeginStep (see system.Stepper).
+func (ecs *ECS) update(s system.Updater) (err error) {
+	var current entity.ID
+	defer ecs.recoverCrash(s, PhaseUpdate, &current, &err)
 
-	zIndexes := make([]int, 0, len(drawers))
+	ecs.iterating++
+	defer func() {
+		ecs.iterating--
+	}()
 
-	for i := range drawers {
-		zIndexes = append(zIndexes, i)
+	var p probe
+	if ecs.profiler != nil {
+		p = ecs.profiler.begin()
 	}
-	sort.Ints(zIndexes)
 
-	for _, i := range zIndexes {
-		for _, d := range drawers[i] {
-			for _, e := range ecs.FilterEntities(d) {
-				registeredComponents := ecs.componentsRegistry[e.ID()]
-				d.Draw(screen, registeredComponents)
-			}
+	visited := 0
+
+	if st, ok := unwrap(s).(system.Stepper); ok {
+		err = st.BeginStep()
+	}
+
+	for _, e := range ecs.FilterEntities(s) {
+		if err != nil {
+			break
+		}
+
+		c, ok := ecs.componentsRegistry[e.ID()]
+		if !ok || !ecs.IsActive(e.ID()) {
+			continue
+		}
+
+		visited++
+		current = e.ID()
+
+		err = s.Update(e.ID(), c, ecs.componentsRegistry)
+		if err != nil {
+			break
 		}
 	}
+
+	if ecs.profiler != nil {
+		ecs.profiler.end(s, PhaseUpdate, p, visited)
+	}
+
+	return err
 }
@@ -0,0 +1,103 @@
+// Package ecstest provides helpers to test systems: worlds with deterministic entity and system IDs,
+// step helpers, component assertions and recording replays. It only depends on the headless core,
+// so that tests build without cgo or a display; the helpers to test drawers are in ecstestebiten.
+//
+//	func TestMovement(t *testing.T) {
+//		world := ecstest.NewWorld(t)
+//		e := world.Spawn(&Position{}, &Velocity{X: 1})
+//		world.RegisterUpdater(NewMovement(), e)
+//
+//		ecstest.AdvanceTicks(t, world, 10)
+//
+//		if p := ecstest.RequireComponent[Position](t, world, e); p.X != 10 {
+//			t.Errorf("X = %v, want 10", p.X)
+//		}
+//	}
+//
+// The worlds of this package reset the global ID allocation: tests using them MUST NOT run in parallel.
+package ecstest
+
+import (
+	"testing"
+
+	ecs "github.com/jtbonhomme/ebiten-ecs"
+	"github.com/jtbonhomme/ebiten-ecs/component"
+	"github.com/jtbonhomme/ebiten-ecs/entity"
+	"github.com/jtbonhomme/ebiten-ecs/system"
+)
+
+// Seed is the seed of the random number generator of the worlds created by NewWorld.
+const Seed int64 = 1
+
+// ResetIDs restarts the allocation of the entity and system IDs from 1, so that golden tests
+// get the same IDs whatever the tests run before them.
+func ResetIDs() {
+	entity.ResetIDs()
+	system.ResetIDs()
+}
+
+// NewWorld resets the ID allocation and creates a deterministic world, seeded with Seed, configured
+// by the given options, which can override the seed. The world is shut down when the test ends.
+func NewWorld(t testing.TB, opts ...ecs.Option) *ecs.ECS {
+	t.Helper()
+
+	ResetIDs()
+
+	world := ecs.New(append([]ecs.Option{ecs.WithDeterministic(Seed)}, opts...)...)
+	t.Cleanup(world.Shutdown)
+
+	return world
+}
+
+// AdvanceTicks updates the world n times, failing the test at the first error.
+func AdvanceTicks(t testing.TB, world *ecs.ECS, n int) {
+	t.Helper()
+
+	for i := 0; i < n; i++ {
+		err := world.Update()
+		if err != nil {
+			t.Fatalf("update %d of %d: %v", i+1, n, err)
+		}
+	}
+}
+
+// RequireComponent returns the component of type T of an entity, failing the test if the entity has none.
+func RequireComponent[T any](t testing.TB, world *ecs.ECS, e entity.Entity) *T {
+	t.Helper()
+
+	c := component.NewType[T]().Get(world.EntityComponents(e.ID()))
+	if c == nil {
+		var zero T
+		t.Fatalf("entity %s has no component %T", e.ID(), &zero)
+	}
+
+	return c
+}
+
+// RequireNoComponent fails the test if an entity has a component of type T.
+func RequireNoComponent[T any](t testing.TB, world *ecs.ECS, e entity.Entity) {
+	t.Helper()
+
+	if ecs.Has[T](world, e.ID()) {
+		var zero T
+		t.Fatalf("entity %s has a component %T", e.ID(), &zero)
+	}
+}
+
+// RequireRegistered fails the test if an entity is not registered in the world.
+func RequireRegistered(t testing.TB, world *ecs.ECS, e entity.Entity) {
+	t.Helper()
+
+	if !world.Registered(e.ID()) {
+		t.Fatalf("entity %s is not registered", e.ID())
+	}
+}
+
+// RequireUnregistered fails the test if an entity is still registered in the world.
+func RequireUnregistered(t testing.TB, world *ecs.ECS, e entity.Entity) {
+	t.Helper()
+
+	if world.Registered(e.ID()) {
+		t.Fatalf("entity %s is still registered", e.ID())
+	}
+}
@@ -0,0 +1,112 @@
+package ecstest_test
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+
+	"github.com/jtbonhomme/ebiten-ecs/component"
+	"github.com/jtbonhomme/ebiten-ecs/ecstest"
+	"github.com/jtbonhomme/ebiten-ecs/entity"
+	"github.com/jtbonhomme/ebiten-ecs/input"
+	"github.com/jtbonhomme/ebiten-ecs/system"
+)
+
+type position struct {
+	X float64
+}
+
+type velocity struct {
+	X float64
+}
+
+type movement struct {
+	system.Base
+}
+
+func (m *movement) Update(_ entity.ID, c []component.Component, _ map[entity.ID][]component.Component) error {
+	p, v := c[0].Data().(*position), c[1].Data().(*velocity)
+	p.X += v.X
+
+	return nil
+}
+
+// fakeTB records the failure of a helper instead of failing the test.
+type fakeTB struct {
+	testing.TB
+	failure string
+}
+
+func (f *fakeTB) Helper() {}
+
+func (f *fakeTB) Fatalf(format string, args ...interface{}) {
+	f.failure = fmt.Sprintf(format, args...)
+	runtime.Goexit()
+}
+
+// run runs a helper with a fake TB, and returns its failure message, empty if it did not fail.
+func run(t *testing.T, helper func(tb testing.TB)) string {
+	f := &fakeTB{TB: t}
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		helper(f)
+	}()
+
+	<-done
+
+	return f.failure
+}
+
+func TestNewWorldResetsIDs(t *testing.T) {
+	for i := 0; i < 2; i++ {
+		world := ecstest.NewWorld(t)
+
+		e := world.Spawn(&position{})
+		if e.ID() != 1 {
+			t.Fatalf("run %d: first entity ID = %s, want 1", i, e.ID())
+		}
+
+		if id := world.RegisterUpdater(&movement{}); id != 1 {
+			t.Fatalf("run %d: first system ID = %s, want 1", i, id)
+		}
+	}
+}
+
+func TestAdvanceTicksAndRequireComponent(t *testing.T) {
+	world := ecstest.NewWorld(t)
+	e := world.Spawn(&position{}, &velocity{X: 1})
+	world.RegisterUpdater(&movement{}, e)
+
+	ecstest.AdvanceTicks(t, world, 10)
+
+	if p := ecstest.RequireComponent[position](t, world, e); p.X != 10 {
+		t.Errorf("X = %v, want 10", p.X)
+	}
+
+	ecstest.RequireRegistered(t, world, e)
+
+	if msg := run(t, func(tb testing.TB) { ecstest.RequireComponent[input.Snapshot](tb, world, e) }); msg == "" {
+		t.Error("RequireComponent did not fail for a missing component")
+	}
+
+	world.UnregisterEntity(e.ID())
+	ecstest.RequireUnregistered(t, world, e)
+
+	if msg := run(t, func(tb testing.TB) { ecstest.RequireRegistered(tb, world, e) }); msg == "" {
+		t.Error("RequireRegistered did not fail for an unregistered entity")
+	}
+}
+
+func TestRequireRegisteredWithoutComponents(t *testing.T) {
+	world := ecstest.NewWorld(t)
+	e := entity.New()
+	world.RegisterEntity(e)
+
+	ecstest.RequireRegistered(t, world, e)
+
+	if msg := run(t, func(tb testing.TB) { ecstest.RequireUnregistered(tb, world, e) }); msg == "" {
+		t.Error("RequireUnregistered did not fail for a registered entity without components")
+	}
+}
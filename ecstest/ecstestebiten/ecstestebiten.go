@@ -0,0 +1,40 @@
+// Package ecstestebiten provides the helpers of ecstest which depend on Ebiten: a fake screen to run drawers on
+// and worlds of the rendering package. It is separate from ecstest so that the tests of headless systems
+// build without cgo or a display.
+package ecstestebiten
+
+import (
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	ecs "github.com/jtbonhomme/ebiten-ecs"
+	"github.com/jtbonhomme/ebiten-ecs/ebitenecs"
+	"github.com/jtbonhomme/ebiten-ecs/ecstest"
+)
+
+// NewScreen returns an offscreen image standing for the screen, for drawers to draw on.
+func NewScreen(width, height int) *ebiten.Image {
+	return ebiten.NewImage(width, height)
+}
+
+// NewRenderWorld resets the ID allocation and creates a deterministic world of the rendering package,
+// like ecstest.NewWorld. The world is shut down when the test ends.
+func NewRenderWorld(t testing.TB, opts ...ecs.Option) *ebitenecs.World {
+	t.Helper()
+
+	ecstest.ResetIDs()
+
+	world := ebitenecs.New(append([]ecs.Option{ecs.WithDeterministic(ecstest.Seed)}, opts...)...)
+	t.Cleanup(world.Shutdown)
+
+	return world
+}
+
+// DrawFrame draws the world on a new screen of the given size, and returns the screen.
+func DrawFrame(world *ebitenecs.World, width, height int) *ebiten.Image {
+	screen := NewScreen(width, height)
+	world.Draw(screen)
+
+	return screen
+}
@@ -0,0 +1,43 @@
+package ecstest
+
+import (
+	"testing"
+
+	ecs "github.com/jtbonhomme/ebiten-ecs"
+)
+
+// ReplayFile replays the recording file at the given path (see ecs.ECS.RecordFile), updating the world once
+// per recorded step, and returns the number of steps run. The test fails if the recording cannot be read,
+// if an update fails, or if an update consumes no input, e.g. because the world is paused. The world, e.g. created with NewWorld, MUST be in the same state as when
+// the recording started, so that gameplay regression tests drive the real update loop headlessly:
+//
+//	world := ecstest.NewWorld(t)
+//	level.Load(world)
+//	ecstest.ReplayFile(t, world, "testdata/boss-fight.rec")
+//	ecstest.RequireUnregistered(t, world, boss)
+func ReplayFile(t testing.TB, world *ecs.ECS, path string) int {
+	t.Helper()
+
+	err := world.ReplayFile(path)
+	if err != nil {
+		t.Fatalf("replay %s: %v", path, err)
+	}
+
+	steps := 0
+
+	for world.Replaying() {
+		steps++
+		tick := world.Tick()
+
+		err := world.Update()
+		if err != nil {
+			t.Fatalf("replay %s: step %d: %v", path, steps, err)
+		}
+
+		if world.Tick() == tick {
+			t.Fatalf("replay %s: step %d: the update consumed no input, is the world paused?", path, steps)
+		}
+	}
+
+	return steps
+}
@@ -0,0 +1,184 @@
+package ecstest_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	ecs "github.com/jtbonhomme/ebiten-ecs"
+	"github.com/jtbonhomme/ebiten-ecs/component"
+	"github.com/jtbonhomme/ebiten-ecs/ecstest"
+	"github.com/jtbonhomme/ebiten-ecs/entity"
+	"github.com/jtbonhomme/ebiten-ecs/input"
+	"github.com/jtbonhomme/ebiten-ecs/system"
+)
+
+func TestReplayFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.rec")
+
+	world := ecstest.NewWorld(t)
+	world.SetInputSource(func(s *input.Snapshot) {
+		s.Reset()
+		s.CursorX = int(world.Tick())
+	})
+
+	err := world.RecordFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ecstest.AdvanceTicks(t, world, 5)
+
+	err = world.StopRecording()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	replayed := ecstest.NewWorld(t)
+
+	var cursors []int
+
+	replayed.RegisterUpdater(&cursorRecorder{world: replayed, cursors: &cursors}, replayed.Spawn(&position{}))
+
+	if steps := ecstest.ReplayFile(t, replayed, path); steps != 5 {
+		t.Fatalf("replayed %d steps, want 5", steps)
+	}
+
+	for i, x := range cursors {
+		if x != i {
+			t.Errorf("step %d: cursor X = %d, want %d", i, x, i)
+		}
+	}
+}
+
+func TestReplayFileFailsWhenPaused(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.rec")
+
+	world := ecstest.NewWorld(t)
+
+	err := world.RecordFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ecstest.AdvanceTicks(t, world, 3)
+
+	err = world.StopRecording()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	paused := ecstest.NewWorld(t)
+	paused.Pause()
+
+	if msg := run(t, func(tb testing.TB) { ecstest.ReplayFile(tb, paused, path) }); msg == "" {
+		t.Error("ReplayFile did not fail on a paused world")
+	}
+}
+
+func TestReplayFileFailsOnTruncatedRecording(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.rec")
+
+	world := ecstest.NewWorld(t)
+	world.SetInputSource(func(s *input.Snapshot) {
+		s.Reset()
+		s.CursorX = int(world.Tick())
+	})
+
+	err := world.RecordFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ecstest.AdvanceTicks(t, world, 3)
+
+	err = world.StopRecording()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = os.WriteFile(path, data[:len(data)-5], 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if msg := run(t, func(tb testing.TB) { ecstest.ReplayFile(tb, ecstest.NewWorld(t), path) }); msg == "" {
+		t.Error("ReplayFile did not fail on a truncated recording")
+	}
+}
+
+// cursorRecorder records the cursor X position of the input of each step.
+type cursorRecorder struct {
+	system.Base
+	world   *ecs.ECS
+	cursors *[]int
+}
+
+func (r *cursorRecorder) Update(entity.ID, []component.Component, map[entity.ID][]component.Component) error {
+	x, _ := r.world.Input().CursorPosition()
+	*r.cursors = append(*r.cursors, x)
+
+	return nil
+}
+
+func TestReplayFileRestoresRandomState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.rec")
+
+	world := ecstest.NewWorld(t)
+	stream := world.RandStream("loot")
+
+	// numbers drawn before the recording starts
+	world.Rand().IntN(100)
+	stream.IntN(100)
+
+	var recorded []int
+
+	world.RegisterUpdater(&randRecorder{world: world, values: &recorded}, world.Spawn(&position{}))
+
+	err := world.RecordFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ecstest.AdvanceTicks(t, world, 5)
+
+	err = world.StopRecording()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	replayed := ecstest.NewWorld(t)
+
+	var values []int
+
+	replayed.RegisterUpdater(&randRecorder{world: replayed, values: &values}, replayed.Spawn(&position{}))
+	ecstest.ReplayFile(t, replayed, path)
+
+	if len(values) != len(recorded) {
+		t.Fatalf("replayed %d values, want %d", len(values), len(recorded))
+	}
+
+	for i := range values {
+		if values[i] != recorded[i] {
+			t.Fatalf("value %d = %d, want %d", i, values[i], recorded[i])
+		}
+	}
+}
+
+// randRecorder records numbers drawn from the generator of the world and from a stream at each step.
+type randRecorder struct {
+	system.Base
+	world  *ecs.ECS
+	values *[]int
+}
+
+func (r *randRecorder) Update(entity.ID, []component.Component, map[entity.ID][]component.Component) error {
+	*r.values = append(*r.values, r.world.Rand().IntN(1000000), r.world.RandStream("loot").IntN(1000000))
+
+	return nil
+}
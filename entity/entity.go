@@ -1,9 +1,12 @@
 package entity
 
-import "strconv"
+import (
+	"strconv"
+	"sync/atomic"
+)
 
 var (
-	id ID
+	id atomic.Int64
 )
 
 // ID is a type that represents a unique identifier for an entity.
@@ -15,28 +18,61 @@ func (id ID) String() string {
 }
 
 // AssignID is a function that assigns a unique ID to an entity.
+// It is safe to call it from several goroutines.
 func AssignID() ID {
-	id++
-	return id
+	return ID(id.Add(1))
 }
 
-// Entity is an interface that represents an entity in the ECS architecture.
-type Entity interface {
-	ID() ID
+// ResetIDs restarts the allocation of the IDs of the entities from 1, so that golden tests get the same IDs
+// whatever the tests run before them. It MUST NOT be called while entities created before are still in use,
+// as their IDs would be assigned again.
+func ResetIDs() {
+	id.Store(0)
 }
 
-type entity struct {
+// Entity is a handle on an entity. It is a plain value: copyable, comparable, usable as a map key,
+// and created without any allocation. IDs are never reused, so a handle on an unregistered entity
+// never refers to another entity. The zero Entity refers to no entity.
+type Entity struct {
 	id ID
 }
 
 // New creates a new entity with a unique ID.
 func New() Entity {
-	return &entity{
+	return Entity{
 		id: AssignID(),
 	}
 }
 
+// FromID returns the handle on the entity with the given ID, e.g. read from a save file or a network message.
+func FromID(id ID) Entity {
+	return Entity{
+		id: id,
+	}
+}
+
 // ID returns the unique ID of the entity.
-func (e *entity) ID() ID {
+func (e Entity) ID() ID {
 	return e.id
 }
+
+// IsZero returns true if the handle refers to no entity.
+func (e Entity) IsZero() bool {
+	return e.id == 0
+}
+
+// NewBatch creates n entities with consecutive unique IDs, reserved at once.
+func NewBatch(n int) []Entity {
+	if n <= 0 {
+		return nil
+	}
+
+	first := ID(id.Add(int64(n))) - ID(n) + 1
+	entities := make([]Entity, n)
+
+	for i := range entities {
+		entities[i].id = first + ID(i)
+	}
+
+	return entities
+}
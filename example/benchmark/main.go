@@ -0,0 +1,16 @@
+package main
+
+import (
+	"flag"
+	"os"
+
+	"github.com/jtbonhomme/ebiten-ecs/benchmarks"
+)
+
+// main compares the storage backends of the components on typical workloads.
+func main() {
+	entities := flag.Int("entities", 10000, "number of entities")
+	flag.Parse()
+
+	benchmarks.Print(os.Stdout, benchmarks.Run(*entities))
+}
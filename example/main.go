@@ -10,8 +10,8 @@ import (
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
 
-	ecs "github.com/jtbonhomme/ebiten-ecs"
 	"github.com/jtbonhomme/ebiten-ecs/component"
+	"github.com/jtbonhomme/ebiten-ecs/ebitenecs"
 	"github.com/jtbonhomme/ebiten-ecs/entity"
 	"github.com/jtbonhomme/ebiten-ecs/system"
 )
@@ -27,6 +27,9 @@ type CounterComponent struct {
 	Value int
 }
 
+// counterType is the handle used to find CounterComponent without reflection.
+var counterType = component.NewType[CounterComponent]()
+
 // CounterSystem is a simple system that updates the CounterComponent.
 // It implements the Updater interface from the ecs package, and the Drawer interface.
 // The Update method decrements the Value field of the CounterComponent by 1.
@@ -44,9 +47,7 @@ func (cs *CounterSystem) ID() system.ID {
 // Update is called every frame to update the CounterComponent.
 // It decrements the Value field of the CounterComponent by 1.
 func (cs *CounterSystem) Update(self entity.ID, c []component.Component, r map[entity.ID][]component.Component) error {
-	var counter *CounterComponent
-
-	component.QueryComponents(c, &counter)
+	counter := counterType.Get(c)
 	counter.Value--
 
 	return nil
@@ -56,9 +57,7 @@ func (cs *CounterSystem) Update(self entity.ID, c []component.Component, r map[e
 func (cs *CounterSystem) Draw(
 	screen *ebiten.Image,
 	c []component.Component) {
-	var counter *CounterComponent
-
-	component.QueryComponents(c, &counter)
+	counter := counterType.Get(c)
 
 	ebitenutil.DebugPrintAt(screen,
 		fmt.Sprintf("Counter value is %d", counter.Value),
@@ -67,7 +66,7 @@ func (cs *CounterSystem) Draw(
 
 // Game is the main structure for the game.
 type Game struct {
-	world *ecs.ECS
+	world *ebitenecs.World
 }
 
 // Update is called every frame to update the game state.
@@ -124,20 +123,13 @@ func main() {
 
 	// create a new game with ECS world
 	g := &Game{
-		world: ecs.New(),
+		world: ebitenecs.New(),
 	}
 
 	// create a new entity countDown wth a CounterComponent
 	// and register it in the ECS world.
 	countDown := entity.New()
-	g.world.RegisterEntity(
-		countDown,
-		component.New(
-			&CounterComponent{
-				Value: 1000000,
-			},
-		),
-	)
+	g.world.RegisterEntity(countDown, &CounterComponent{Value: 1000000})
 
 	// create a system to manage the CounterComponent
 	counterSystem := &CounterSystem{
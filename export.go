@@ -0,0 +1,168 @@
+package ecs
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/jtbonhomme/ebiten-ecs/component"
+	"github.com/jtbonhomme/ebiten-ecs/entity"
+)
+
+// exportedEntity is the text representation of an entity, its ID, name, tags and components.
+// The ID is only used to remap the references between the entities when they are imported.
+type exportedEntity struct {
+	ID         entity.ID                  `json:"id,omitempty"`
+	Name       string                     `json:"name,omitempty"`
+	Tags       []string                   `json:"tags,omitempty"`
+	Components map[string]json.RawMessage `json:"components"`
+}
+
+// ExportEntities exports the given entities with their components, names and tags to an indented JSON text,
+// which can be copy-pasted between levels or shared in bug reports.
+// The component types must have been registered with component.Register.
+// It returns an error if an entity holds a component of an unregistered type.
+func (ecs *ECS) ExportEntities(ids ...entity.ID) (string, error) {
+	exported, err := ecs.exportEntities(ids)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := json.MarshalIndent(exported, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+// exportEntities returns the text representations of the given entities.
+func (ecs *ECS) exportEntities(ids []entity.ID) ([]exportedEntity, error) {
+	exported := make([]exportedEntity, 0, len(ids))
+
+	for _, id := range ids {
+		ee := exportedEntity{
+			ID:         id,
+			Name:       ecs.Name(id),
+			Tags:       ecs.Tags(id),
+			Components: make(map[string]json.RawMessage, len(ecs.componentsRegistry[id])),
+		}
+
+		for _, c := range ecs.componentsRegistry[id] {
+			name, ok := component.TypeName(c.Data())
+			if !ok {
+				return nil, fmt.Errorf("entity %s: component type %T is not registered", id, c.Data())
+			}
+
+			data, err := json.Marshal(c.Data())
+			if err != nil {
+				return nil, fmt.Errorf("entity %s: component %q: %w", id, name, err)
+			}
+
+			ee.Components[name] = data
+		}
+
+		exported = append(exported, ee)
+	}
+
+	return exported, nil
+}
+
+// ImportEntities creates and registers new entities from a text produced by ExportEntities.
+// Imported entities get new IDs. Their names are restored unless already used by another entity.
+// The references between imported entities (see EntityRef) are remapped to the new IDs and tracked, without handler;
+// the references to entities which are not imported along are nulled.
+// It returns the created entities, or an error if the text is invalid or refers to an unregistered component type,
+// in which case no entity is registered.
+func (ecs *ECS) ImportEntities(data string) ([]entity.Entity, error) {
+	imported := []exportedEntity{}
+
+	err := json.Unmarshal([]byte(data), &imported)
+	if err != nil {
+		return nil, err
+	}
+
+	components, err := decodeEntities(imported)
+	if err != nil {
+		return nil, err
+	}
+
+	return ecs.registerImported(imported, components), nil
+}
+
+// decodeEntities creates the components of entities from their text representations.
+func decodeEntities(imported []exportedEntity) ([][]component.Component, error) {
+	components := make([][]component.Component, 0, len(imported))
+
+	for i, ie := range imported {
+		names := make([]string, 0, len(ie.Components))
+		for name := range ie.Components {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		cs := make([]component.Component, 0, len(ie.Components))
+
+		for _, name := range names {
+			raw := ie.Components[name]
+
+			d, err := component.NewData(name)
+			if err != nil {
+				return nil, fmt.Errorf("entity #%d: %w", i, err)
+			}
+
+			err = json.Unmarshal(raw, d)
+			if err != nil {
+				return nil, fmt.Errorf("entity #%d: component %q: %w", i, name, err)
+			}
+
+			cs = append(cs, component.New(d))
+		}
+
+		components = append(components, cs)
+	}
+
+	return components, nil
+}
+
+// registerImported registers new entities with their decoded components, names and tags.
+func (ecs *ECS) registerImported(imported []exportedEntity, components [][]component.Component) []entity.Entity {
+	entities := make([]entity.Entity, 0, len(imported))
+
+	for i, ie := range imported {
+		e := entity.New()
+		ecs.RegisterComponents(e, components[i]...)
+		ecs.Tag(e, ie.Tags...)
+
+		if ie.Name != "" {
+			_ = ecs.SetName(e, ie.Name)
+		}
+
+		entities = append(entities, e)
+	}
+
+	ecs.remapRefs(imported, entities)
+
+	return entities
+}
+
+// remapRefs makes the references held by imported entities refer to the new IDs of their targets, and tracks them.
+func (ecs *ECS) remapRefs(imported []exportedEntity, entities []entity.Entity) {
+	ids := make(map[entity.ID]entity.ID, len(imported))
+	for i, ie := range imported {
+		if ie.ID != 0 {
+			ids[ie.ID] = entities[i].ID()
+		}
+	}
+
+	for _, e := range entities {
+		for _, c := range ecs.componentsRegistry[e.ID()] {
+			visitRefs(reflect.ValueOf(c.Data()).Elem(), func(ref *EntityRef) {
+				target := ids[ref.id]
+				ref.id = 0
+				ecs.SetRef(e.ID(), ref, target, nil)
+			})
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package ecs
+
+import (
+	"github.com/jtbonhomme/ebiten-ecs/component"
+	"github.com/jtbonhomme/ebiten-ecs/entity"
+)
+
+// ForEach calls fn for each entity registered in the world, active or not, by increasing ID,
+// so that tools can walk all the entities regardless of the systems they are associated with.
+// Entities registered by fn during the iteration are only seen by the next one.
+func (ecs *ECS) ForEach(fn func(id entity.ID, c []component.Component)) {
+	ecs.ForEachWith(fn)
+}
+
+// ForEachWith calls fn for each entity registered in the world, active or not, having components
+// of all the given types, by increasing ID:
+//
+//	world.ForEachWith(inspect, PositionType.ID(), VelocityType.ID())
+func (ecs *ECS) ForEachWith(fn func(id entity.ID, c []component.Component), types ...component.TypeID) {
+	if ecs.all.world == nil {
+		ecs.all.world = ecs
+	}
+
+	ecs.all.refresh()
+	ecs.all.iterating++
+	defer func() { ecs.all.iterating-- }()
+
+	for _, id := range ecs.all.ids {
+		// entities unregistered by fn during the iteration are skipped
+		c, ok := ecs.componentsRegistry[id]
+		if !ok {
+			continue
+		}
+
+		matches := true
+
+		for _, t := range types {
+			if !hasType(c, t) {
+				matches = false
+				break
+			}
+		}
+
+		if matches {
+			fn(id, c)
+		}
+	}
+}
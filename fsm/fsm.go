@@ -0,0 +1,97 @@
+// Package fsm provides a generic finite-state-machine component and the system running it,
+// for enemy AI, player states or UI flows.
+// The states and transitions are described once by a Definition shared by every entity,
+// while each entity holds the current state of its own Machine.
+package fsm
+
+import (
+	"fmt"
+
+	"github.com/jtbonhomme/ebiten-ecs/component"
+	"github.com/jtbonhomme/ebiten-ecs/entity"
+)
+
+// State holds the callbacks of a state. Every callback is optional.
+type State[S comparable] struct {
+	// Enter is called when the entity enters the state.
+	Enter func(id entity.ID, c []component.Component) error
+	// Exit is called when the entity leaves the state.
+	Exit func(id entity.ID, c []component.Component) error
+	// Update is called at each update while the entity is in the state. It returns the next state
+	// of the entity, which is the current state to stay in it.
+	Update func(id entity.ID, c []component.Component) (S, error)
+}
+
+// Definition describes the states of a machine and the events triggering its transitions.
+type Definition[S comparable] struct {
+	initial     S
+	states      map[S]State[S]
+	transitions map[S]map[string]S
+}
+
+// NewDefinition creates a definition in which machines start in the given state.
+func NewDefinition[S comparable](initial S) *Definition[S] {
+	return &Definition[S]{
+		initial:     initial,
+		states:      make(map[S]State[S]),
+		transitions: make(map[S]map[string]S),
+	}
+}
+
+// State sets the callbacks of a state. It returns the definition so that calls can be chained.
+func (d *Definition[S]) State(s S, callbacks State[S]) *Definition[S] {
+	d.states[s] = callbacks
+	return d
+}
+
+// On adds a transition from a state to another, triggered by an event fired on the machine.
+// It returns the definition so that calls can be chained.
+// The method panics if the event already triggers a transition from this state.
+func (d *Definition[S]) On(from S, event string, to S) *Definition[S] {
+	events, ok := d.transitions[from]
+	if !ok {
+		events = make(map[string]S)
+		d.transitions[from] = events
+	}
+
+	if _, ok := events[event]; ok {
+		panic(fmt.Sprintf("the event %q you are trying to add already triggers a transition from state %v", event, from))
+	}
+
+	events[event] = to
+
+	return d
+}
+
+// Machine is the component holding the state of an entity.
+type Machine[S comparable] struct {
+	definition *Definition[S]
+	current    S
+	started    bool
+	ticks      int
+	events     []string
+}
+
+// NewMachine creates a machine described by the definition. It enters its initial state at its first update.
+func NewMachine[S comparable](d *Definition[S]) *Machine[S] {
+	return &Machine[S]{
+		definition: d,
+		current:    d.initial,
+	}
+}
+
+// Current returns the current state of the machine.
+func (m *Machine[S]) Current() S {
+	return m.current
+}
+
+// Ticks returns the number of updates since the machine entered its current state.
+func (m *Machine[S]) Ticks() int {
+	return m.ticks
+}
+
+// Fire queues an event, handled at the next update of the machine.
+// Events which do not trigger any transition from the state the machine is in at that time are ignored.
+func (m *Machine[S]) Fire(event string) {
+	m.events = append(m.events, event)
+}
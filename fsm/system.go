@@ -0,0 +1,126 @@
+package fsm
+
+import (
+	"fmt"
+
+	"github.com/jtbonhomme/ebiten-ecs/component"
+	"github.com/jtbonhomme/ebiten-ecs/entity"
+	"github.com/jtbonhomme/ebiten-ecs/system"
+)
+
+// Listener is called after each transition of a machine, with the event which triggered it,
+// or an empty event if the transition was returned by the Update callback of a state.
+type Listener[S comparable] func(id entity.ID, from, to S, event string)
+
+// System is the updater running the Machine[S] component of its entities: it enters the initial state,
+// handles the fired events, then calls the Update callback of the current state.
+type System[S comparable] struct {
+	id          system.ID
+	machineType component.Type[Machine[S]]
+	listener    Listener[S]
+}
+
+// NewSystem creates the updater running the Machine[S] component of its entities.
+// The listener, which may be nil, is notified of every transition.
+func NewSystem[S comparable](listener Listener[S]) *System[S] {
+	return &System[S]{
+		id:          system.AssignID(),
+		machineType: component.NewType[Machine[S]](),
+		listener:    listener,
+	}
+}
+
+// ID returns the unique ID of the system.
+func (s *System[S]) ID() system.ID {
+	return s.id
+}
+
+// Update runs the machine of the entity, if it has one.
+func (s *System[S]) Update(id entity.ID, c []component.Component, _ map[entity.ID][]component.Component) error {
+	m := s.machineType.Get(c)
+	if m == nil {
+		return nil
+	}
+
+	if !m.started {
+		m.started = true
+
+		err := s.enter(m, id, c)
+		if err != nil {
+			return err
+		}
+	}
+
+	for i := 0; i < len(m.events); i++ {
+		to, ok := m.definition.transitions[m.current][m.events[i]]
+		if !ok {
+			continue
+		}
+
+		err := s.transition(m, id, c, to, m.events[i])
+		if err != nil {
+			m.events = m.events[:0]
+			return err
+		}
+	}
+
+	m.events = m.events[:0]
+
+	state := m.definition.states[m.current]
+	if state.Update != nil {
+		next, err := state.Update(id, c)
+		if err != nil {
+			return fmt.Errorf("update state %v: %w", m.current, err)
+		}
+
+		if next != m.current {
+			err = s.transition(m, id, c, next, "")
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	m.ticks++
+
+	return nil
+}
+
+// transition moves the machine from its current state to another one.
+func (s *System[S]) transition(m *Machine[S], id entity.ID, c []component.Component, to S, event string) error {
+	from := m.current
+
+	if exit := m.definition.states[from].Exit; exit != nil {
+		err := exit(id, c)
+		if err != nil {
+			return fmt.Errorf("exit state %v: %w", from, err)
+		}
+	}
+
+	m.current = to
+
+	err := s.enter(m, id, c)
+	if err != nil {
+		return err
+	}
+
+	if s.listener != nil {
+		s.listener(id, from, to, event)
+	}
+
+	return nil
+}
+
+// enter calls the Enter callback of the current state of the machine.
+func (s *System[S]) enter(m *Machine[S], id entity.ID, c []component.Component) error {
+	m.ticks = 0
+
+	if enter := m.definition.states[m.current].Enter; enter != nil {
+		err := enter(id, c)
+		if err != nil {
+			return fmt.Errorf("enter state %v: %w", m.current, err)
+		}
+	}
+
+	return nil
+}
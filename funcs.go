@@ -0,0 +1,37 @@
+package ecs
+
+import (
+	"github.com/jtbonhomme/ebiten-ecs/component"
+	"github.com/jtbonhomme/ebiten-ecs/entity"
+	"github.com/jtbonhomme/ebiten-ecs/system"
+)
+
+// funcUpdater is an updater calling a function.
+type funcUpdater struct {
+	id system.ID
+	fn func(entity.ID, []component.Component, map[entity.ID][]component.Component) error
+}
+
+// UpdaterFunc returns an updater, with a new unique ID, calling fn for each of its entities,
+// so that a small system can be registered as a closure:
+//
+//	world.RegisterUpdater(ecs.UpdaterFunc(func(id entity.ID, c []component.Component, _ map[entity.ID][]component.Component) error {
+//		// ...
+//		return nil
+//	}), e)
+func UpdaterFunc(fn func(entity.ID, []component.Component, map[entity.ID][]component.Component) error) system.Updater {
+	return &funcUpdater{
+		id: system.AssignID(),
+		fn: fn,
+	}
+}
+
+// ID returns the unique ID of the updater.
+func (u *funcUpdater) ID() system.ID {
+	return u.id
+}
+
+// Update calls the function of the updater.
+func (u *funcUpdater) Update(id entity.ID, c []component.Component, r map[entity.ID][]component.Component) error {
+	return u.fn(id, c, r)
+}
@@ -0,0 +1,170 @@
+package ecs
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/jtbonhomme/ebiten-ecs/system"
+)
+
+// EventPublisher is implemented by systems declaring the events they publish, so that DumpGraph shows the event flows.
+type EventPublisher interface {
+	PublishedEvents() []string
+}
+
+// EventSubscriber is implemented by systems declaring the events they handle, so that DumpGraph shows the event flows.
+type EventSubscriber interface {
+	SubscribedEvents() []string
+}
+
+// DumpGraph writes a DOT graph (see GraphViz) of the architecture of the world: the updaters in the order they run,
+// clustered by system group, followed by the other given systems (e.g. the drawers of a rendering package),
+// the ordering constraints between them, the component types of the entities each system is associated with,
+// and the events they publish and handle (see EventPublisher and EventSubscriber). Render it with:
+//
+//	dot -Tsvg world.dot -o world.svg
+//
+// The component types are named after their registered name (see component.Register), or their Go type.
+func (ecs *ECS) DumpGraph(w io.Writer, others ...system.System) error {
+	systems := make([]system.System, 0, len(ecs.updaters)+len(others))
+	for _, s := range ecs.updaters {
+		systems = append(systems, s)
+	}
+
+	systems = append(systems, others...)
+
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintln(bw, "digraph world {")
+	fmt.Fprintln(bw, "\trankdir=LR;")
+	fmt.Fprintln(bw, "\tnode [fontname=\"monospace\"];")
+
+	// system nodes, clustered by group
+	clusters := make(map[string][]system.System)
+	loose := []system.System{}
+
+	for _, s := range systems {
+		if g, ok := ecs.groupOf[s.ID()]; ok {
+			clusters[g.name] = append(clusters[g.name], s)
+			continue
+		}
+
+		loose = append(loose, s)
+	}
+
+	groups := make([]string, 0, len(clusters))
+	for name := range clusters {
+		groups = append(groups, name)
+	}
+
+	sort.Strings(groups)
+
+	for i, name := range groups {
+		fmt.Fprintf(bw, "\tsubgraph cluster_%d {\n\t\tlabel=%q;\n", i, name)
+
+		for _, s := range clusters[name] {
+			fmt.Fprintf(bw, "\t\t%s;\n", systemNode(s))
+		}
+
+		fmt.Fprintln(bw, "\t}")
+	}
+
+	for _, s := range loose {
+		fmt.Fprintf(bw, "\t%s;\n", systemNode(s))
+	}
+
+	// ordering constraints
+	registered := make(map[system.ID]struct{}, len(systems))
+	for _, s := range systems {
+		registered[s.ID()] = struct{}{}
+	}
+
+	for _, s := range systems {
+		if p, ok := unwrap(s).(system.Predecessor); ok {
+			for _, id := range p.RunBefore() {
+				if _, ok := registered[id]; ok {
+					fmt.Fprintf(bw, "\ts%d -> s%d [style=dashed, label=\"before\"];\n", s.ID(), id)
+				}
+			}
+		}
+
+		if p, ok := unwrap(s).(system.Successor); ok {
+			for _, id := range p.RunAfter() {
+				if _, ok := registered[id]; ok {
+					fmt.Fprintf(bw, "\ts%d -> s%d [style=dashed, label=\"before\"];\n", id, s.ID())
+				}
+			}
+		}
+	}
+
+	// component types
+	components := make(map[string]struct{})
+
+	for _, s := range systems {
+		counts := make(map[string]int)
+
+		for _, e := range ecs.entitiesRegistry[s.ID()] {
+			for _, c := range ecs.componentsRegistry[e.ID()] {
+				counts[debugTypeName(c.Data())]++
+			}
+		}
+
+		for _, name := range sortedKeys(counts) {
+			components[name] = struct{}{}
+			fmt.Fprintf(bw, "\ts%d -> %q [color=gray, label=\"%d\"];\n", s.ID(), "c:"+name, counts[name])
+		}
+	}
+
+	for _, name := range sortedKeys(components) {
+		fmt.Fprintf(bw, "\t%q [shape=ellipse, label=%q];\n", "c:"+name, name)
+	}
+
+	// event flows
+	events := make(map[string]struct{})
+
+	for _, s := range systems {
+		if p, ok := unwrap(s).(EventPublisher); ok {
+			for _, event := range p.PublishedEvents() {
+				events[event] = struct{}{}
+				fmt.Fprintf(bw, "\ts%d -> %q [color=blue];\n", s.ID(), "e:"+event)
+			}
+		}
+
+		if h, ok := unwrap(s).(EventSubscriber); ok {
+			for _, event := range h.SubscribedEvents() {
+				events[event] = struct{}{}
+				fmt.Fprintf(bw, "\t%q -> s%d [color=blue];\n", "e:"+event, s.ID())
+			}
+		}
+	}
+
+	for _, event := range sortedKeys(events) {
+		fmt.Fprintf(bw, "\t%q [shape=diamond, color=blue, label=%q];\n", "e:"+event, event)
+	}
+
+	fmt.Fprintln(bw, "}")
+
+	return bw.Flush()
+}
+
+// systemNode returns the DOT node of a system, labelled with its Go type and its ID.
+func systemNode(s system.System) string {
+	name := strings.TrimPrefix(fmt.Sprintf("%T", unwrap(s)), "*")
+
+	return fmt.Sprintf("s%d [shape=box, label=%q]", s.ID(), fmt.Sprintf("%s\n#%d", name, s.ID()))
+}
+
+// sortedKeys returns the keys of a map, sorted.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
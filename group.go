@@ -0,0 +1,124 @@
+package ecs
+
+import (
+	"github.com/jtbonhomme/ebiten-ecs/entity"
+)
+
+// Group is a named set of entities sharing a lifetime, e.g. the entities of a level,
+// which are despawned in one call when the level unloads.
+type Group struct {
+	world    *ECS
+	name     string
+	entities []entity.Entity
+	members  map[entity.ID]struct{}
+	// shared is true if entities was returned by Entities, and must be copied before being modified
+	shared bool
+}
+
+// Group returns the group with the given name, creating it if it does not exist yet.
+func (ecs *ECS) Group(name string) *Group {
+	if g, ok := ecs.groups[name]; ok {
+		return g
+	}
+
+	g := &Group{
+		world:   ecs,
+		name:    name,
+		members: make(map[entity.ID]struct{}),
+	}
+	ecs.groups[name] = g
+
+	return g
+}
+
+// Name returns the name of the group.
+func (g *Group) Name() string {
+	return g.name
+}
+
+// Add adds entities to the group. Adding an entity twice has no effect.
+func (g *Group) Add(e ...entity.Entity) {
+	for _, e := range e {
+		if _, ok := g.members[e.ID()]; ok {
+			continue
+		}
+
+		g.members[e.ID()] = struct{}{}
+		g.entities = append(g.entities, e)
+	}
+}
+
+// Scope runs fn with the group as the current scope of the world: the entities registered while fn runs,
+// whether directly, with an EntityBuilder, from a pool or by importing them, are added to the group.
+// Scopes can be nested, entities being added to the innermost group only.
+func (g *Group) Scope(fn func()) {
+	g.world.scopes = append(g.world.scopes, g)
+	defer func() {
+		g.world.scopes = g.world.scopes[:len(g.world.scopes)-1]
+	}()
+
+	fn()
+}
+
+// Has returns true if the entity belongs to the group.
+func (g *Group) Has(id entity.ID) bool {
+	_, ok := g.members[id]
+	return ok
+}
+
+// Entities returns the entities of the group, in the order they were added.
+// The returned slice MUST NOT be modified; it is not affected by the entities unregistered afterwards,
+// so that entities can be unregistered while ranging over it.
+func (g *Group) Entities() []entity.Entity {
+	g.shared = true
+	return g.entities
+}
+
+// Len returns the number of entities of the group.
+func (g *Group) Len() int {
+	return len(g.entities)
+}
+
+// Despawn unregisters all the entities of the group, leaving it empty and ready to be used again.
+func (g *Group) Despawn() {
+	entities := g.entities
+	g.entities = nil
+
+	for _, e := range entities {
+		g.world.UnregisterEntity(e.ID())
+	}
+
+	clear(g.members)
+}
+
+// remove removes an entity from the group, if it belongs to it.
+func (g *Group) remove(id entity.ID) {
+	if _, ok := g.members[id]; !ok {
+		return
+	}
+
+	delete(g.members, id)
+
+	var copied bool
+	g.entities, copied = g.world.removeEntity(g.entities, id, g.shared)
+
+	if copied {
+		g.shared = false
+	}
+}
+
+// scope adds a newly registered entity to the current scope, if any.
+func (ecs *ECS) scope(e entity.Entity) {
+	if len(ecs.scopes) == 0 {
+		return
+	}
+
+	ecs.scopes[len(ecs.scopes)-1].Add(e)
+}
+
+// ungroup removes an entity from all the groups.
+func (ecs *ECS) ungroup(id entity.ID) {
+	for _, g := range ecs.groups {
+		g.remove(id)
+	}
+}
@@ -0,0 +1,161 @@
+// Package health provides Health and Damage components, and the system resolving the damage queued
+// during a step: it applies the hits to the health of the entities, honors their invulnerability windows,
+// and publishes Damaged and Died events for the systems running after it.
+package health
+
+import (
+	ecs "github.com/jtbonhomme/ebiten-ecs"
+	"github.com/jtbonhomme/ebiten-ecs/component"
+	"github.com/jtbonhomme/ebiten-ecs/entity"
+	"github.com/jtbonhomme/ebiten-ecs/system"
+)
+
+// Health is the component of the entities which can be damaged.
+type Health struct {
+	Current float64
+	Max     float64
+	// Invulnerability is the number of steps during which the entity ignores damage after being hit.
+	Invulnerability int
+	invulnerable    int
+	dead            bool
+}
+
+// NewHealth creates a health component with max health points.
+func NewHealth(max float64) *Health {
+	return &Health{
+		Current: max,
+		Max:     max,
+	}
+}
+
+// Invulnerable returns true while the entity ignores damage.
+func (h *Health) Invulnerable() bool {
+	return h.invulnerable > 0
+}
+
+// Dead returns true once the health of the entity has dropped to 0.
+func (h *Health) Dead() bool {
+	return h.dead
+}
+
+// Heal gives health points back to the entity, up to its max health. It does not revive a dead entity.
+func (h *Health) Heal(amount float64) {
+	if h.dead {
+		return
+	}
+
+	h.Current = min(h.Current+amount, h.Max)
+}
+
+// Hit is a hit dealt to an entity.
+type Hit struct {
+	Amount float64
+	Source entity.ID
+}
+
+// Damage is the component holding the hits dealt to an entity during the step, until they are resolved.
+type Damage struct {
+	hits []Hit
+}
+
+// Deal queues a hit of the given amount dealt by the source entity.
+func (d *Damage) Deal(amount float64, source entity.ID) {
+	d.hits = append(d.hits, Hit{Amount: amount, Source: source})
+}
+
+// Pending returns the hits which have not been resolved yet.
+func (d *Damage) Pending() []Hit {
+	return d.hits
+}
+
+// Damaged is the event published when an entity loses health points.
+type Damaged struct {
+	Entity entity.ID
+	Hit    Hit
+	// Remaining is the health of the entity after the hit.
+	Remaining float64
+}
+
+// Died is the event published when the health of an entity drops to 0.
+type Died struct {
+	Entity entity.ID
+	// Killer is the source of the last hit.
+	Killer entity.ID
+}
+
+// Events is the world resource holding the events published by the system during the last step.
+type Events struct {
+	Damaged []Damaged
+	Died    []Died
+}
+
+var (
+	healthType = component.NewType[Health]()
+	damageType = component.NewType[Damage]()
+)
+
+// System is the updater resolving the damage dealt to its entities. It should run after the systems
+// dealing damage, and before the systems reading the events.
+type System struct {
+	id     system.ID
+	events *Events
+}
+
+// NewSystem creates the updater resolving damage, and adds the Events resource to the world.
+func NewSystem(world *ecs.ECS) *System {
+	events := &Events{}
+	world.SetResource(events)
+
+	return &System{
+		id:     system.AssignID(),
+		events: events,
+	}
+}
+
+// ID returns the unique ID of the system.
+func (s *System) ID() system.ID {
+	return s.id
+}
+
+// BeginStep clears the events of the previous step.
+func (s *System) BeginStep() error {
+	s.events.Damaged = s.events.Damaged[:0]
+	s.events.Died = s.events.Died[:0]
+
+	return nil
+}
+
+// Update applies the hits dealt to the entity since the last step, and counts down its invulnerability.
+func (s *System) Update(id entity.ID, c []component.Component, _ map[entity.ID][]component.Component) error {
+	h, d := healthType.Get(c), damageType.Get(c)
+	if h == nil {
+		return nil
+	}
+
+	if h.invulnerable > 0 {
+		h.invulnerable--
+	}
+
+	if d == nil {
+		return nil
+	}
+
+	for _, hit := range d.hits {
+		if h.dead || h.invulnerable > 0 || hit.Amount <= 0 {
+			continue
+		}
+
+		h.Current = max(h.Current-hit.Amount, 0)
+		h.invulnerable = h.Invulnerability
+		s.events.Damaged = append(s.events.Damaged, Damaged{Entity: id, Hit: hit, Remaining: h.Current})
+
+		if h.Current == 0 {
+			h.dead = true
+			s.events.Died = append(s.events.Died, Died{Entity: id, Killer: hit.Source})
+		}
+	}
+
+	d.hits = d.hits[:0]
+
+	return nil
+}
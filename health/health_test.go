@@ -0,0 +1,31 @@
+package health_test
+
+import (
+	"testing"
+
+	ecs "github.com/jtbonhomme/ebiten-ecs"
+	"github.com/jtbonhomme/ebiten-ecs/ecstest"
+	"github.com/jtbonhomme/ebiten-ecs/health"
+)
+
+func TestEventsClearedWithoutEntities(t *testing.T) {
+	world := ecstest.NewWorld(t)
+	d := &health.Damage{}
+	e := world.Spawn(health.NewHealth(1), d)
+	world.RegisterUpdater(health.NewSystem(world), e)
+	events := ecs.Resource[health.Events](world)
+
+	d.Deal(1, 0)
+	ecstest.AdvanceTicks(t, world, 1)
+
+	if len(events.Damaged) != 1 || len(events.Died) != 1 {
+		t.Fatalf("%d damaged and %d died events, want 1 and 1", len(events.Damaged), len(events.Died))
+	}
+
+	world.UnregisterEntity(e.ID())
+	ecstest.AdvanceTicks(t, world, 1)
+
+	if len(events.Damaged) != 0 || len(events.Died) != 0 {
+		t.Errorf("%d damaged and %d died events after a step without entities, want none", len(events.Damaged), len(events.Died))
+	}
+}
@@ -0,0 +1,85 @@
+package ecs
+
+import (
+	"slices"
+
+	"github.com/jtbonhomme/ebiten-ecs/component"
+	"github.com/jtbonhomme/ebiten-ecs/entity"
+)
+
+// Hook is a function called when an entity is registered in or unregistered from the world,
+// with the components of the entity.
+type Hook func(id entity.ID, c []component.Component)
+
+// HookID identifies a hook added to the world, to remove it with RemoveHook.
+type HookID uint64
+
+// registeredHook is a hook added to the world, with its ID.
+type registeredHook struct {
+	id   HookID
+	hook Hook
+}
+
+// OnSpawn adds a hook called each time a new entity is registered, once its components are,
+// so that subsystems (spatial index, physics bridge, audio...) keep their own structures in sync with the world.
+// Registering more components for an already registered entity does not call the hooks again.
+// It returns the ID of the hook, to remove it when the subsystem is closed.
+func (ecs *ECS) OnSpawn(hook Hook) HookID {
+	ecs.hookID++
+	ecs.spawnHooks = append(ecs.spawnHooks, registeredHook{id: ecs.hookID, hook: hook})
+
+	return ecs.hookID
+}
+
+// OnDespawn adds a hook called each time an entity is unregistered, including by Clear and Group.Despawn,
+// before its components are removed. It returns the ID of the hook, to remove it when the subsystem is closed.
+func (ecs *ECS) OnDespawn(hook Hook) HookID {
+	ecs.hookID++
+	ecs.despawnHooks = append(ecs.despawnHooks, registeredHook{id: ecs.hookID, hook: hook})
+
+	return ecs.hookID
+}
+
+// RemoveHook removes a hook added with OnSpawn or OnDespawn. Removing a hook which was already removed does nothing.
+// A hook can be removed from a hook: the hooks of the running spawn or despawn are still all called.
+func (ecs *ECS) RemoveHook(id HookID) {
+	removed := func(h registeredHook) bool {
+		return h.id == id
+	}
+
+	// the hooks are copied, as they may be iterated over by the caller
+	ecs.spawnHooks = slices.DeleteFunc(slices.Clone(ecs.spawnHooks), removed)
+	ecs.despawnHooks = slices.DeleteFunc(slices.Clone(ecs.despawnHooks), removed)
+}
+
+// spawned calls the spawn hooks for a newly registered entity.
+func (ecs *ECS) spawned(id entity.ID) {
+	for _, h := range ecs.spawnHooks {
+		h.hook(id, ecs.componentsRegistry[id])
+	}
+}
+
+// despawned calls the despawn hooks for an entity about to be unregistered.
+func (ecs *ECS) despawned(id entity.ID) {
+	for _, h := range ecs.despawnHooks {
+		h.hook(id, ecs.componentsRegistry[id])
+	}
+}
+
+// despawnedAll calls the despawn hooks for all the entities of the world, by increasing ID.
+func (ecs *ECS) despawnedAll() {
+	if len(ecs.despawnHooks) == 0 {
+		return
+	}
+
+	ids := make([]entity.ID, 0, len(ecs.componentsRegistry))
+	for id := range ecs.componentsRegistry {
+		ids = append(ids, id)
+	}
+
+	slices.Sort(ids)
+
+	for _, id := range ids {
+		ecs.despawned(id)
+	}
+}
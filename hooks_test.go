@@ -0,0 +1,31 @@
+package ecs_test
+
+import (
+	"testing"
+
+	ecs "github.com/jtbonhomme/ebiten-ecs"
+	"github.com/jtbonhomme/ebiten-ecs/component"
+	"github.com/jtbonhomme/ebiten-ecs/entity"
+)
+
+func TestRemoveHook(t *testing.T) {
+	world := ecs.New()
+	spawns, despawns := 0, 0
+
+	spawn := world.OnSpawn(func(entity.ID, []component.Component) { spawns++ })
+	despawn := world.OnDespawn(func(entity.ID, []component.Component) { despawns++ })
+
+	e := world.Spawn(&position{})
+	world.UnregisterEntity(e.ID())
+
+	world.RemoveHook(spawn)
+	world.RemoveHook(despawn)
+	world.RemoveHook(despawn)
+
+	e = world.Spawn(&position{})
+	world.UnregisterEntity(e.ID())
+
+	if spawns != 1 || despawns != 1 {
+		t.Errorf("%d spawns and %d despawns, want 1 and 1", spawns, despawns)
+	}
+}
@@ -0,0 +1,164 @@
+// Package hud provides heads-up display primitives: a Score resource, and Gauge and Counter components
+// laid out like UI widgets and drawn by a drawer registered on the UI layer, so that health bars and
+// score counters need no custom draw code.
+package hud
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+
+	ecs "github.com/jtbonhomme/ebiten-ecs"
+	"github.com/jtbonhomme/ebiten-ecs/component"
+	"github.com/jtbonhomme/ebiten-ecs/health"
+	"github.com/jtbonhomme/ebiten-ecs/system"
+	"github.com/jtbonhomme/ebiten-ecs/text"
+	"github.com/jtbonhomme/ebiten-ecs/ui"
+)
+
+// Score is the resource holding the score of the game, and the best score reached.
+type Score struct {
+	Value int
+	Best  int
+}
+
+// Add adds points to the score, and updates the best score.
+func (s *Score) Add(points int) {
+	s.Value += points
+	s.Best = max(s.Best, s.Value)
+}
+
+// Reset sets the score back to 0, keeping the best score.
+func (s *Score) Reset() {
+	s.Value = 0
+}
+
+// Gauge is the component of the widgets drawn as a bar filled proportionally to a value.
+// If the entity also has a health.Health component, the gauge shows its health and Value and Max are ignored.
+type Gauge struct {
+	Value float64
+	Max   float64
+	// Color is the color of the filled part, Background of the empty part and Border of the outline.
+	// Nil colors are not drawn, but Color defaults to white.
+	Color      color.Color
+	Background color.Color
+	Border     color.Color
+	// Vertical gauges fill from the bottom up, the others from left to right.
+	Vertical bool
+}
+
+// fraction returns the filled fraction of the gauge, between 0 and 1.
+func (g *Gauge) fraction(c []component.Component) float64 {
+	value, maxValue := g.Value, g.Max
+	if h := healthType.Get(c); h != nil {
+		value, maxValue = h.Current, h.Max
+	}
+
+	if maxValue <= 0 {
+		return 0
+	}
+
+	return min(max(value/maxValue, 0), 1)
+}
+
+// Counter is the component of the widgets displaying the score of the world, formatted with Format
+// ("%[1]d" if empty) which receives the score and the best score as explicitly indexed arguments,
+// e.g. "Score: %[1]d  Best: %[2]d".
+type Counter struct {
+	Format string
+	Face   text.Face
+	Color  color.Color
+	Align  text.Align
+	text   text.Text
+}
+
+var (
+	widgetType  = component.NewType[ui.Widget]()
+	gaugeType   = component.NewType[Gauge]()
+	counterType = component.NewType[Counter]()
+	healthType  = component.NewType[health.Health]()
+)
+
+// Drawer draws the gauges and counters of its entities in screen space, at the position of their ui.Widget component.
+// It is meant to be registered at the ui.Layer z-index.
+type Drawer struct {
+	id    system.ID
+	world *ecs.ECS
+}
+
+// NewDrawer creates the drawer of the HUD, displaying the Score resource of the world.
+func NewDrawer(world *ecs.ECS) *Drawer {
+	return &Drawer{
+		id:    system.AssignID(),
+		world: world,
+	}
+}
+
+// ID returns the unique ID of the drawer.
+func (d *Drawer) ID() system.ID {
+	return d.id
+}
+
+// Draw lays the widget of the entity out on the screen, and draws its gauge or counter.
+func (d *Drawer) Draw(screen *ebiten.Image, c []component.Component) {
+	w := widgetType.Get(c)
+	if w == nil || w.Hidden {
+		return
+	}
+
+	size := screen.Bounds().Size()
+	r := w.Layout(size.X, size.Y)
+
+	if g := gaugeType.Get(c); g != nil {
+		x, y, width, height := float32(r.Min.X), float32(r.Min.Y), float32(r.Dx()), float32(r.Dy())
+
+		if g.Background != nil {
+			vector.DrawFilledRect(screen, x, y, width, height, g.Background, false)
+		}
+
+		clr := g.Color
+		if clr == nil {
+			clr = color.White
+		}
+
+		f := float32(g.fraction(c))
+		if g.Vertical {
+			vector.DrawFilledRect(screen, x, y+height*(1-f), width, height*f, clr, false)
+		} else {
+			vector.DrawFilledRect(screen, x, y, width*f, height, clr, false)
+		}
+
+		if g.Border != nil {
+			vector.StrokeRect(screen, x, y, width, height, 1, g.Border, false)
+		}
+	}
+
+	if cnt := counterType.Get(c); cnt != nil && cnt.Face != nil {
+		score := ecs.Resource[Score](d.world)
+		if score == nil {
+			return
+		}
+
+		format := cnt.Format
+		if format == "" {
+			format = "%[1]d"
+		}
+
+		cnt.text.Value = fmt.Sprintf(format, score.Value, score.Best)
+		cnt.text.Face, cnt.text.Color, cnt.text.Align = cnt.Face, cnt.Color, cnt.Align
+		cnt.text.Y = float64(r.Min.Y) + (float64(r.Dy())-cnt.Face.LineHeight())/2
+
+		switch cnt.Align {
+		case text.AlignCenter:
+			cnt.text.X = float64(r.Min.X) + float64(r.Dx())/2
+		case text.AlignRight:
+			cnt.text.X = float64(r.Max.X)
+		default:
+			cnt.text.X = float64(r.Min.X)
+		}
+
+		cnt.text.Draw(screen)
+	}
+}
@@ -0,0 +1,87 @@
+package input
+
+// EventKind is the kind of an input event.
+type EventKind int
+
+// Kinds of input events.
+const (
+	KeyPressed EventKind = iota
+	KeyReleased
+	MouseButtonPressed
+	MouseButtonReleased
+	MouseMoved
+	Wheel
+)
+
+// String returns the name of the event kind.
+func (k EventKind) String() string {
+	switch k {
+	case KeyPressed:
+		return "KeyPressed"
+	case KeyReleased:
+		return "KeyReleased"
+	case MouseButtonPressed:
+		return "MouseButtonPressed"
+	case MouseButtonReleased:
+		return "MouseButtonReleased"
+	case MouseMoved:
+		return "MouseMoved"
+	case Wheel:
+		return "Wheel"
+	default:
+		return "Unknown"
+	}
+}
+
+// Event is a change of the input between the previous step and the current one.
+// Only the fields related to its kind are set: Key for key events, Button for mouse button events,
+// X and Y for every mouse event, and WheelX and WheelY for wheel events.
+type Event struct {
+	Kind   EventKind
+	Key    Key
+	Button MouseButton
+	X      int
+	Y      int
+	WheelX float64
+	WheelY float64
+}
+
+// appendEvents appends to dst the events leading from the previous snapshot to the current one:
+// key presses, key releases, button presses, button releases, then cursor moves and wheel scrolls.
+func appendEvents(dst []Event, previous, current *Snapshot) []Event {
+	x, y := current.CursorX, current.CursorY
+
+	for _, k := range current.Keys {
+		if !previous.IsKeyPressed(k) {
+			dst = append(dst, Event{Kind: KeyPressed, Key: k})
+		}
+	}
+
+	for _, k := range previous.Keys {
+		if !current.IsKeyPressed(k) {
+			dst = append(dst, Event{Kind: KeyReleased, Key: k})
+		}
+	}
+
+	for _, b := range current.MouseButtons {
+		if !previous.IsMouseButtonPressed(b) {
+			dst = append(dst, Event{Kind: MouseButtonPressed, Button: b, X: x, Y: y})
+		}
+	}
+
+	for _, b := range previous.MouseButtons {
+		if !current.IsMouseButtonPressed(b) {
+			dst = append(dst, Event{Kind: MouseButtonReleased, Button: b, X: x, Y: y})
+		}
+	}
+
+	if x != previous.CursorX || y != previous.CursorY {
+		dst = append(dst, Event{Kind: MouseMoved, X: x, Y: y})
+	}
+
+	if current.WheelX != 0 || current.WheelY != 0 {
+		dst = append(dst, Event{Kind: Wheel, X: x, Y: y, WheelX: current.WheelX, WheelY: current.WheelY})
+	}
+
+	return dst
+}
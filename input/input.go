@@ -0,0 +1,125 @@
+// Package input provides snapshots of the player input, captured once per simulation step,
+// so that every system sees the same input during a step and so that input can be recorded and replayed.
+package input
+
+// Key is a keyboard key, with the same values as ebiten.Key.
+type Key int
+
+// MouseButton is a mouse button, with the same values as ebiten.MouseButton.
+type MouseButton int
+
+// Source fills a snapshot with the current state of the input devices, reusing its slices.
+// The ebitenecs package provides a source reading the Ebiten input devices.
+type Source func(s *Snapshot)
+
+// Snapshot is the state of the input devices at a given step.
+type Snapshot struct {
+	Keys         []Key         `json:"keys,omitempty"`
+	MouseButtons []MouseButton `json:"buttons,omitempty"`
+	CursorX      int           `json:"x"`
+	CursorY      int           `json:"y"`
+	WheelX       float64       `json:"wx,omitempty"`
+	WheelY       float64       `json:"wy,omitempty"`
+}
+
+// Reset empties the snapshot, keeping the memory of its slices.
+func (s *Snapshot) Reset() {
+	*s = Snapshot{Keys: s.Keys[:0], MouseButtons: s.MouseButtons[:0]}
+}
+
+// CopyFrom copies another snapshot into s, reusing its slices.
+func (s *Snapshot) CopyFrom(o *Snapshot) {
+	s.Keys = append(s.Keys[:0], o.Keys...)
+	s.MouseButtons = append(s.MouseButtons[:0], o.MouseButtons...)
+	s.CursorX, s.CursorY = o.CursorX, o.CursorY
+	s.WheelX, s.WheelY = o.WheelX, o.WheelY
+}
+
+// IsKeyPressed returns true if the key is pressed in the snapshot.
+func (s *Snapshot) IsKeyPressed(key Key) bool {
+	for _, k := range s.Keys {
+		if k == key {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsMouseButtonPressed returns true if the mouse button is pressed in the snapshot.
+func (s *Snapshot) IsMouseButtonPressed(button MouseButton) bool {
+	for _, b := range s.MouseButtons {
+		if b == button {
+			return true
+		}
+	}
+
+	return false
+}
+
+// State is the input of the current step, along with the input of the previous step
+// to detect presses and releases. The world maintains it as a resource.
+type State struct {
+	Current  Snapshot
+	Previous Snapshot
+	// Events are the changes of the input at the current step, so that systems can react to them
+	// instead of polling every key: every system sees the same events during a step.
+	Events []Event
+}
+
+// Next moves the current snapshot to the previous one, sets the current snapshot and computes the events of the step.
+func (s *State) Next(current *Snapshot) {
+	s.Previous, s.Current = s.Current, s.Previous
+	s.Current.CopyFrom(current)
+	s.Events = appendEvents(s.Events[:0], &s.Previous, &s.Current)
+}
+
+// Set replaces the current snapshot and the previous one, and computes the events of the step again,
+// e.g. when a step is simulated again with a corrected input.
+func (s *State) Set(previous, current *Snapshot) {
+	s.Previous.CopyFrom(previous)
+	s.Current.CopyFrom(current)
+	s.Events = appendEvents(s.Events[:0], &s.Previous, &s.Current)
+}
+
+// CopyFrom copies another state into s, reusing its slices.
+func (s *State) CopyFrom(o *State) {
+	s.Current.CopyFrom(&o.Current)
+	s.Previous.CopyFrom(&o.Previous)
+	s.Events = append(s.Events[:0], o.Events...)
+}
+
+// IsKeyPressed returns true if the key is pressed at the current step.
+func (s *State) IsKeyPressed(key Key) bool {
+	return s.Current.IsKeyPressed(key)
+}
+
+// IsKeyJustPressed returns true if the key has been pressed at the current step.
+func (s *State) IsKeyJustPressed(key Key) bool {
+	return s.Current.IsKeyPressed(key) && !s.Previous.IsKeyPressed(key)
+}
+
+// IsKeyJustReleased returns true if the key has been released at the current step.
+func (s *State) IsKeyJustReleased(key Key) bool {
+	return !s.Current.IsKeyPressed(key) && s.Previous.IsKeyPressed(key)
+}
+
+// IsMouseButtonPressed returns true if the mouse button is pressed at the current step.
+func (s *State) IsMouseButtonPressed(button MouseButton) bool {
+	return s.Current.IsMouseButtonPressed(button)
+}
+
+// IsMouseButtonJustPressed returns true if the mouse button has been pressed at the current step.
+func (s *State) IsMouseButtonJustPressed(button MouseButton) bool {
+	return s.Current.IsMouseButtonPressed(button) && !s.Previous.IsMouseButtonPressed(button)
+}
+
+// IsMouseButtonJustReleased returns true if the mouse button has been released at the current step.
+func (s *State) IsMouseButtonJustReleased(button MouseButton) bool {
+	return !s.Current.IsMouseButtonPressed(button) && s.Previous.IsMouseButtonPressed(button)
+}
+
+// CursorPosition returns the position of the cursor at the current step.
+func (s *State) CursorPosition() (int, int) {
+	return s.Current.CursorX, s.Current.CursorY
+}
@@ -0,0 +1,100 @@
+package input
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+)
+
+// header is the first line of a recording, holding the seed and the state of the recorded simulation.
+type header struct {
+	Seed  int64           `json:"seed"`
+	State json.RawMessage `json:"state,omitempty"`
+}
+
+// Recorder writes a recording: a header with the seed, followed by one JSON snapshot per line and per step.
+type Recorder struct {
+	w   *bufio.Writer
+	enc *json.Encoder
+}
+
+// NewRecorder creates a recorder writing to w, and writes the header of the recording.
+func NewRecorder(w io.Writer, seed int64) (*Recorder, error) {
+	return NewStateRecorder(w, seed, nil)
+}
+
+// NewStateRecorder creates a recorder writing to w, and writes the header of the recording with the state
+// of the simulation when the recording starts, e.g. of its random number generators, as a JSON value.
+func NewStateRecorder(w io.Writer, seed int64, state json.RawMessage) (*Recorder, error) {
+	bw := bufio.NewWriter(w)
+	r := &Recorder{
+		w:   bw,
+		enc: json.NewEncoder(bw),
+	}
+
+	err := r.enc.Encode(header{Seed: seed, State: state})
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Write appends the snapshot of a step to the recording.
+func (r *Recorder) Write(s *Snapshot) error {
+	return r.enc.Encode(s)
+}
+
+// Flush writes the buffered snapshots to the underlying writer.
+func (r *Recorder) Flush() error {
+	return r.w.Flush()
+}
+
+// Player reads a recording written by a Recorder.
+type Player struct {
+	dec   *json.Decoder
+	seed  int64
+	state json.RawMessage
+}
+
+// NewPlayer creates a player reading from r, and reads the header of the recording.
+func NewPlayer(r io.Reader) (*Player, error) {
+	p := &Player{
+		dec: json.NewDecoder(bufio.NewReader(r)),
+	}
+
+	h := header{}
+
+	err := p.dec.Decode(&h)
+	if err != nil {
+		return nil, err
+	}
+
+	p.seed = h.Seed
+	p.state = h.State
+
+	return p, nil
+}
+
+// Seed returns the seed of the recorded simulation.
+func (p *Player) Seed() int64 {
+	return p.seed
+}
+
+// State returns the state of the recorded simulation written by NewStateRecorder, nil if there is none.
+func (p *Player) State() json.RawMessage {
+	return p.state
+}
+
+// Read reads the snapshot of the next step into s.
+// It returns io.EOF at the end of the recording, and io.ErrUnexpectedEOF if the recording is truncated.
+func (p *Player) Read(s *Snapshot) error {
+	*s = Snapshot{Keys: s.Keys[:0], MouseButtons: s.MouseButtons[:0]}
+
+	return p.dec.Decode(s)
+}
+
+// More returns true if the recording holds the snapshot of another step.
+func (p *Player) More() bool {
+	return p.dec.More()
+}
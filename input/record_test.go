@@ -0,0 +1,70 @@
+package input_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/jtbonhomme/ebiten-ecs/input"
+)
+
+// record returns a recording of two steps.
+func record(t *testing.T) []byte {
+	t.Helper()
+
+	b := &bytes.Buffer{}
+
+	r, err := input.NewRecorder(b, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for x := 1; x <= 2; x++ {
+		if err := r.Write(&input.Snapshot{CursorX: x}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := r.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	return b.Bytes()
+}
+
+func TestReadEnd(t *testing.T) {
+	p, err := input.NewPlayer(bytes.NewReader(record(t)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &input.Snapshot{}
+	for x := 1; x <= 2; x++ {
+		if err := p.Read(s); err != nil || s.CursorX != x {
+			t.Fatalf("step %d: cursor X = %d, err = %v", x, s.CursorX, err)
+		}
+	}
+
+	if err := p.Read(s); err != io.EOF {
+		t.Errorf("Read at the end of the recording returned %v, want io.EOF", err)
+	}
+}
+
+func TestReadTruncated(t *testing.T) {
+	data := record(t)
+
+	p, err := input.NewPlayer(bytes.NewReader(data[:len(data)-5]))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &input.Snapshot{}
+	if err := p.Read(s); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := p.Read(s); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("Read of a truncated step returned %v, want io.ErrUnexpectedEOF", err)
+	}
+}
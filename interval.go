@@ -0,0 +1,74 @@
+package ecs
+
+import (
+	"time"
+
+	"github.com/jtbonhomme/ebiten-ecs/system"
+)
+
+// DefaultStep is the simulated duration of a step in a deterministic world without a fixed timestep.
+const DefaultStep = time.Second / 60
+
+// stepDuration returns the simulated duration of the step about to run: the fixed timestep if any,
+// DefaultStep in a deterministic world, and the real time elapsed since the previous step otherwise,
+// scaled by the time scale.
+func (ecs *ECS) stepDuration() time.Duration {
+	if ecs.config.fixedStep > 0 {
+		return ecs.config.fixedStep
+	}
+
+	if ecs.config.deterministic {
+		return DefaultStep
+	}
+
+	if ecs.repeating {
+		return ecs.dt
+	}
+
+	now := time.Now()
+	defer func() {
+		ecs.lastStep = now
+	}()
+
+	if ecs.lastStep.IsZero() {
+		return 0
+	}
+
+	return ecs.scaled(now.Sub(ecs.lastStep))
+}
+
+// advanceTime advances the simulated time by the duration of the step. With a constant step duration,
+// the simulated time only depends on the tick, so that it is rolled back along with it.
+func (ecs *ECS) advanceTime() {
+	if ecs.config.fixedStep > 0 || ecs.config.deterministic {
+		ecs.elapsed = time.Duration(ecs.tick) * ecs.dt
+		return
+	}
+
+	ecs.elapsed += ecs.dt
+}
+
+// due returns true if an updater has to run during the current step, according to its interval if it has one.
+// Whether it is due only depends on the tick and the simulated time, so that rollbacks and replays run
+// the same updaters.
+func (ecs *ECS) due(s system.Updater) bool {
+	if ecs.tick <= 1 {
+		return true
+	}
+
+	if p, ok := unwrap(s).(system.Periodic); ok {
+		n := p.RunEvery()
+		if n > 1 && (ecs.tick-1)%uint64(n) != 0 {
+			return false
+		}
+	}
+
+	if t, ok := unwrap(s).(system.Timed); ok {
+		period := t.RunEveryDuration()
+		if period > 0 && ecs.elapsed/period == (ecs.elapsed-ecs.dt)/period {
+			return false
+		}
+	}
+
+	return true
+}
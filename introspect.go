@@ -0,0 +1,33 @@
+package ecs
+
+import (
+	"reflect"
+
+	"github.com/jtbonhomme/ebiten-ecs/component"
+	"github.com/jtbonhomme/ebiten-ecs/entity"
+)
+
+// HasComponent returns true if the entity has a component of the same type as data, e.g. &Position{}.
+// It uses reflection: prefer Has, or Type.Has in the hot path.
+func (ecs *ECS) HasComponent(id entity.ID, data interface{}) bool {
+	return hasType(ecs.componentsRegistry[id], component.TypeIDOf(data))
+}
+
+// Has returns true if the entity has a component of type *T.
+// It looks the type up on each call: in the hot path, prefer a Type handle stored in a package variable.
+func Has[T any](ecs *ECS, id entity.ID) bool {
+	return component.NewType[T]().Has(ecs.componentsRegistry[id])
+}
+
+// Components returns the types of the components of an entity, in the order they were registered,
+// e.g. *game.Position. It is meant for debug tools and editors.
+func (ecs *ECS) Components(id entity.ID) []reflect.Type {
+	components := ecs.componentsRegistry[id]
+
+	types := make([]reflect.Type, len(components))
+	for i, c := range components {
+		types[i] = reflect.TypeOf(c.Data())
+	}
+
+	return types
+}
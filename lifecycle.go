@@ -0,0 +1,77 @@
+package ecs
+
+import (
+	"fmt"
+
+	"github.com/jtbonhomme/ebiten-ecs/system"
+)
+
+// Initializer is implemented by systems which need to be set up when they are registered in a world,
+// e.g. to allocate buffers, add resources or hooks, instead of doing it lazily in Update.
+type Initializer interface {
+	Init(world *ECS) error
+}
+
+// Finalizer is implemented by systems which need to release resources when they are unregistered from a world,
+// or when the world shuts down.
+type Finalizer interface {
+	Shutdown()
+}
+
+// InitSystem initializes a newly registered system if it implements Initializer.
+// An initialization error is returned by the next call to Update, which does not run any step.
+// It is called by RegisterUpdater, and meant for packages registering their own kind of systems, such as drawers.
+func (ecs *ECS) InitSystem(s system.System) {
+	i, ok := unwrap(s).(Initializer)
+	if !ok {
+		return
+	}
+
+	err := i.Init(ecs)
+	if err != nil && ecs.initErr == nil {
+		ecs.initErr = fmt.Errorf("init system %s: %w", s.ID(), err)
+	}
+}
+
+// ShutdownSystem shuts an unregistered system down if it implements Finalizer.
+// It is called by UnregisterUpdater and Shutdown, and meant for packages unregistering their own kind of systems.
+func (ecs *ECS) ShutdownSystem(s system.System) {
+	if f, ok := unwrap(s).(Finalizer); ok {
+		f.Shutdown()
+	}
+}
+
+// UnregisterUpdater removes the updater with the given ID from the world, along with its associations
+// with entities, its run conditions and its system group, and shuts it down. It can be called from a running updater:
+// the updaters of the current step still run. It returns false if there is no such updater.
+func (ecs *ECS) UnregisterUpdater(id system.ID) bool {
+	for i, s := range ecs.updaters {
+		if s.ID() != id {
+			continue
+		}
+
+		// build a new slice, so that a step iterating over the updaters is not disturbed
+		updaters := make([]system.Updater, 0, len(ecs.updaters)-1)
+		updaters = append(updaters, ecs.updaters[:i]...)
+		ecs.updaters = append(updaters, ecs.updaters[i+1:]...)
+
+		delete(ecs.entitiesRegistry, id)
+		delete(ecs.conditions, id)
+		delete(ecs.groupOf, id)
+		ecs.ReleaseSystems(1)
+		ecs.ShutdownSystem(s)
+
+		return true
+	}
+
+	return false
+}
+
+// Shutdown shuts down the updaters of the world, in the reverse order of their execution,
+// so that they release their resources deterministically when the game exits.
+// The updaters stay registered: the world is not meant to be updated anymore.
+func (ecs *ECS) Shutdown() {
+	for i := len(ecs.updaters) - 1; i >= 0; i-- {
+		ecs.ShutdownSystem(ecs.updaters[i])
+	}
+}
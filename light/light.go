@@ -0,0 +1,280 @@
+// Package light provides 2D lighting: Light and Occluder components, and a post-processing effect rendering
+// a light map from them, optionally with hard shadows cast by the occluders, multiplied over the world pass.
+package light
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"github.com/jtbonhomme/ebiten-ecs/component"
+	"github.com/jtbonhomme/ebiten-ecs/ebitenecs"
+	"github.com/jtbonhomme/ebiten-ecs/entity"
+	"github.com/jtbonhomme/ebiten-ecs/system"
+)
+
+// Light is the component of the entities emitting light, at a position of the world.
+type Light struct {
+	X         float64
+	Y         float64
+	Radius    float64
+	Color     color.Color
+	Intensity float64
+}
+
+// Occluder is the component of the entities blocking light, as a box of the world.
+type Occluder struct {
+	X      float64
+	Y      float64
+	Width  float64
+	Height float64
+}
+
+var (
+	lightType    = component.NewType[Light]()
+	occluderType = component.NewType[Occluder]()
+)
+
+// gradientSize is the size of the radial gradient texture the lights are drawn with.
+const gradientSize = 256
+
+// Lighting is the post-processing effect lighting the world pass. The lights and occluders are found on the entities
+// associated with it, and drawn from the point of view of the camera of the world. Each light is rendered on its own
+// before being added to the light map, so that shadows only hide the light casting them.
+type Lighting struct {
+	id system.ID
+	// Ambient is the light of the areas lit by no light.
+	Ambient color.Color
+	// Shadows enables the hard shadows cast by the occluders.
+	Shadows   bool
+	world     *ebitenecs.World
+	lightMap  *ebiten.Image
+	scratch   *ebiten.Image
+	gradient  *ebiten.Image
+	white     *ebiten.Image
+	occluders [][4][2]float64
+	vertices  []ebiten.Vertex
+	indices   []uint16
+	op        ebiten.DrawImageOptions
+	shadowOp  ebiten.DrawTrianglesOptions
+}
+
+// New creates the lighting of the world, with a dark ambient light and no shadows.
+// It is enabled with world.AddPostEffect, and lights and occluders are added with Add.
+func New(world *ebitenecs.World) *Lighting {
+	white := ebiten.NewImage(3, 3)
+	white.Fill(color.White)
+
+	return &Lighting{
+		id:       system.AssignID(),
+		Ambient:  color.Gray{Y: 32},
+		world:    world,
+		gradient: newGradient(),
+		white:    white.SubImage(image.Rect(1, 1, 2, 2)).(*ebiten.Image),
+	}
+}
+
+// newGradient creates a white radial gradient, opaque at its center and transparent on its edge.
+func newGradient() *ebiten.Image {
+	pixels := make([]byte, gradientSize*gradientSize*4)
+	half := float64(gradientSize) / 2
+
+	for y := 0; y < gradientSize; y++ {
+		for x := 0; x < gradientSize; x++ {
+			d := math.Hypot(float64(x)+0.5-half, float64(y)+0.5-half) / half
+			a := byte(255 * math.Max(0, 1-d) * math.Max(0, 1-d))
+
+			i := (y*gradientSize + x) * 4
+			// premultiplied alpha
+			pixels[i], pixels[i+1], pixels[i+2], pixels[i+3] = a, a, a, a
+		}
+	}
+
+	img := ebiten.NewImage(gradientSize, gradientSize)
+	img.WritePixels(pixels)
+
+	return img
+}
+
+// ID returns the unique ID of the lighting, with which lights and occluders are associated.
+func (l *Lighting) ID() system.ID {
+	return l.id
+}
+
+// Add associates entities with a Light or an Occluder component with the lighting.
+func (l *Lighting) Add(e ...entity.Entity) {
+	l.world.Associate(l, e...)
+}
+
+// Apply draws the world pass, then multiplies it by the light map.
+func (l *Lighting) Apply(dst, src *ebiten.Image) {
+	size := src.Bounds().Size()
+	l.lightMap = resize(l.lightMap, size)
+	l.scratch = resize(l.scratch, size)
+
+	camera := l.world.Camera()
+	geoM := camera.GeoM(size.X, size.Y)
+	zoom := math.Sqrt(math.Abs(geoM.Element(0, 0)*geoM.Element(1, 1) - geoM.Element(0, 1)*geoM.Element(1, 0)))
+
+	l.lightMap.Fill(l.Ambient)
+	l.collectOccluders(geoM)
+
+	for _, e := range l.world.FilterEntities(l) {
+		if !l.world.IsActive(e.ID()) {
+			continue
+		}
+
+		lt := lightType.Get(l.world.EntityComponents(e.ID()))
+		if lt == nil || lt.Radius <= 0 {
+			continue
+		}
+
+		x, y := geoM.Apply(lt.X, lt.Y)
+		l.drawLight(lt, x, y, lt.Radius*zoom)
+	}
+
+	l.op.GeoM.Reset()
+	l.op.ColorScale.Reset()
+	l.op.Blend = ebiten.Blend{}
+	dst.DrawImage(src, &l.op)
+
+	l.op.Blend = ebiten.Blend{
+		BlendFactorSourceRGB:        ebiten.BlendFactorDestinationColor,
+		BlendFactorSourceAlpha:      ebiten.BlendFactorZero,
+		BlendFactorDestinationRGB:   ebiten.BlendFactorZero,
+		BlendFactorDestinationAlpha: ebiten.BlendFactorOne,
+		BlendOperationRGB:           ebiten.BlendOperationAdd,
+		BlendOperationAlpha:         ebiten.BlendOperationAdd,
+	}
+	dst.DrawImage(l.lightMap, &l.op)
+	l.op.Blend = ebiten.Blend{}
+}
+
+// resize returns an image of the given size, reusing img if it already has this size.
+func resize(img *ebiten.Image, size image.Point) *ebiten.Image {
+	if img != nil && img.Bounds().Size() == size {
+		return img
+	}
+
+	if img != nil {
+		img.Deallocate()
+	}
+
+	return ebiten.NewImage(size.X, size.Y)
+}
+
+// collectOccluders computes the screen corners of the occluders, if shadows are enabled.
+func (l *Lighting) collectOccluders(geoM ebiten.GeoM) {
+	l.occluders = l.occluders[:0]
+	if !l.Shadows {
+		return
+	}
+
+	for _, e := range l.world.FilterEntities(l) {
+		if !l.world.IsActive(e.ID()) {
+			continue
+		}
+
+		o := occluderType.Get(l.world.EntityComponents(e.ID()))
+		if o == nil {
+			continue
+		}
+
+		var corners [4][2]float64
+		for i, p := range [4][2]float64{{0, 0}, {o.Width, 0}, {o.Width, o.Height}, {0, o.Height}} {
+			corners[i][0], corners[i][1] = geoM.Apply(o.X+p[0], o.Y+p[1])
+		}
+
+		l.occluders = append(l.occluders, corners)
+	}
+}
+
+// drawLight renders a light centered at the given screen position on the scratch image,
+// hides the parts in the shadow of the occluders, and adds it to the light map.
+func (l *Lighting) drawLight(lt *Light, x, y, radius float64) {
+	l.scratch.Clear()
+
+	intensity := lt.Intensity
+	if intensity == 0 {
+		intensity = 1
+	}
+
+	clr := lt.Color
+	if clr == nil {
+		clr = color.White
+	}
+
+	scale := 2 * radius / gradientSize
+
+	l.op.GeoM.Reset()
+	l.op.GeoM.Scale(scale, scale)
+	l.op.GeoM.Translate(x-radius, y-radius)
+	l.op.ColorScale.Reset()
+	l.op.ColorScale.ScaleWithColor(clr)
+	l.op.ColorScale.Scale(float32(intensity), float32(intensity), float32(intensity), 1)
+	l.op.Blend = ebiten.Blend{}
+	l.scratch.DrawImage(l.gradient, &l.op)
+
+	if len(l.occluders) > 0 {
+		l.drawShadows(x, y, radius)
+	}
+
+	l.op.GeoM.Reset()
+	l.op.ColorScale.Reset()
+	l.op.Blend = ebiten.BlendLighter
+	l.lightMap.DrawImage(l.scratch, &l.op)
+}
+
+// drawShadows clears the parts of the scratch image in the shadow of the occluders, seen from the light.
+// Each edge of an occluder casts a quad stretching away from the light beyond its radius.
+func (l *Lighting) drawShadows(x, y, radius float64) {
+	l.vertices = l.vertices[:0]
+	l.indices = l.indices[:0]
+
+	for _, corners := range l.occluders {
+		for i := range corners {
+			a, b := corners[i], corners[(i+1)%4]
+			if len(l.vertices)+4 > math.MaxUint16 {
+				break
+			}
+
+			n := uint16(len(l.vertices))
+			ax, ay := project(a, x, y, radius)
+			bx, by := project(b, x, y, radius)
+
+			l.vertices = append(l.vertices,
+				shadowVertex(a[0], a[1]), shadowVertex(b[0], b[1]),
+				shadowVertex(bx, by), shadowVertex(ax, ay),
+			)
+			l.indices = append(l.indices, n, n+1, n+2, n, n+2, n+3)
+		}
+	}
+
+	l.shadowOp.Blend = ebiten.BlendClear
+	l.scratch.DrawTriangles(l.vertices, l.indices, l.white, &l.shadowOp)
+}
+
+// project returns the point p pushed away from the light, far enough to be out of its radius.
+func project(p [2]float64, x, y, radius float64) (float64, float64) {
+	dx, dy := p[0]-x, p[1]-y
+
+	d := math.Hypot(dx, dy)
+	if d == 0 {
+		return p[0], p[1]
+	}
+
+	k := (2*radius + d) / d
+
+	return x + dx*k, y + dy*k
+}
+
+// shadowVertex returns an opaque vertex at the given position, sampling the white image.
+func shadowVertex(x, y float64) ebiten.Vertex {
+	return ebiten.Vertex{
+		DstX: float32(x), DstY: float32(y),
+		SrcX: 1, SrcY: 1,
+		ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1,
+	}
+}
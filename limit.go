@@ -0,0 +1,79 @@
+package ecs
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrLimitReached is matched, with errors.Is, by the errors reporting that a capacity limit of the world is reached.
+var ErrLimitReached = errors.New("capacity limit reached")
+
+// LimitError is the error a world panics with when registering an entity or a system would exceed
+// the limit set with WithEntityLimit or WithSystemLimit.
+type LimitError struct {
+	// Kind is what is limited: "entities" or "systems".
+	Kind  string
+	Limit int
+}
+
+// Error returns a description of the limit reached.
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("the world cannot hold more than %d %s", e.Limit, e.Kind)
+}
+
+// Is returns true if target is ErrLimitReached.
+func (e *LimitError) Is(target error) bool {
+	return target == ErrLimitReached
+}
+
+// WithEntityLimit sets a hard limit on the number of entities registered in the world, e.g. to budget memory
+// on WASM or mobile targets. Registering an entity beyond the limit panics with a *LimitError:
+// use CheckEntityLimit to find out beforehand. There is no limit if n is 0, which is the default.
+func WithEntityLimit(n int) Option {
+	return func(c *config) {
+		c.entityLimit = n
+	}
+}
+
+// WithSystemLimit sets a hard limit on the number of systems registered in the world, updaters and drawers alike.
+// Registering a system beyond the limit panics with a *LimitError. There is no limit if n is 0, which is the default.
+func WithSystemLimit(n int) Option {
+	return func(c *config) {
+		c.systemLimit = n
+	}
+}
+
+// CheckEntityLimit returns a *LimitError if registering n more entities would exceed the entity limit of the world.
+func (ecs *ECS) CheckEntityLimit(n int) error {
+	limit := ecs.config.entityLimit
+	if limit > 0 && len(ecs.componentsRegistry)+n > limit {
+		return &LimitError{Kind: "entities", Limit: limit}
+	}
+
+	return nil
+}
+
+// ReserveSystems counts n more systems against the system limit of the world, panicking with a *LimitError
+// if it is exceeded. Updaters are counted by RegisterUpdater: it is meant for packages running their own kind
+// of systems, such as drawers.
+func (ecs *ECS) ReserveSystems(n int) {
+	limit := ecs.config.systemLimit
+	if limit > 0 && ecs.systems+n > limit {
+		panic(&LimitError{Kind: "systems", Limit: limit})
+	}
+
+	ecs.systems += n
+}
+
+// ReleaseSystems stops counting n systems unregistered by a package against the system limit of the world.
+func (ecs *ECS) ReleaseSystems(n int) {
+	ecs.systems = max(ecs.systems-n, 0)
+}
+
+// reserveEntities panics with a *LimitError if registering n more entities would exceed the entity limit.
+func (ecs *ECS) reserveEntities(n int) {
+	err := ecs.CheckEntityLimit(n)
+	if err != nil {
+		panic(err)
+	}
+}
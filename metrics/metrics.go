@@ -0,0 +1,228 @@
+// Package metrics publishes the metrics of a world (entity and component counts, per-system update durations,
+// event counts and rates) through expvar or as a Prometheus text exposition, for long-running game servers
+// built on the headless world. It has no dependency: the Prometheus handler writes the text format itself.
+//
+//	m := metrics.New(world)
+//	m.PublishExpvar("world")
+//	http.Handle("/metrics", m.Handler())
+//
+//	// in the game loop
+//	err := world.Update()
+//	m.Sample()
+package metrics
+
+import (
+	"expvar"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	ecs "github.com/jtbonhomme/ebiten-ecs"
+	"github.com/jtbonhomme/ebiten-ecs/component"
+	"github.com/jtbonhomme/ebiten-ecs/entity"
+)
+
+// SystemMetrics holds the metrics of a system.
+type SystemMetrics struct {
+	ID             int     `json:"id"`
+	Name           string  `json:"name"`
+	UpdateSeconds  float64 `json:"updateSeconds"`
+	UpdateEntities int     `json:"updateEntities"`
+}
+
+// EventMetrics holds the metrics of a kind of events.
+type EventMetrics struct {
+	Total uint64 `json:"total"`
+	// Rate is the number of events per second between the last two samples.
+	Rate float64 `json:"rate"`
+}
+
+// Snapshot holds the metrics of a world at the time of a sample.
+type Snapshot struct {
+	Tick       uint64                  `json:"tick"`
+	Entities   int                     `json:"entities"`
+	Components map[string]int          `json:"components"`
+	Memory     int64                   `json:"memoryBytes"`
+	Systems    []SystemMetrics         `json:"systems"`
+	Events     map[string]EventMetrics `json:"events"`
+}
+
+// Metrics samples the metrics of a world from the game loop, and serves the last sample to other goroutines.
+// The spawns and despawns of entities are counted as the "spawn" and "despawn" events,
+// and the events of the game are counted with Count.
+type Metrics struct {
+	world *ecs.ECS
+
+	// counts of the events since the last sample, only accessed from the game loop
+	pending map[string]uint64
+	totals  map[string]uint64
+	last    time.Time
+
+	mutex    sync.Mutex
+	snapshot Snapshot
+}
+
+// New creates the metrics of the world, and enables its profiling to measure the systems.
+func New(world *ecs.ECS) *Metrics {
+	m := &Metrics{
+		world:   world,
+		pending: make(map[string]uint64),
+		totals:  make(map[string]uint64),
+	}
+
+	world.EnableProfiling()
+	world.OnSpawn(func(entity.ID, []component.Component) {
+		m.Count("spawn", 1)
+	})
+	world.OnDespawn(func(entity.ID, []component.Component) {
+		m.Count("despawn", 1)
+	})
+
+	return m
+}
+
+// Count counts n events of the given kind, e.g. "damage" or "message". It MUST be called from the game loop.
+func (m *Metrics) Count(kind string, n int) {
+	m.pending[kind] += uint64(n)
+}
+
+// Sample samples the metrics of the world. It MUST be called from the game loop, e.g. after each update.
+func (m *Metrics) Sample() {
+	now := time.Now()
+	elapsed := now.Sub(m.last).Seconds()
+	first := m.last.IsZero()
+	m.last = now
+
+	stats := m.world.Stats()
+
+	s := Snapshot{
+		Tick:       m.world.Tick(),
+		Entities:   stats.Entities,
+		Components: stats.Components,
+		Memory:     stats.Memory,
+		Systems:    make([]SystemMetrics, 0, len(stats.Systems)),
+		Events:     make(map[string]EventMetrics, len(m.totals)),
+	}
+
+	for _, sys := range stats.Systems {
+		s.Systems = append(s.Systems, SystemMetrics{
+			ID:             int(sys.ID),
+			Name:           sys.Name,
+			UpdateSeconds:  sys.UpdateDuration.Seconds(),
+			UpdateEntities: sys.UpdateEntities,
+		})
+	}
+
+	for kind, n := range m.pending {
+		m.totals[kind] += n
+	}
+
+	for kind, total := range m.totals {
+		e := EventMetrics{Total: total}
+		if !first && elapsed > 0 {
+			e.Rate = float64(m.pending[kind]) / elapsed
+		}
+
+		s.Events[kind] = e
+	}
+
+	clear(m.pending)
+
+	m.mutex.Lock()
+	m.snapshot = s
+	m.mutex.Unlock()
+}
+
+// Snapshot returns the last sample. It is safe to call it from any goroutine.
+func (m *Metrics) Snapshot() Snapshot {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return m.snapshot
+}
+
+// PublishExpvar publishes the last sample as the expvar variable with the given name, served as JSON
+// by the /debug/vars handler of the expvar package. The function panics if the name is already published.
+func (m *Metrics) PublishExpvar(name string) {
+	expvar.Publish(name, expvar.Func(func() interface{} {
+		return m.Snapshot()
+	}))
+}
+
+// Handler returns an HTTP handler serving the last sample in the Prometheus text exposition format.
+func (m *Metrics) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_, _ = w.Write([]byte(m.Prometheus()))
+	})
+}
+
+// Prometheus returns the last sample in the Prometheus text exposition format.
+func (m *Metrics) Prometheus() string {
+	s := m.Snapshot()
+	b := &strings.Builder{}
+
+	metric := func(name, kind, help string) {
+		fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+	}
+
+	metric("ecs_tick", "counter", "Number of steps run by the world.")
+	fmt.Fprintf(b, "ecs_tick %d\n", s.Tick)
+
+	metric("ecs_entities", "gauge", "Number of registered entities.")
+	fmt.Fprintf(b, "ecs_entities %d\n", s.Entities)
+
+	metric("ecs_components", "gauge", "Number of components per type.")
+	for _, name := range sortedKeys(s.Components) {
+		fmt.Fprintf(b, "ecs_components{type=\"%s\"} %d\n", labelValue(name), s.Components[name])
+	}
+
+	metric("ecs_memory_bytes", "gauge", "Estimated memory of the components and the stores.")
+	fmt.Fprintf(b, "ecs_memory_bytes %d\n", s.Memory)
+
+	metric("ecs_system_update_seconds", "gauge", "Duration of the updates of a system during the last frame.")
+	for _, sys := range s.Systems {
+		fmt.Fprintf(b, "ecs_system_update_seconds{id=\"%d\",system=\"%s\"} %g\n", sys.ID, labelValue(sys.Name), sys.UpdateSeconds)
+	}
+
+	metric("ecs_system_update_entities", "gauge", "Number of entities updated by a system during the last frame.")
+	for _, sys := range s.Systems {
+		fmt.Fprintf(b, "ecs_system_update_entities{id=\"%d\",system=\"%s\"} %d\n", sys.ID, labelValue(sys.Name), sys.UpdateEntities)
+	}
+
+	metric("ecs_events_total", "counter", "Number of events per kind.")
+	for _, kind := range sortedKeys(s.Events) {
+		fmt.Fprintf(b, "ecs_events_total{kind=\"%s\"} %d\n", labelValue(kind), s.Events[kind].Total)
+	}
+
+	metric("ecs_events_per_second", "gauge", "Rate of events per kind between the last two samples.")
+	for _, kind := range sortedKeys(s.Events) {
+		fmt.Fprintf(b, "ecs_events_per_second{kind=\"%s\"} %g\n", labelValue(kind), s.Events[kind].Rate)
+	}
+
+	return b.String()
+}
+
+// labelEscaper escapes the characters which the Prometheus text format does not accept in label values.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// labelValue escapes a label value for the Prometheus text format, which only accepts the escape sequences
+// \\, \" and \n, unlike Go quoted strings.
+func labelValue(s string) string {
+	return labelEscaper.Replace(s)
+}
+
+// sortedKeys returns the keys of a map, sorted.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,21 @@
+package metrics_test
+
+import (
+	"strings"
+	"testing"
+
+	ecs "github.com/jtbonhomme/ebiten-ecs"
+	"github.com/jtbonhomme/ebiten-ecs/metrics"
+)
+
+func TestPrometheusEscapesLabelValues(t *testing.T) {
+	m := metrics.New(ecs.New())
+	m.Count("say \"hé\"\tto\\the\nworld", 1)
+	m.Sample()
+
+	want := `ecs_events_total{kind="say \"hé\"` + "\t" + `to\\the\nworld"} 1`
+
+	if out := m.Prometheus(); !strings.Contains(out, want) {
+		t.Errorf("the exposition does not contain %s:\n%s", want, out)
+	}
+}
@@ -0,0 +1,186 @@
+// Package minimap provides a minimap: the entities marked with an Icon component are rendered each frame
+// into a small offscreen image, showing a viewport of the world at a given scale, drawn on the UI layer.
+package minimap
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+
+	"github.com/jtbonhomme/ebiten-ecs/component"
+	"github.com/jtbonhomme/ebiten-ecs/ebitenecs"
+	"github.com/jtbonhomme/ebiten-ecs/entity"
+	"github.com/jtbonhomme/ebiten-ecs/system"
+	"github.com/jtbonhomme/ebiten-ecs/ui"
+)
+
+// Icon is the component of the entities shown on the minimap, as a square of Size pixels of the minimap.
+type Icon struct {
+	Color color.Color
+	Size  float32
+}
+
+// PositionFunc returns the position of an entity in the world, and false if it has none.
+type PositionFunc func(c []component.Component) (float64, float64, bool)
+
+// iconType is the handle used to find the Icon component.
+var iconType = component.NewType[Icon]()
+
+// Minimap is the drawer of the minimap, registered at the ui.Layer z-index of the screen pass with the entities
+// to show. Their icons are rendered once all the entities have been seen, then the minimap is drawn on screen.
+type Minimap struct {
+	id    system.ID
+	world *ebitenecs.World
+	// Widget places the minimap on screen; its Width and Height are the size of the minimap, in pixels.
+	Widget ui.Widget
+	// Scale is the number of pixels of the minimap per unit of the world.
+	Scale float64
+	// Viewport is the part of the world shown by the minimap. If it is empty, the minimap is centered on the camera.
+	Viewport image.Rectangle
+	// Background is the color the minimap is cleared with, and Border the color of its outline, if not nil.
+	Background color.Color
+	Border     color.Color
+	// Camera, if not nil, is the color of the outline of the part of the world seen by the camera.
+	Camera color.Color
+	// Position returns the position of the entities.
+	Position PositionFunc
+	image    *ebiten.Image
+	op       ebiten.DrawImageOptions
+}
+
+// New creates a minimap of the given size in pixels, anchored to the top right corner of the screen,
+// locating the entities with position.
+func New(world *ebitenecs.World, width, height int, scale float64, position PositionFunc) *Minimap {
+	return &Minimap{
+		id:    system.AssignID(),
+		world: world,
+		Widget: ui.Widget{
+			Anchor: ui.TopRight,
+			Width:  width,
+			Height: height,
+		},
+		Scale:      scale,
+		Background: color.RGBA{A: 192},
+		Border:     color.White,
+		Position:   position,
+	}
+}
+
+// ID returns the unique ID of the minimap, with which the entities to show are associated.
+func (m *Minimap) ID() system.ID {
+	return m.id
+}
+
+// Add associates entities with an Icon component with the minimap.
+func (m *Minimap) Add(e ...entity.Entity) {
+	m.world.Associate(m, e...)
+}
+
+// Draw does nothing: the icons of every entity are rendered by Flush.
+func (m *Minimap) Draw(*ebiten.Image, []component.Component) {}
+
+// Flush renders the icons of the active entities of the minimap, and draws it on screen.
+func (m *Minimap) Flush(screen *ebiten.Image) {
+	if m.Widget.Hidden || m.Widget.Width <= 0 || m.Widget.Height <= 0 || m.Position == nil {
+		return
+	}
+
+	size := screen.Bounds().Size()
+	r := m.Widget.Layout(size.X, size.Y)
+
+	if m.image == nil || m.image.Bounds().Size() != r.Size() {
+		if m.image != nil {
+			m.image.Deallocate()
+		}
+
+		m.image = ebiten.NewImage(r.Dx(), r.Dy())
+	}
+
+	m.image.Clear()
+	if m.Background != nil {
+		m.image.Fill(m.Background)
+	}
+
+	geoM := m.geoM(r.Size())
+
+	for _, e := range m.world.FilterEntities(m) {
+		if !m.world.IsActive(e.ID()) {
+			continue
+		}
+
+		c := m.world.EntityComponents(e.ID())
+
+		icon := iconType.Get(c)
+		if icon == nil {
+			continue
+		}
+
+		x, y, ok := m.Position(c)
+		if !ok {
+			continue
+		}
+
+		clr := icon.Color
+		if clr == nil {
+			clr = color.White
+		}
+
+		iconSize := max(icon.Size, 1)
+		mx, my := geoM.Apply(x, y)
+		vector.DrawFilledRect(m.image, float32(mx)-iconSize/2, float32(my)-iconSize/2, iconSize, iconSize, clr, false)
+	}
+
+	if m.Camera != nil {
+		m.drawCamera(geoM, size)
+	}
+
+	if m.Border != nil {
+		vector.StrokeRect(m.image, 0.5, 0.5, float32(r.Dx())-1, float32(r.Dy())-1, 1, m.Border, false)
+	}
+
+	m.op.GeoM.Reset()
+	m.op.GeoM.Translate(float64(r.Min.X), float64(r.Min.Y))
+	screen.DrawImage(m.image, &m.op)
+}
+
+// geoM returns the transformation from the world to the minimap of the given size.
+func (m *Minimap) geoM(size image.Point) ebiten.GeoM {
+	scale := m.Scale
+	if scale <= 0 {
+		scale = 1
+	}
+
+	var geoM ebiten.GeoM
+
+	if m.Viewport.Empty() {
+		camera := m.world.Camera()
+		geoM.Translate(-camera.X, -camera.Y)
+		geoM.Scale(scale, scale)
+		geoM.Translate(float64(size.X)/2, float64(size.Y)/2)
+
+		return geoM
+	}
+
+	geoM.Translate(-float64(m.Viewport.Min.X), -float64(m.Viewport.Min.Y))
+	geoM.Scale(scale, scale)
+
+	return geoM
+}
+
+// drawCamera draws the outline of the part of the world seen by the camera on a screen of the given size.
+func (m *Minimap) drawCamera(geoM ebiten.GeoM, screen image.Point) {
+	inverse := m.world.Camera().GeoM(screen.X, screen.Y)
+	inverse.Invert()
+
+	corners := [4][2]float64{{0, 0}, {float64(screen.X), 0}, {float64(screen.X), float64(screen.Y)}, {0, float64(screen.Y)}}
+	for i := range corners {
+		corners[i][0], corners[i][1] = geoM.Apply(inverse.Apply(corners[i][0], corners[i][1]))
+	}
+
+	for i, a := range corners {
+		b := corners[(i+1)%4]
+		vector.StrokeLine(m.image, float32(a[0]), float32(a[1]), float32(b[0]), float32(b[1]), 1, m.Camera, false)
+	}
+}
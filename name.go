@@ -0,0 +1,50 @@
+package ecs
+
+import (
+	"fmt"
+
+	"github.com/jtbonhomme/ebiten-ecs/entity"
+)
+
+// SetName gives a unique name to an entity, so that scripts, debug tools and save files can refer to it stably.
+// Naming an entity again replaces its previous name.
+// It returns an error if the name is already used by another entity.
+func (ecs *ECS) SetName(e entity.Entity, name string) error {
+	if named, ok := ecs.namedEntities[name]; ok {
+		if named.ID() == e.ID() {
+			return nil
+		}
+
+		return fmt.Errorf("the name %q is already used by entity %s", name, named.ID())
+	}
+
+	ecs.unname(e.ID())
+
+	ecs.namedEntities[name] = e
+	ecs.entityNames[e.ID()] = name
+
+	return nil
+}
+
+// Name returns the name of an entity, or an empty string if the entity has no name.
+func (ecs *ECS) Name(id entity.ID) string {
+	return ecs.entityNames[id]
+}
+
+// EntityByName returns the entity with the given name.
+// The boolean is false if there is no entity with this name.
+func (ecs *ECS) EntityByName(name string) (entity.Entity, bool) {
+	e, ok := ecs.namedEntities[name]
+	return e, ok
+}
+
+// unname removes the name of an entity, if any.
+func (ecs *ECS) unname(id entity.ID) {
+	name, ok := ecs.entityNames[id]
+	if !ok {
+		return
+	}
+
+	delete(ecs.namedEntities, name)
+	delete(ecs.entityNames, id)
+}
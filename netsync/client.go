@@ -0,0 +1,289 @@
+package netsync
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	ecs "github.com/jtbonhomme/ebiten-ecs"
+	"github.com/jtbonhomme/ebiten-ecs/component"
+	"github.com/jtbonhomme/ebiten-ecs/entity"
+)
+
+// interpolation holds the values a component is interpolated between.
+type interpolation struct {
+	data reflect.Value
+	from reflect.Value
+	to   reflect.Value
+}
+
+// Client applies the snapshots received from a server to a world.
+// Replicated entities are created locally with new IDs, tagged with Replicated.
+type Client struct {
+	world          *ecs.ECS
+	transport      Transport
+	entities       map[entity.ID]entity.Entity
+	interpolated   map[string]struct{}
+	interpolations map[entity.ID]map[string]*interpolation
+	seq            uint64
+	ticks          int
+	interval       int
+	encoding       Encoding
+	predictor      *Predictor
+	ack            uint64
+}
+
+// NewClient creates a client applying the snapshots received on the transport to the world.
+func NewClient(world *ecs.ECS, transport Transport) *Client {
+	return &Client{
+		world:          world,
+		transport:      transport,
+		entities:       make(map[entity.ID]entity.Entity),
+		interpolated:   make(map[string]struct{}),
+		interpolations: make(map[entity.ID]map[string]*interpolation),
+		interval:       1,
+	}
+}
+
+// Interpolate enables the interpolation of the given component types, identified by their registered names:
+// instead of jumping to the received values, their float fields move smoothly towards them over the
+// interval observed between two snapshots.
+func (c *Client) Interpolate(components ...string) {
+	for _, name := range components {
+		c.interpolated[name] = struct{}{}
+	}
+}
+
+// SetEncoding sets the encoding of the snapshots, JSON by default. It MUST be the encoding of the server.
+func (c *Client) SetEncoding(enc Encoding) {
+	c.encoding = enc
+}
+
+// Entity returns the local entity replicating the server entity with the given ID.
+func (c *Client) Entity(serverID entity.ID) (entity.Entity, bool) {
+	e, ok := c.entities[serverID]
+	return e, ok
+}
+
+// Update applies the received snapshots and advances the interpolations. It is meant to be called
+// once per tick, before the world Update.
+func (c *Client) Update() error {
+	c.ticks++
+
+	received := false
+
+	for {
+		data, ok, err := c.transport.Receive()
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			break
+		}
+
+		msg, err := decodeMessage(data, c.encoding)
+		if err != nil {
+			return err
+		}
+
+		err = c.apply(msg)
+		if err != nil {
+			return err
+		}
+
+		received = true
+	}
+
+	if received && c.predictor != nil {
+		c.predictor.reconcile(c.ack)
+	}
+
+	c.interpolate()
+
+	return nil
+}
+
+// apply applies a snapshot to the world.
+func (c *Client) apply(msg *message) error {
+	if msg.Seq <= c.seq {
+		return nil
+	}
+
+	c.seq = msg.Seq
+	if c.predictor != nil {
+		c.ack = msg.Acks[c.predictor.serverID]
+	}
+	c.interval, c.ticks = c.ticks, 0
+	if c.interval < 1 {
+		c.interval = 1
+	}
+
+	if msg.Full {
+		for id := range c.entities {
+			if _, ok := msg.Entities[id]; !ok {
+				c.remove(id)
+			}
+		}
+	}
+
+	for _, id := range msg.Removed {
+		c.remove(id)
+	}
+
+	ids := make([]entity.ID, 0, len(msg.Entities))
+	for id := range msg.Entities {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	for _, id := range ids {
+		err := c.applyEntity(id, msg.Entities[id])
+		if err != nil {
+			return fmt.Errorf("entity %s: %w", id, err)
+		}
+	}
+
+	return nil
+}
+
+// applyEntity creates or updates the local entity replicating a server entity.
+func (c *Client) applyEntity(id entity.ID, components map[string]json.RawMessage) error {
+	e, ok := c.entities[id]
+	if !ok {
+		e = entity.New()
+		c.world.RegisterEntity(e)
+		c.world.Tag(e, Replicated)
+		c.entities[id] = e
+	}
+
+	for name, raw := range components {
+		d, err := c.unmarshal(name, raw)
+		if err != nil {
+			return fmt.Errorf("component %q: %w", name, err)
+		}
+
+		predicted := c.predictor != nil && id == c.predictor.serverID
+		if predicted {
+			d = c.predictor.receive(name, d)
+		}
+
+		current := find(c.world.EntityComponents(e.ID()), d)
+		if current == nil {
+			c.world.RegisterEntity(e, component.New(d))
+			continue
+		}
+
+		if _, ok := c.interpolated[name]; !ok || predicted {
+			reflect.ValueOf(current).Elem().Set(reflect.ValueOf(d).Elem())
+			continue
+		}
+
+		c.startInterpolation(id, name, current, d)
+	}
+
+	return nil
+}
+
+// unmarshal decodes the data of a component in the encoding of the snapshots.
+func (c *Client) unmarshal(name string, raw []byte) (interface{}, error) {
+	if c.encoding == Protobuf {
+		return component.UnmarshalBinary(name, raw)
+	}
+
+	d, err := component.NewData(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return d, json.Unmarshal(raw, d)
+}
+
+// find returns the data of the component of the same type as d, or nil if there is none.
+func find(components []component.Component, d interface{}) interface{} {
+	id := component.TypeIDOf(d)
+
+	for _, rComponent := range components {
+		if rComponent.TypeID() == id {
+			return rComponent.Data()
+		}
+	}
+
+	return nil
+}
+
+// startInterpolation starts moving the current value of a component towards the received one.
+func (c *Client) startInterpolation(id entity.ID, name string, current, received interface{}) {
+	if c.interpolations[id] == nil {
+		c.interpolations[id] = make(map[string]*interpolation)
+	}
+
+	data := reflect.ValueOf(current).Elem()
+	from := reflect.New(data.Type()).Elem()
+	from.Set(data)
+
+	c.interpolations[id][name] = &interpolation{
+		data: data,
+		from: from,
+		to:   reflect.ValueOf(received).Elem(),
+	}
+}
+
+// interpolate moves the interpolated components towards their received values.
+func (c *Client) interpolate() {
+	alpha := float64(c.ticks) / float64(c.interval)
+	if alpha > 1 {
+		alpha = 1
+	}
+
+	for id, interpolations := range c.interpolations {
+		for name, i := range interpolations {
+			i.data.Set(i.to)
+			lerp(i.data, i.from, i.to, alpha)
+
+			// the component reached the received value, and is left alone until the next snapshot
+			if alpha >= 1 {
+				delete(interpolations, name)
+			}
+		}
+
+		if len(interpolations) == 0 {
+			delete(c.interpolations, id)
+		}
+	}
+}
+
+// lerp sets the float fields of dst, recursively, to the linear interpolation of from and to.
+func lerp(dst, from, to reflect.Value, alpha float64) {
+	switch dst.Kind() {
+	case reflect.Float32, reflect.Float64:
+		dst.SetFloat(from.Float() + (to.Float()-from.Float())*alpha)
+	case reflect.Struct:
+		for i := 0; i < dst.NumField(); i++ {
+			if dst.Field(i).CanSet() {
+				lerp(dst.Field(i), from.Field(i), to.Field(i), alpha)
+			}
+		}
+	case reflect.Array:
+		for i := 0; i < dst.Len(); i++ {
+			lerp(dst.Index(i), from.Index(i), to.Index(i), alpha)
+		}
+	}
+}
+
+// remove unregisters the local entity replicating a server entity.
+func (c *Client) remove(id entity.ID) {
+	e, ok := c.entities[id]
+	if !ok {
+		return
+	}
+
+	c.world.UnregisterEntity(e.ID())
+	delete(c.entities, id)
+	delete(c.interpolations, id)
+
+	if c.predictor != nil && c.predictor.serverID == id {
+		clear(c.predictor.authoritative)
+	}
+}
@@ -0,0 +1,131 @@
+package netsync
+
+import (
+	"math"
+
+	ecs "github.com/jtbonhomme/ebiten-ecs"
+	"github.com/jtbonhomme/ebiten-ecs/component"
+	"github.com/jtbonhomme/ebiten-ecs/entity"
+)
+
+// Interest is a function returning true if a replicated entity is relevant to the client of a server.
+// The entities which are not relevant are not sent; an entity which stops being relevant is removed
+// from the client, and is sent again in full when it becomes relevant again.
+type Interest func(id entity.ID, components []component.Component) bool
+
+// SetInterest sets the function filtering the entities replicated to the client, nil to replicate all of them.
+// Filtering requires a server per client, each with its own transport, sharing the same world.
+func (s *Server) SetInterest(f Interest) {
+	s.interest = f
+}
+
+// PositionFunc returns the position of an entity from its components, false if it has none.
+type PositionFunc func(components []component.Component) (x, y float64, ok bool)
+
+// cell is the coordinates of a cell of a grid.
+type cell struct {
+	x, y int
+}
+
+// Grid is a uniform grid indexing the positions of the replicated entities, rebuilt once per tick and shared
+// by the servers of all the clients, so that filtering the entities around each client does not scan the world.
+type Grid struct {
+	size       float64
+	position   PositionFunc
+	cells      map[cell][]entity.ID
+	positions  map[entity.ID][2]float64
+	generation uint64
+}
+
+// NewGrid creates a grid with square cells of the given size, indexing the positions returned by position.
+// The cells should be about the size of the radius of interest.
+// The function panics if the size is not positive.
+func NewGrid(size float64, position PositionFunc) *Grid {
+	if size <= 0 {
+		panic("the size of the cells of the grid MUST be positive")
+	}
+
+	return &Grid{
+		size:      size,
+		position:  position,
+		cells:     make(map[cell][]entity.ID),
+		positions: make(map[entity.ID][2]float64),
+	}
+}
+
+// Update indexes the current positions of the replicated entities of the world. It is meant to be called
+// once per tick, after the world Update and before the servers Sync.
+func (g *Grid) Update(world *ecs.ECS) {
+	for k, ids := range g.cells {
+		g.cells[k] = ids[:0]
+	}
+
+	clear(g.positions)
+
+	for _, e := range world.EntitiesWithTag(Replicated) {
+		x, y, ok := g.position(world.EntityComponents(e.ID()))
+		if !ok {
+			continue
+		}
+
+		k := g.cellAt(x, y)
+		g.cells[k] = append(g.cells[k], e.ID())
+		g.positions[e.ID()] = [2]float64{x, y}
+	}
+
+	g.generation++
+}
+
+// Near appends to dst the IDs of the entities within radius r of (x, y), and returns the extended slice.
+func (g *Grid) Near(dst []entity.ID, x, y, r float64) []entity.ID {
+	lo, hi := g.cellAt(x-r, y-r), g.cellAt(x+r, y+r)
+
+	for cx := lo.x; cx <= hi.x; cx++ {
+		for cy := lo.y; cy <= hi.y; cy++ {
+			for _, id := range g.cells[cell{cx, cy}] {
+				p := g.positions[id]
+				if (p[0]-x)*(p[0]-x)+(p[1]-y)*(p[1]-y) <= r*r {
+					dst = append(dst, id)
+				}
+			}
+		}
+	}
+
+	return dst
+}
+
+// Within returns an interest keeping the entities within radius r of the position returned by center,
+// typically the position of the entity of the player, read once per update of the grid.
+// The entities without position are kept, so that global entities (score, game state...) are replicated.
+func (g *Grid) Within(center func() (x, y float64), r float64) Interest {
+	relevant := make(map[entity.ID]struct{})
+	generation := uint64(0)
+	var ids []entity.ID
+
+	return func(id entity.ID, _ []component.Component) bool {
+		if generation != g.generation {
+			generation = g.generation
+			clear(relevant)
+
+			x, y := center()
+			ids = g.Near(ids[:0], x, y, r)
+
+			for _, near := range ids {
+				relevant[near] = struct{}{}
+			}
+		}
+
+		if _, ok := g.positions[id]; !ok {
+			return true
+		}
+
+		_, ok := relevant[id]
+
+		return ok
+	}
+}
+
+// cellAt returns the cell holding a position.
+func (g *Grid) cellAt(x, y float64) cell {
+	return cell{int(math.Floor(x / g.size)), int(math.Floor(y / g.size))}
+}
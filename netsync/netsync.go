@@ -0,0 +1,70 @@
+// Package netsync replicates components from a server-authoritative world to client worlds.
+// The server serializes the marked components of the entities tagged with Replicated into delta-compressed
+// snapshots, sent over a pluggable transport as JSON or protobuf (see Encoding), and the clients apply them
+// with optional interpolation. The entity controlled by the local player can be predicted, see Client.Predict.
+// Replicated component types MUST be registered with component.Register on both sides.
+package netsync
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/jtbonhomme/ebiten-ecs/entity"
+)
+
+var errPipeFull = errors.New("netsync: pipe is full")
+
+// Replicated is the tag of the entities replicated by the server.
+const Replicated = "netsync.replicated"
+
+// Transport is an interface that represents the way snapshots are exchanged between a server and its clients.
+// Messages MUST be delivered in order. A server transport broadcasts to all the clients.
+type Transport interface {
+	Send(data []byte) error
+	// Receive returns the next received message, or false if there is none for now.
+	Receive() ([]byte, bool, error)
+}
+
+// message is a snapshot sent by the server. Unless it is a full snapshot, it only holds the components
+// which changed since the previous message.
+type message struct {
+	Seq      uint64                                   `json:"seq"`
+	Full     bool                                     `json:"full,omitempty"`
+	Entities map[entity.ID]map[string]json.RawMessage `json:"entities,omitempty"`
+	Removed  []entity.ID                              `json:"removed,omitempty"`
+	// Acks are the sequence numbers of the last inputs simulated by the server, per controlled entity.
+	Acks map[entity.ID]uint64 `json:"acks,omitempty"`
+}
+
+type pipeEnd struct {
+	in  chan []byte
+	out chan []byte
+}
+
+// Pipe returns two connected in-memory transports, e.g. to run a server and a client in the same process.
+// Each direction buffers up to size messages; Send fails when the buffer is full.
+func Pipe(size int) (Transport, Transport) {
+	a, b := make(chan []byte, size), make(chan []byte, size)
+
+	return &pipeEnd{in: a, out: b}, &pipeEnd{in: b, out: a}
+}
+
+// Send sends a message to the other end of the pipe.
+func (p *pipeEnd) Send(data []byte) error {
+	select {
+	case p.out <- data:
+		return nil
+	default:
+		return errPipeFull
+	}
+}
+
+// Receive returns the next message sent by the other end of the pipe, if any.
+func (p *pipeEnd) Receive() ([]byte, bool, error) {
+	select {
+	case data := <-p.in:
+		return data, true, nil
+	default:
+		return nil, false, nil
+	}
+}
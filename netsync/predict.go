@@ -0,0 +1,180 @@
+package netsync
+
+import (
+	"encoding/json"
+	"reflect"
+
+	ecs "github.com/jtbonhomme/ebiten-ecs"
+	"github.com/jtbonhomme/ebiten-ecs/entity"
+	"github.com/jtbonhomme/ebiten-ecs/input"
+)
+
+// inputMessage is an input sent by a client to the server, identified by the server ID of the entity it controls.
+type inputMessage struct {
+	Entity entity.ID      `json:"entity"`
+	Seq    uint64         `json:"seq"`
+	Input  input.Snapshot `json:"input"`
+}
+
+// StepFunc simulates one fixed step of a locally controlled entity with an input, exactly as the server does,
+// e.g. by moving it according to the pressed keys.
+type StepFunc func(world *ecs.ECS, e entity.Entity, in *input.Snapshot)
+
+// pendingInput is an input sent to the server and not acknowledged yet.
+type pendingInput struct {
+	seq uint64
+	in  input.Snapshot
+}
+
+// Predictor predicts the state of an entity controlled by the local player: inputs are applied immediately
+// instead of waiting for the server, stored until the server acknowledges them, and re-simulated on top of
+// each authoritative snapshot, so that the entity is responsive and still converges to the server state.
+type Predictor struct {
+	client        *Client
+	serverID      entity.ID
+	step          StepFunc
+	seq           uint64
+	pending       []pendingInput
+	authoritative map[string]interface{}
+}
+
+// Predict enables the prediction of the entity with the given server ID, simulated with step.
+// Its components are not interpolated. A client predicts at most one entity: calling Predict again
+// replaces the predictor.
+func (c *Client) Predict(serverID entity.ID, step StepFunc) *Predictor {
+	c.predictor = &Predictor{
+		client:        c,
+		serverID:      serverID,
+		step:          step,
+		authoritative: make(map[string]interface{}),
+	}
+
+	return c.predictor
+}
+
+// Input sends an input to the server and applies it immediately to the predicted entity, if it was
+// already received. It is meant to be called once per fixed step, from an updater, with the current input.
+func (p *Predictor) Input(in *input.Snapshot) error {
+	p.seq++
+
+	pending := pendingInput{seq: p.seq}
+	pending.in.CopyFrom(in)
+	p.pending = append(p.pending, pending)
+
+	data, err := json.Marshal(inputMessage{Entity: p.serverID, Seq: p.seq, Input: pending.in})
+	if err != nil {
+		return err
+	}
+
+	err = p.client.transport.Send(data)
+	if err != nil {
+		return err
+	}
+
+	if e, ok := p.client.entities[p.serverID]; ok {
+		p.step(p.client.world, e, &pending.in)
+	}
+
+	return nil
+}
+
+// Pending returns the number of inputs sent to the server and not acknowledged yet.
+func (p *Predictor) Pending() int {
+	return len(p.pending)
+}
+
+// receive records the authoritative data of a component of the predicted entity, and returns a copy
+// to apply to the local entity.
+func (p *Predictor) receive(name string, data interface{}) interface{} {
+	p.authoritative[name] = data
+
+	clone := reflect.New(reflect.TypeOf(data).Elem())
+	clone.Elem().Set(reflect.ValueOf(data).Elem())
+
+	return clone.Interface()
+}
+
+// reconcile drops the inputs acknowledged by the server, restores the authoritative state of the predicted entity,
+// and simulates again the pending inputs on top of it.
+func (p *Predictor) reconcile(ack uint64) {
+	i := 0
+	for i < len(p.pending) && p.pending[i].seq <= ack {
+		i++
+	}
+
+	p.pending = append(p.pending[:0], p.pending[i:]...)
+
+	e, ok := p.client.entities[p.serverID]
+	if !ok {
+		return
+	}
+
+	components := p.client.world.EntityComponents(e.ID())
+
+	for _, data := range p.authoritative {
+		current := find(components, data)
+		if current != nil {
+			reflect.ValueOf(current).Elem().Set(reflect.ValueOf(data).Elem())
+		}
+	}
+
+	for i := range p.pending {
+		p.step(p.client.world, e, &p.pending[i].in)
+	}
+}
+
+// NextInput reads the next input sent by the client controlling the entity with the given ID into in,
+// and returns false if there is none for now. The inputs are identified by the predicted entity of the clients
+// (see Client.Predict), so that the inputs of several clients sharing a transport are queued and acknowledged
+// separately. It is meant to be called once per fixed step and per controlled entity, before the world Update,
+// so that the server simulates the inputs at the same rate as the clients. The snapshots sent afterwards
+// acknowledge the input.
+func (s *Server) NextInput(id entity.ID, in *input.Snapshot) (bool, error) {
+	err := s.receiveInputs()
+	if err != nil {
+		return false, err
+	}
+
+	queue := s.inputs[id]
+	if len(queue) == 0 {
+		return false, nil
+	}
+
+	msg := queue[0]
+	s.inputs[id] = queue[1:]
+	s.acks[id] = msg.Seq
+	s.acked = true
+	in.CopyFrom(&msg.Input)
+
+	return true, nil
+}
+
+// receiveInputs queues the inputs received from the clients, dropping the ones already received.
+func (s *Server) receiveInputs() error {
+	for {
+		data, ok, err := s.transport.Receive()
+		if err != nil || !ok {
+			return err
+		}
+
+		msg := inputMessage{}
+
+		err = json.Unmarshal(data, &msg)
+		if err != nil {
+			return err
+		}
+
+		queue := s.inputs[msg.Entity]
+
+		last := s.acks[msg.Entity]
+		if len(queue) > 0 {
+			last = queue[len(queue)-1].Seq
+		}
+
+		if msg.Seq <= last {
+			continue
+		}
+
+		s.inputs[msg.Entity] = append(queue, msg)
+	}
+}
@@ -0,0 +1,194 @@
+package netsync_test
+
+import (
+	"testing"
+
+	ecs "github.com/jtbonhomme/ebiten-ecs"
+	"github.com/jtbonhomme/ebiten-ecs/component"
+	"github.com/jtbonhomme/ebiten-ecs/entity"
+	"github.com/jtbonhomme/ebiten-ecs/input"
+	"github.com/jtbonhomme/ebiten-ecs/netsync"
+)
+
+type position struct {
+	X float64
+}
+
+func init() {
+	component.Register("netsync_test.position", &position{})
+}
+
+// hub is a server transport broadcasting to several clients, and receiving from all of them.
+type hub struct {
+	in      [][]byte
+	clients []*hubClient
+}
+
+func (h *hub) Send(data []byte) error {
+	for _, c := range h.clients {
+		c.in = append(c.in, data)
+	}
+
+	return nil
+}
+
+func (h *hub) Receive() ([]byte, bool, error) {
+	if len(h.in) == 0 {
+		return nil, false, nil
+	}
+
+	data := h.in[0]
+	h.in = h.in[1:]
+
+	return data, true, nil
+}
+
+// hubClient is the transport of a client of a hub.
+type hubClient struct {
+	hub *hub
+	in  [][]byte
+}
+
+func (c *hubClient) Send(data []byte) error {
+	c.hub.in = append(c.hub.in, data)
+	return nil
+}
+
+func (c *hubClient) Receive() ([]byte, bool, error) {
+	if len(c.in) == 0 {
+		return nil, false, nil
+	}
+
+	data := c.in[0]
+	c.in = c.in[1:]
+
+	return data, true, nil
+}
+
+func (h *hub) connect() *hubClient {
+	c := &hubClient{hub: h}
+	h.clients = append(h.clients, c)
+
+	return c
+}
+
+// move moves an entity by 1 if key 1 is pressed.
+func move(world *ecs.ECS, e entity.Entity, in *input.Snapshot) {
+	if !in.IsKeyPressed(1) {
+		return
+	}
+
+	for _, c := range world.EntityComponents(e.ID()) {
+		if p, ok := c.Data().(*position); ok {
+			p.X++
+		}
+	}
+}
+
+func positionOf(t *testing.T, world *ecs.ECS, id entity.ID) float64 {
+	t.Helper()
+
+	for _, c := range world.EntityComponents(id) {
+		if p, ok := c.Data().(*position); ok {
+			return p.X
+		}
+	}
+
+	t.Fatalf("entity %s has no position", id)
+
+	return 0
+}
+
+func TestPredictionWithSeveralClients(t *testing.T) {
+	h := &hub{}
+	serverWorld := ecs.New()
+	server := netsync.NewServer(serverWorld, h, "netsync_test.position")
+
+	players := make([]entity.Entity, 2)
+	for i := range players {
+		players[i] = serverWorld.Spawn(&position{})
+		serverWorld.Tag(players[i], netsync.Replicated)
+	}
+
+	clients := make([]*netsync.Client, 2)
+	predictors := make([]*netsync.Predictor, 2)
+	worlds := make([]*ecs.ECS, 2)
+
+	for i := range clients {
+		worlds[i] = ecs.New()
+		clients[i] = netsync.NewClient(worlds[i], h.connect())
+		predictors[i] = clients[i].Predict(players[i].ID(), move)
+	}
+
+	if err := server.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	pressed := &input.Snapshot{Keys: []input.Key{1}}
+
+	for tick := 0; tick < 10; tick++ {
+		for i, c := range clients {
+			if err := c.Update(); err != nil {
+				t.Fatal(err)
+			}
+
+			// the second client only moves every other tick
+			if i == 0 || tick%2 == 0 {
+				if err := predictors[i].Input(pressed); err != nil {
+					t.Fatal(err)
+				}
+			} else if err := predictors[i].Input(&input.Snapshot{}); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		// the server lags behind: it simulates the inputs of every player every other tick
+		if tick%2 == 1 {
+			for _, p := range players {
+				in := &input.Snapshot{}
+				for n := 0; n < 2; n++ {
+					ok, err := server.NextInput(p.ID(), in)
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					if ok {
+						move(serverWorld, p, in)
+					}
+				}
+			}
+
+			if err := server.Sync(); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	for i, c := range clients {
+		if err := c.Update(); err != nil {
+			t.Fatal(err)
+		}
+
+		if n := predictors[i].Pending(); n != 0 {
+			t.Errorf("client %d: %d inputs are not acknowledged", i, n)
+		}
+
+		local, ok := c.Entity(players[i].ID())
+		if !ok {
+			t.Fatalf("client %d: the predicted entity was not replicated", i)
+		}
+
+		want := positionOf(t, serverWorld, players[i].ID())
+		if got := positionOf(t, worlds[i], local.ID()); got != want {
+			t.Errorf("client %d: predicted X = %v, server X = %v", i, got, want)
+		}
+	}
+
+	if x := positionOf(t, serverWorld, players[0].ID()); x != 10 {
+		t.Errorf("first player X = %v, want 10", x)
+	}
+
+	if x := positionOf(t, serverWorld, players[1].ID()); x != 5 {
+		t.Errorf("second player X = %v, want 5", x)
+	}
+}
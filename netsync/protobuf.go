@@ -0,0 +1,276 @@
+package netsync
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/jtbonhomme/ebiten-ecs/entity"
+)
+
+// Encoding is the format of the snapshots exchanged between a server and its clients.
+type Encoding int
+
+const (
+	// JSON encodes the snapshots and their components as JSON. It is the default encoding.
+	JSON Encoding = iota
+	// Protobuf encodes the snapshots in the protobuf wire format described by snapshot.proto, and their
+	// components with the binary codecs of their types (see component.RegisterBinary), e.g. protobuf messages,
+	// for compact snapshots which can be decoded by tools written in other languages.
+	Protobuf
+)
+
+var errTruncated = errors.New("netsync: truncated protobuf message")
+
+// protobuf wire types
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+// encodeMessage serializes a snapshot, whose components are already encoded, in the given encoding.
+func encodeMessage(msg *message, enc Encoding) ([]byte, error) {
+	if enc == JSON {
+		return json.Marshal(msg)
+	}
+
+	var b []byte
+
+	b = appendVarintField(b, 1, msg.Seq)
+	if msg.Full {
+		b = appendVarintField(b, 2, 1)
+	}
+
+	ids := make([]entity.ID, 0, len(msg.Entities))
+	for id := range msg.Entities {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	for _, id := range ids {
+		components := msg.Entities[id]
+
+		names := make([]string, 0, len(components))
+		for name := range components {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		var e []byte
+		e = appendVarintField(e, 1, uint64(id))
+
+		for _, name := range names {
+			var c []byte
+			c = appendBytesField(c, 1, []byte(name))
+			c = appendBytesField(c, 2, components[name])
+			e = appendBytesField(e, 2, c)
+		}
+
+		b = appendBytesField(b, 3, e)
+	}
+
+	if len(msg.Removed) > 0 {
+		var packed []byte
+		for _, id := range msg.Removed {
+			packed = binary.AppendUvarint(packed, uint64(id))
+		}
+
+		b = appendBytesField(b, 4, packed)
+	}
+
+	acked := make([]entity.ID, 0, len(msg.Acks))
+	for id := range msg.Acks {
+		acked = append(acked, id)
+	}
+	sort.Slice(acked, func(i, j int) bool { return acked[i] < acked[j] })
+
+	for _, id := range acked {
+		var a []byte
+		a = appendVarintField(a, 1, uint64(id))
+		a = appendVarintField(a, 2, msg.Acks[id])
+		b = appendBytesField(b, 5, a)
+	}
+
+	return b, nil
+}
+
+// decodeMessage deserializes a snapshot in the given encoding. Its components are left encoded.
+func decodeMessage(data []byte, enc Encoding) (*message, error) {
+	msg := &message{}
+
+	if enc == JSON {
+		err := json.Unmarshal(data, msg)
+		return msg, err
+	}
+
+	msg.Entities = make(map[entity.ID]map[string]json.RawMessage)
+
+	err := readFields(data, func(field int, v uint64, b []byte) error {
+		switch field {
+		case 1:
+			msg.Seq = v
+		case 2:
+			msg.Full = v != 0
+		case 3:
+			return decodeEntity(b, msg)
+		case 4:
+			if b == nil {
+				// unpacked
+				msg.Removed = append(msg.Removed, entity.ID(v))
+				return nil
+			}
+
+			for len(b) > 0 {
+				id, n := binary.Uvarint(b)
+				if n <= 0 {
+					return errTruncated
+				}
+
+				msg.Removed = append(msg.Removed, entity.ID(id))
+				b = b[n:]
+			}
+		case 5:
+			var id entity.ID
+			var seq uint64
+
+			err := readFields(b, func(field int, v uint64, _ []byte) error {
+				switch field {
+				case 1:
+					id = entity.ID(v)
+				case 2:
+					seq = v
+				}
+
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+
+			if msg.Acks == nil {
+				msg.Acks = make(map[entity.ID]uint64)
+			}
+
+			msg.Acks[id] = seq
+		}
+
+		return nil
+	})
+
+	return msg, err
+}
+
+// decodeEntity deserializes an entity of a snapshot.
+func decodeEntity(data []byte, msg *message) error {
+	var id entity.ID
+	components := make(map[string]json.RawMessage)
+
+	err := readFields(data, func(field int, v uint64, b []byte) error {
+		switch field {
+		case 1:
+			id = entity.ID(v)
+		case 2:
+			var name string
+			var raw []byte
+
+			err := readFields(b, func(field int, _ uint64, b []byte) error {
+				switch field {
+				case 1:
+					name = string(b)
+				case 2:
+					raw = b
+				}
+
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+
+			components[name] = raw
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	msg.Entities[id] = components
+
+	return nil
+}
+
+// readFields calls f with each field of a protobuf message: v holds the value of varint fields,
+// b the value of length-delimited fields. Fixed-size fields are skipped.
+func readFields(data []byte, f func(field int, v uint64, b []byte) error) error {
+	for len(data) > 0 {
+		key, n := binary.Uvarint(data)
+		if n <= 0 {
+			return errTruncated
+		}
+
+		data = data[n:]
+		field, wire := int(key>>3), key&7
+
+		switch wire {
+		case wireVarint:
+			v, n := binary.Uvarint(data)
+			if n <= 0 {
+				return errTruncated
+			}
+
+			data = data[n:]
+
+			if err := f(field, v, nil); err != nil {
+				return err
+			}
+		case wireBytes:
+			l, n := binary.Uvarint(data)
+			if n <= 0 || uint64(len(data)-n) < l {
+				return errTruncated
+			}
+
+			b := data[n : n+int(l)]
+			data = data[n+int(l):]
+
+			if err := f(field, 0, b); err != nil {
+				return err
+			}
+		case wireFixed64:
+			if len(data) < 8 {
+				return errTruncated
+			}
+
+			data = data[8:]
+		case wireFixed32:
+			if len(data) < 4 {
+				return errTruncated
+			}
+
+			data = data[4:]
+		default:
+			return fmt.Errorf("netsync: unsupported protobuf wire type %d", wire)
+		}
+	}
+
+	return nil
+}
+
+// appendVarintField appends a varint field to a protobuf message.
+func appendVarintField(b []byte, field int, v uint64) []byte {
+	b = binary.AppendUvarint(b, uint64(field)<<3|wireVarint)
+	return binary.AppendUvarint(b, v)
+}
+
+// appendBytesField appends a length-delimited field to a protobuf message.
+func appendBytesField(b []byte, field int, v []byte) []byte {
+	b = binary.AppendUvarint(b, uint64(field)<<3|wireBytes)
+	b = binary.AppendUvarint(b, uint64(len(v)))
+
+	return append(b, v...)
+}
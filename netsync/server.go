@@ -0,0 +1,165 @@
+package netsync
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	ecs "github.com/jtbonhomme/ebiten-ecs"
+	"github.com/jtbonhomme/ebiten-ecs/component"
+	"github.com/jtbonhomme/ebiten-ecs/entity"
+)
+
+// DefaultKeyframeInterval is the default number of snapshots between two full snapshots.
+const DefaultKeyframeInterval = 60
+
+// Server sends snapshots of the replicated entities of a world.
+type Server struct {
+	world     *ecs.ECS
+	transport Transport
+	marked    map[string]struct{}
+	sent      map[entity.ID]map[string][]byte
+	seq       uint64
+	keyframe  uint64
+	encoding  Encoding
+	inputs    map[entity.ID][]inputMessage
+	acks      map[entity.ID]uint64
+	acked     bool
+	interest  Interest
+}
+
+// NewServer creates a server replicating the given component types, identified by their registered names.
+func NewServer(world *ecs.ECS, transport Transport, components ...string) *Server {
+	s := &Server{
+		world:     world,
+		transport: transport,
+		marked:    make(map[string]struct{}, len(components)),
+		sent:      make(map[entity.ID]map[string][]byte),
+		keyframe:  DefaultKeyframeInterval,
+		inputs:    make(map[entity.ID][]inputMessage),
+		acks:      make(map[entity.ID]uint64),
+	}
+
+	for _, name := range components {
+		s.marked[name] = struct{}{}
+	}
+
+	return s
+}
+
+// SetKeyframeInterval sets the number of snapshots between two full snapshots,
+// which let clients recover from missed messages or join late.
+func (s *Server) SetKeyframeInterval(n uint64) {
+	s.keyframe = n
+}
+
+// SetEncoding sets the encoding of the snapshots, JSON by default. The clients MUST use the same encoding.
+func (s *Server) SetEncoding(enc Encoding) {
+	s.encoding = enc
+}
+
+// Sync builds a snapshot of the replicated entities and sends it. It is meant to be called once per tick
+// or at the network rate, after the world Update. Only the components which changed since the previous
+// snapshot are sent, except every keyframe interval where a full snapshot is sent.
+func (s *Server) Sync() error {
+	s.seq++
+
+	msg := message{
+		Seq:      s.seq,
+		Full:     s.seq == 1 || s.keyframe > 0 && (s.seq-1)%s.keyframe == 0,
+		Entities: make(map[entity.ID]map[string]json.RawMessage),
+	}
+
+	if len(s.acks) > 0 {
+		msg.Acks = make(map[entity.ID]uint64, len(s.acks))
+		for id, seq := range s.acks {
+			msg.Acks[id] = seq
+		}
+	}
+
+	alive := make(map[entity.ID]struct{})
+
+	for _, e := range s.world.EntitiesWithTag(Replicated) {
+		if s.interest != nil && !s.interest(e.ID(), s.world.EntityComponents(e.ID())) {
+			continue
+		}
+
+		alive[e.ID()] = struct{}{}
+		sent := s.sent[e.ID()]
+
+		for _, c := range s.world.EntityComponents(e.ID()) {
+			name, ok := component.TypeName(c.Data())
+			if !ok {
+				continue
+			}
+
+			if _, ok := s.marked[name]; !ok {
+				continue
+			}
+
+			data, err := s.marshal(c.Data())
+			if err != nil {
+				return fmt.Errorf("entity %s: component %q: %w", e.ID(), name, err)
+			}
+
+			if !msg.Full && bytes.Equal(sent[name], data) {
+				continue
+			}
+
+			if msg.Entities[e.ID()] == nil {
+				msg.Entities[e.ID()] = make(map[string]json.RawMessage)
+			}
+			msg.Entities[e.ID()][name] = data
+		}
+	}
+
+	for id := range s.sent {
+		if _, ok := alive[id]; !ok {
+			msg.Removed = append(msg.Removed, id)
+		}
+	}
+
+	if !msg.Full && len(msg.Entities) == 0 && len(msg.Removed) == 0 && !s.acked {
+		return nil
+	}
+
+	data, err := encodeMessage(&msg, s.encoding)
+	if err != nil {
+		return err
+	}
+
+	err = s.transport.Send(data)
+	if err != nil {
+		return err
+	}
+
+	// what was sent is only recorded once the snapshot is sent, so that the next one is computed
+	// against what the clients received
+	for id, components := range msg.Entities {
+		if s.sent[id] == nil {
+			s.sent[id] = make(map[string][]byte, len(components))
+		}
+
+		for name, data := range components {
+			s.sent[id][name] = data
+		}
+	}
+
+	for _, id := range msg.Removed {
+		delete(s.sent, id)
+	}
+
+	s.acked = false
+
+	return nil
+}
+
+// marshal encodes the data of a component in the encoding of the snapshots.
+func (s *Server) marshal(data interface{}) ([]byte, error) {
+	if s.encoding == Protobuf {
+		_, b, err := component.MarshalBinary(data)
+		return b, err
+	}
+
+	return json.Marshal(data)
+}
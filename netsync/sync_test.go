@@ -0,0 +1,124 @@
+package netsync_test
+
+import (
+	"errors"
+	"testing"
+
+	ecs "github.com/jtbonhomme/ebiten-ecs"
+	"github.com/jtbonhomme/ebiten-ecs/netsync"
+)
+
+// flaky is a transport failing to send while fail is true.
+type flaky struct {
+	netsync.Transport
+	fail bool
+}
+
+func (f *flaky) Send(data []byte) error {
+	if f.fail {
+		return errors.New("network is down")
+	}
+
+	return f.Transport.Send(data)
+}
+
+func TestSyncResendsAfterFailedSend(t *testing.T) {
+	a, b := netsync.Pipe(16)
+	transport := &flaky{Transport: a}
+
+	serverWorld := ecs.New()
+	server := netsync.NewServer(serverWorld, transport, "netsync_test.position")
+	server.SetKeyframeInterval(0)
+
+	p := &position{}
+	e := serverWorld.Spawn(p)
+	serverWorld.Tag(e, netsync.Replicated)
+
+	clientWorld := ecs.New()
+	client := netsync.NewClient(clientWorld, b)
+
+	if err := server.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	p.X = 1
+	transport.fail = true
+
+	if err := server.Sync(); err == nil {
+		t.Fatal("Sync did not return the error of the transport")
+	}
+
+	transport.fail = false
+
+	if err := server.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.Update(); err != nil {
+		t.Fatal(err)
+	}
+
+	local, ok := client.Entity(e.ID())
+	if !ok {
+		t.Fatal("the entity was not replicated")
+	}
+
+	if x := positionOf(t, clientWorld, local.ID()); x != 1 {
+		t.Errorf("X = %v, want 1", x)
+	}
+}
+
+func TestInterpolationEnds(t *testing.T) {
+	a, b := netsync.Pipe(16)
+
+	serverWorld := ecs.New()
+	server := netsync.NewServer(serverWorld, a, "netsync_test.position")
+
+	p := &position{}
+	e := serverWorld.Spawn(p)
+	serverWorld.Tag(e, netsync.Replicated)
+
+	clientWorld := ecs.New()
+	client := netsync.NewClient(clientWorld, b)
+	client.Interpolate("netsync_test.position")
+
+	for _, x := range []float64{0, 10} {
+		p.X = x
+
+		if err := server.Sync(); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := client.Update(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	local, _ := client.Entity(e.ID())
+
+	// no snapshot for a while: the interpolation reaches its end
+	for i := 0; i < 3; i++ {
+		if err := client.Update(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if x := positionOf(t, clientWorld, local.ID()); x != 10 {
+		t.Fatalf("X = %v, want 10", x)
+	}
+
+	// a finished interpolation no longer overwrites the component
+	for _, c := range clientWorld.EntityComponents(local.ID()) {
+		if lp, ok := c.Data().(*position); ok {
+			lp.X = 42
+		}
+	}
+
+	if err := client.Update(); err != nil {
+		t.Fatal(err)
+	}
+
+	if x := positionOf(t, clientWorld, local.ID()); x != 42 {
+		t.Errorf("X = %v, want 42", x)
+	}
+}
@@ -0,0 +1,147 @@
+// Package offline provides an offline rendering mode, stepping an ECS world once per rendered frame
+// regardless of real time and exporting every frame, to render trailers or replays at full quality.
+package offline
+
+import (
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"github.com/jtbonhomme/ebiten-ecs/ebitenecs"
+)
+
+// Output is an interface that represents the destination of the rendered frames.
+type Output interface {
+	WriteFrame(n int, img image.Image) error
+}
+
+type directory struct {
+	pattern string
+}
+
+// Directory creates an output writing every frame as a numbered PNG file.
+// The pattern is a path containing a single integer verb, e.g. "frames/frame-%06d.png".
+func Directory(pattern string) Output {
+	return &directory{
+		pattern: pattern,
+	}
+}
+
+// WriteFrame writes the frame as a PNG file named after the frame number.
+func (d *directory) WriteFrame(n int, img image.Image) error {
+	name := fmt.Sprintf(d.pattern, n)
+
+	err := os.MkdirAll(filepath.Dir(name), 0o755)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+
+	err = png.Encode(f, img)
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+type pipe struct {
+	w io.Writer
+}
+
+// Pipe creates an output writing every frame as a PNG image to w, one after the other.
+// It is meant to feed ffmpeg through its standard input with "-f image2pipe -i -".
+func Pipe(w io.Writer) Output {
+	return &pipe{
+		w: w,
+	}
+}
+
+// WriteFrame writes the frame as a PNG image to the pipe.
+func (p *pipe) WriteFrame(_ int, img image.Image) error {
+	return png.Encode(p.w, img)
+}
+
+// Renderer is an ebiten.Game stepping the world once per frame and writing every frame to an output.
+// Each call to Update is exactly one fixed step of the world followed by one rendered frame,
+// so the result does not depend on the actual TPS or on how long exporting a frame takes.
+// The game terminates once all the frames have been rendered.
+type Renderer struct {
+	world  *ebitenecs.World
+	width  int
+	height int
+	frames int
+	output Output
+	frame  int
+	canvas *ebiten.Image
+	pixels *image.RGBA
+}
+
+// NewRenderer creates a renderer for the given world, rendering the given number of frames of width x height pixels.
+func NewRenderer(world *ebitenecs.World, width, height, frames int, output Output) *Renderer {
+	return &Renderer{
+		world:  world,
+		width:  width,
+		height: height,
+		frames: frames,
+		output: output,
+		pixels: image.NewRGBA(image.Rect(0, 0, width, height)),
+	}
+}
+
+// Update steps the world, renders it offscreen and writes the frame to the output.
+// It returns ebiten.Termination once all the frames have been written.
+func (r *Renderer) Update() error {
+	if r.frame >= r.frames {
+		return ebiten.Termination
+	}
+
+	err := r.world.Update()
+	if err != nil {
+		return err
+	}
+
+	if r.canvas == nil {
+		r.canvas = ebiten.NewImage(r.width, r.height)
+	}
+
+	r.canvas.Clear()
+	r.world.Draw(r.canvas)
+	r.canvas.ReadPixels(r.pixels.Pix)
+
+	err = r.output.WriteFrame(r.frame, r.pixels)
+	if err != nil {
+		return fmt.Errorf("write frame %d: %w", r.frame, err)
+	}
+
+	r.frame++
+
+	return nil
+}
+
+// Draw displays the last rendered frame as a preview.
+func (r *Renderer) Draw(screen *ebiten.Image) {
+	if r.canvas != nil {
+		screen.DrawImage(r.canvas, nil)
+	}
+}
+
+// Layout returns the size of the rendered frames.
+func (r *Renderer) Layout(outsideWidth, outsideHeight int) (int, int) {
+	return r.width, r.height
+}
+
+// Frame returns the number of frames rendered so far.
+func (r *Renderer) Frame() int {
+	return r.frame
+}
@@ -0,0 +1,110 @@
+package ecs
+
+import (
+	"fmt"
+	"time"
+)
+
+// MaxFixedSteps is the maximum number of fixed steps run by a single call to Update,
+// so that a slow frame does not make the next ones even slower.
+const MaxFixedSteps int = 5
+
+// config holds the settings of an ECS instance.
+type config struct {
+	maxEntities int
+	maxSystems  int
+	maxDrawers  int
+	entityLimit int
+	systemLimit int
+	fixedStep   time.Duration
+	threadSafe  bool
+
+	deterministic bool
+	seed          int64
+	rollback      int
+
+	crashDir     string
+	crashRepanic bool
+}
+
+// defaultConfig returns the settings used when New is called without options.
+func defaultConfig() config {
+	return config{
+		maxEntities: MaxEntities,
+		maxSystems:  MaxSystems,
+		maxDrawers:  MaxDrawers,
+		seed:        time.Now().UnixNano(),
+	}
+}
+
+// Option is a function configuring the ECS instance created by New.
+type Option func(*config)
+
+// WithMaxEntities sets the number of entities the registries are sized for. It defaults to MaxEntities.
+// It is only a hint: the registries grow as needed. See WithEntityLimit for a hard limit.
+func WithMaxEntities(n int) Option {
+	return func(c *config) {
+		c.maxEntities = n
+	}
+}
+
+// WithMaxSystems sets the number of systems the registries are sized for. It defaults to MaxSystems.
+// It is only a hint: the registries grow as needed. See WithSystemLimit for a hard limit.
+func WithMaxSystems(n int) Option {
+	return func(c *config) {
+		c.maxSystems = n
+	}
+}
+
+// WithMaxDrawers sets the number of z-indexes the drawers registry is sized for. It defaults to MaxDrawers.
+func WithMaxDrawers(n int) Option {
+	return func(c *config) {
+		c.maxDrawers = n
+	}
+}
+
+// MaxDrawers returns the number of z-indexes the drawers registry of a rendering package is sized for.
+func (ecs *ECS) MaxDrawers() int {
+	return ecs.config.maxDrawers
+}
+
+// WithFixedTimestep runs the updaters at a fixed rate of tps steps per second, whatever the rate at which
+// Update is called: each call to Update runs as many steps as needed to catch up with real time,
+// up to MaxFixedSteps. Without this option, each call to Update runs exactly one step.
+// The function panics if tps is not positive.
+func WithFixedTimestep(tps int) Option {
+	if tps <= 0 {
+		panic(fmt.Sprintf("the number of steps per second of a fixed timestep MUST be positive, got %d", tps))
+	}
+
+	return func(c *config) {
+		c.fixedStep = time.Second / time.Duration(tps)
+	}
+}
+
+// WithThreadSafe protects the world with a mutex, so that other goroutines can access it with Do
+// while the game loop runs Update and Draw.
+func WithThreadSafe() Option {
+	return func(c *config) {
+		c.threadSafe = true
+	}
+}
+
+// WithDeterministic makes the simulation deterministic: the random number generator of the world is seeded
+// with the given seed, and each call to Update runs exactly one step, even with a fixed timestep,
+// so that the same input produces bit-identical results. See Record and Replay.
+func WithDeterministic(seed int64) Option {
+	return func(c *config) {
+		c.deterministic = true
+		c.seed = seed
+	}
+}
+
+// WithRollback keeps the state and the input of the given number of past ticks, so that the world can
+// roll back to one of them and simulate the following ticks again (see Rollback), e.g. for GGPO-style
+// peer-to-peer games. It is meant to be used along with WithDeterministic.
+func WithRollback(ticks int) Option {
+	return func(c *config) {
+		c.rollback = ticks
+	}
+}
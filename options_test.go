@@ -0,0 +1,21 @@
+package ecs_test
+
+import (
+	"testing"
+
+	ecs "github.com/jtbonhomme/ebiten-ecs"
+)
+
+func TestWithFixedTimestepRejectsNonPositiveRates(t *testing.T) {
+	for _, tps := range []int{0, -60} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("WithFixedTimestep(%d) did not panic", tps)
+				}
+			}()
+
+			ecs.WithFixedTimestep(tps)
+		}()
+	}
+}
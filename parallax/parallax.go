@@ -0,0 +1,106 @@
+// Package parallax provides parallax background layers, scrolling slower or faster than the world
+// as the camera moves, and wrapping around to fill the screen.
+package parallax
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"github.com/jtbonhomme/ebiten-ecs/component"
+	"github.com/jtbonhomme/ebiten-ecs/ebitenecs"
+	"github.com/jtbonhomme/ebiten-ecs/system"
+)
+
+// Repeat tells along which axes a layer is repeated to fill the screen.
+type Repeat int
+
+// Repeat modes of the layers.
+const (
+	NoRepeat Repeat = iota
+	RepeatX
+	RepeatY
+	RepeatBoth
+)
+
+// Layer is the component of the entities drawn as a parallax layer.
+type Layer struct {
+	Image *ebiten.Image
+	// FactorX and FactorY are the fraction of the camera moves the layer follows: 0 for a layer fixed on screen
+	// (sky), less than 1 for a far layer (mountains), 1 for a layer moving with the world, more for a near layer.
+	FactorX float64
+	FactorY float64
+	// OffsetX and OffsetY are the position of the top left corner of the layer when the camera is at the origin,
+	// relative to the center of the screen.
+	OffsetX float64
+	OffsetY float64
+	Repeat  Repeat
+}
+
+// layerType is the handle used to find the Layer component.
+var layerType = component.NewType[Layer]()
+
+// Drawer is the drawer of the parallax layers, registered in the world pass below the other world drawers,
+// with one entity per layer, from the farthest to the nearest. The layers follow the position of the camera,
+// but are neither zoomed nor rotated with it.
+type Drawer struct {
+	id     system.ID
+	camera *ebitenecs.Camera
+	op     ebiten.DrawImageOptions
+}
+
+// NewDrawer creates the drawer of the parallax layers scrolling with the camera.
+func NewDrawer(camera *ebitenecs.Camera) *Drawer {
+	return &Drawer{
+		id:     system.AssignID(),
+		camera: camera,
+	}
+}
+
+// ID returns the unique ID of the drawer.
+func (d *Drawer) ID() system.ID {
+	return d.id
+}
+
+// DrawWorld draws the layer of the entity, repeated as needed to fill the screen.
+func (d *Drawer) DrawWorld(screen *ebiten.Image, _ ebiten.GeoM, c []component.Component) {
+	l := layerType.Get(c)
+	if l == nil || l.Image == nil {
+		return
+	}
+
+	size := screen.Bounds().Size()
+	sw, sh := float64(size.X), float64(size.Y)
+	iw, ih := float64(l.Image.Bounds().Dx()), float64(l.Image.Bounds().Dy())
+
+	x := l.OffsetX - d.camera.X*l.FactorX + sw/2
+	y := l.OffsetY - d.camera.Y*l.FactorY + sh/2
+
+	x0, x1 := x, x
+	if l.Repeat == RepeatX || l.Repeat == RepeatBoth {
+		x0, x1 = wrap(x, iw), sw
+	}
+
+	y0, y1 := y, y
+	if l.Repeat == RepeatY || l.Repeat == RepeatBoth {
+		y0, y1 = wrap(y, ih), sh
+	}
+
+	for ty := y0; ty <= y1; ty += ih {
+		for tx := x0; tx <= x1; tx += iw {
+			d.op.GeoM.Reset()
+			d.op.GeoM.Translate(tx, ty)
+			screen.DrawImage(l.Image, &d.op)
+		}
+	}
+}
+
+// wrap returns the position of the first copy of a repeated image of the given size covering the screen origin.
+func wrap(v, size float64) float64 {
+	v = math.Mod(v, size)
+	if v > 0 {
+		v -= size
+	}
+
+	return v
+}
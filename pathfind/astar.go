@@ -0,0 +1,219 @@
+package pathfind
+
+import (
+	"container/heap"
+	"math"
+)
+
+// node is a cell in the open set of A*.
+type node struct {
+	p     Point
+	g     float64
+	f     float64
+	index int
+}
+
+// openSet is the priority queue of the cells to explore, by increasing estimated cost.
+type openSet []*node
+
+func (o openSet) Len() int           { return len(o) }
+func (o openSet) Less(i, j int) bool { return o[i].f < o[j].f }
+
+func (o openSet) Swap(i, j int) {
+	o[i], o[j] = o[j], o[i]
+	o[i].index, o[j].index = i, j
+}
+
+func (o *openSet) Push(x interface{}) {
+	n := x.(*node)
+	n.index = len(*o)
+	*o = append(*o, n)
+}
+
+func (o *openSet) Pop() interface{} {
+	old := *o
+	n := old[len(old)-1]
+	*o = old[:len(old)-1]
+
+	return n
+}
+
+// neighbours are the moves from a cell to its neighbours, the orthogonal ones first.
+var neighbours = []Point{
+	{1, 0}, {-1, 0}, {0, 1}, {0, -1},
+	{1, 1}, {1, -1}, {-1, 1}, {-1, -1},
+}
+
+// heuristic estimates the cost of the path between two cells.
+func (g *Grid) heuristic(a, b Point) float64 {
+	dx, dy := math.Abs(float64(a.X-b.X)), math.Abs(float64(a.Y-b.Y))
+	if !g.Diagonal {
+		return dx + dy
+	}
+
+	// octile distance
+	return dx + dy + (math.Sqrt2-2)*math.Min(dx, dy)
+}
+
+// FindPath returns the cells of the shortest path from one cell to another, both included,
+// moving diagonally if the grid allows it, but never across the corner of a blocked cell.
+// It returns false if there is no path.
+func (g *Grid) FindPath(from, to Point) ([]Point, bool) {
+	if !g.Walkable(from.X, from.Y) || !g.Walkable(to.X, to.Y) {
+		return nil, false
+	}
+
+	moves := neighbours[:4]
+	if g.Diagonal {
+		moves = neighbours
+	}
+
+	nodes := map[Point]*node{}
+	cameFrom := map[Point]Point{}
+	closed := map[Point]bool{}
+
+	start := &node{p: from, f: g.heuristic(from, to)}
+	nodes[from] = start
+	open := &openSet{start}
+
+	for open.Len() > 0 {
+		current := heap.Pop(open).(*node)
+		if current.p == to {
+			return reconstruct(cameFrom, from, to), true
+		}
+
+		closed[current.p] = true
+
+		for _, m := range moves {
+			next := Point{current.p.X + m.X, current.p.Y + m.Y}
+			if closed[next] || !g.Walkable(next.X, next.Y) {
+				continue
+			}
+
+			cost := 1.0
+			if m.X != 0 && m.Y != 0 {
+				if !g.Walkable(current.p.X+m.X, current.p.Y) || !g.Walkable(current.p.X, current.p.Y+m.Y) {
+					continue
+				}
+
+				cost = math.Sqrt2
+			}
+
+			gScore := current.g + cost
+
+			n, ok := nodes[next]
+			if ok && gScore >= n.g {
+				continue
+			}
+
+			cameFrom[next] = current.p
+
+			if !ok {
+				n = &node{p: next}
+				nodes[next] = n
+				n.g, n.f = gScore, gScore+g.heuristic(next, to)
+				heap.Push(open, n)
+
+				continue
+			}
+
+			n.g, n.f = gScore, gScore+g.heuristic(next, to)
+			heap.Fix(open, n.index)
+		}
+	}
+
+	return nil, false
+}
+
+// reconstruct returns the path ending at to, walking back the cells it comes from.
+func reconstruct(cameFrom map[Point]Point, from, to Point) []Point {
+	path := []Point{to}
+
+	for p := to; p != from; {
+		p = cameFrom[p]
+		path = append(path, p)
+	}
+
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+
+	return path
+}
+
+// Smooth removes the intermediate cells of a path which can be skipped by walking in a straight line,
+// so that entities following it do not zigzag along the grid.
+func (g *Grid) Smooth(path []Point) []Point {
+	if len(path) < 3 {
+		return path
+	}
+
+	smoothed := []Point{path[0]}
+	anchor := path[0]
+
+	for i := 2; i < len(path); i++ {
+		if !g.LineOfSight(anchor, path[i]) {
+			anchor = path[i-1]
+			smoothed = append(smoothed, anchor)
+		}
+	}
+
+	return append(smoothed, path[len(path)-1])
+}
+
+// LineOfSight returns true if the segment between the centers of two cells only crosses walkable cells.
+// A segment passing exactly through the corner of cells needs all of them to be walkable.
+func (g *Grid) LineOfSight(a, b Point) bool {
+	x, y := a.X, a.Y
+	dx, dy := b.X-a.X, b.Y-a.Y
+	nx, ny := abs(dx), abs(dy)
+	sx, sy := sign(dx), sign(dy)
+
+	for ix, iy := 0, 0; ix < nx || iy < ny; {
+		// compare the next vertical and horizontal crossings: (0.5+ix)/nx against (0.5+iy)/ny
+		d := (1+2*ix)*ny - (1+2*iy)*nx
+
+		switch {
+		case d == 0:
+			if !g.Walkable(x+sx, y) || !g.Walkable(x, y+sy) {
+				return false
+			}
+
+			x, y = x+sx, y+sy
+			ix, iy = ix+1, iy+1
+		case d < 0:
+			x += sx
+			ix++
+		default:
+			y += sy
+			iy++
+		}
+
+		if !g.Walkable(x, y) {
+			return false
+		}
+	}
+
+	return g.Walkable(a.X, a.Y)
+}
+
+// abs returns the absolute value of v.
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+
+	return v
+}
+
+// sign returns -1, 0 or 1 according to the sign of v.
+func sign(v int) int {
+	switch {
+	case v < 0:
+		return -1
+	case v > 0:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,158 @@
+package pathfind
+
+import (
+	"math"
+
+	ecs "github.com/jtbonhomme/ebiten-ecs"
+	"github.com/jtbonhomme/ebiten-ecs/component"
+	"github.com/jtbonhomme/ebiten-ecs/entity"
+	"github.com/jtbonhomme/ebiten-ecs/system"
+)
+
+// Follower is the component of the entities moving along a path.
+// X and Y are the position of the entity in pixels, which the game copies to its own position component
+// if it has one, and Speed is the distance it moves per update, in pixels.
+type Follower struct {
+	X       float64
+	Y       float64
+	Speed   float64
+	path    []Point
+	next    int
+	pending bool
+	failed  bool
+	request uint64
+}
+
+// followerType is the handle used to find the Follower component.
+var followerType = component.NewType[Follower]()
+
+// SetPath makes the entity follow the given path from its first cell.
+func (f *Follower) SetPath(path []Point) {
+	f.path, f.next = path, 0
+	f.pending, f.failed = false, false
+}
+
+// Path returns the path followed by the entity.
+func (f *Follower) Path() []Point {
+	return f.path
+}
+
+// Done returns true if the entity has reached the end of its path, or has no path.
+func (f *Follower) Done() bool {
+	return f.next >= len(f.path)
+}
+
+// Pending returns true while a path requested for the entity from a Solver is being computed.
+func (f *Follower) Pending() bool {
+	return f.pending
+}
+
+// Failed returns true if no path was found by the last request for the entity.
+func (f *Follower) Failed() bool {
+	return f.failed
+}
+
+// FollowSystem is the updater moving the entities with a Follower component toward the center
+// of the next cell of their path.
+type FollowSystem struct {
+	id         system.ID
+	cellWidth  float64
+	cellHeight float64
+}
+
+// NewFollowSystem creates the updater moving entities along their path, on a grid made of cells
+// of the given size in pixels.
+func NewFollowSystem(cellWidth, cellHeight float64) *FollowSystem {
+	return &FollowSystem{
+		id:         system.AssignID(),
+		cellWidth:  cellWidth,
+		cellHeight: cellHeight,
+	}
+}
+
+// ID returns the unique ID of the system.
+func (s *FollowSystem) ID() system.ID {
+	return s.id
+}
+
+// Update moves the entity along its path.
+func (s *FollowSystem) Update(_ entity.ID, c []component.Component, _ map[entity.ID][]component.Component) error {
+	f := followerType.Get(c)
+	if f == nil {
+		return nil
+	}
+
+	step := f.Speed
+
+	for step > 0 && !f.Done() {
+		p := f.path[f.next]
+		tx, ty := (float64(p.X)+0.5)*s.cellWidth, (float64(p.Y)+0.5)*s.cellHeight
+		dx, dy := tx-f.X, ty-f.Y
+
+		dist := math.Hypot(dx, dy)
+		if dist <= step {
+			f.X, f.Y = tx, ty
+			f.next++
+			step -= dist
+
+			continue
+		}
+
+		f.X += dx / dist * step
+		f.Y += dy / dist * step
+		step = 0
+	}
+
+	return nil
+}
+
+// Solver computes paths in background goroutines, so that long searches do not stall the game loop.
+// The paths are given to the Follower component of the entities at the beginning of the next Update
+// once they are found.
+type Solver struct {
+	world  *ecs.ECS
+	smooth bool
+}
+
+// NewSolver creates a solver giving the paths it finds to the entities of the world.
+// If smooth is true, the paths are smoothed (see Grid.Smooth).
+func NewSolver(world *ecs.ECS, smooth bool) *Solver {
+	return &Solver{
+		world:  world,
+		smooth: smooth,
+	}
+}
+
+// Request starts computing a path on the grid from one cell to another for an entity,
+// which must have a Follower component. It must be called from the game loop, e.g. from a system:
+// the search runs on a copy of the grid, so that the grid can be modified while it runs.
+// Only the path of the last request of an entity is given to it.
+func (s *Solver) Request(id entity.ID, grid *Grid, from, to Point) {
+	f := followerType.Get(s.world.EntityComponents(id))
+	if f == nil {
+		return
+	}
+
+	f.pending = true
+	f.request++
+	request := f.request
+
+	grid = grid.Clone()
+
+	go func() {
+		path, ok := grid.FindPath(from, to)
+		if ok && s.smooth {
+			path = grid.Smooth(path)
+		}
+
+		s.world.Defer(func(world *ecs.ECS) {
+			f := followerType.Get(world.EntityComponents(id))
+			if f == nil || f.request != request {
+				return
+			}
+
+			f.SetPath(path)
+			f.failed = !ok
+		})
+	}()
+}
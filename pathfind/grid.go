@@ -0,0 +1,67 @@
+// Package pathfind finds paths on a walkability grid with A*, and moves entities along them.
+// Paths can be computed asynchronously by a Solver, and followed by entities with a Follower component.
+package pathfind
+
+// Point is the position of a cell of a grid.
+type Point struct {
+	X int
+	Y int
+}
+
+// Grid is a walkability grid. It is usually stored as a world resource, so that systems can find it.
+type Grid struct {
+	Width    int
+	Height   int
+	blocked  []bool
+	Diagonal bool
+}
+
+// NewGrid creates a grid of width x height walkable cells.
+func NewGrid(width, height int) *Grid {
+	return &Grid{
+		Width:   width,
+		Height:  height,
+		blocked: make([]bool, width*height),
+	}
+}
+
+// NewGridFrom creates a grid of width x height cells, whose cells are blocked if solid returns true for them.
+// It can be derived from a tilemap collision grid with:
+//
+//	grid := pathfind.NewGridFrom(collision.Width, collision.Height, collision.Solid)
+func NewGridFrom(width, height int, solid func(x, y int) bool) *Grid {
+	g := NewGrid(width, height)
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			g.blocked[y*width+x] = solid(x, y)
+		}
+	}
+
+	return g
+}
+
+// SetWalkable changes whether a cell is walkable. Cells outside of the grid are ignored.
+func (g *Grid) SetWalkable(x, y int, walkable bool) {
+	if g.contains(x, y) {
+		g.blocked[y*g.Width+x] = !walkable
+	}
+}
+
+// Walkable returns true if the cell is walkable. Cells outside of the grid are not.
+func (g *Grid) Walkable(x, y int) bool {
+	return g.contains(x, y) && !g.blocked[y*g.Width+x]
+}
+
+// contains returns true if the cell is inside the grid.
+func (g *Grid) contains(x, y int) bool {
+	return x >= 0 && y >= 0 && x < g.Width && y < g.Height
+}
+
+// Clone returns a copy of the grid.
+func (g *Grid) Clone() *Grid {
+	c := *g
+	c.blocked = append([]bool(nil), g.blocked...)
+
+	return &c
+}
@@ -0,0 +1,29 @@
+package ecs
+
+import "time"
+
+// Pause freezes the simulation: Update no longer runs the updaters, while Draw keeps drawing the world.
+func (ecs *ECS) Pause() {
+	ecs.paused = true
+}
+
+// Resume resumes a paused simulation.
+func (ecs *ECS) Resume() {
+	ecs.paused = false
+	ecs.steps = 0
+	// do not try to catch up with the time spent paused
+	ecs.lastUpdate = time.Time{}
+}
+
+// Paused returns true if the simulation is paused.
+func (ecs *ECS) Paused() bool {
+	return ecs.paused
+}
+
+// Step requests to advance a paused simulation by a single step, run by the next call to Update.
+// It has no effect if the simulation is not paused.
+func (ecs *ECS) Step() {
+	if ecs.paused {
+		ecs.steps++
+	}
+}
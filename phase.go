@@ -0,0 +1,90 @@
+package ecs
+
+import (
+	"github.com/jtbonhomme/ebiten-ecs/component"
+	"github.com/jtbonhomme/ebiten-ecs/entity"
+	"github.com/jtbonhomme/ebiten-ecs/system"
+)
+
+// Phase is a part of a frame during which systems run, measured separately by the profiler.
+type Phase int
+
+// Phases of a frame.
+const (
+	// PhaseUpdate is the phase during which the updaters run, see Update.
+	PhaseUpdate Phase = iota
+	// PhaseDraw is the phase during which the drawers of a rendering package run.
+	PhaseDraw
+)
+
+// BeginPhase starts a new phase of the frame, clearing the profiler measures of the previous frame for this phase.
+// Update begins PhaseUpdate by itself: rendering packages call it with PhaseDraw before running their drawers.
+func (ecs *ECS) BeginPhase(phase Phase) {
+	if ecs.profiler != nil {
+		ecs.profiler.reset(phase)
+	}
+}
+
+// Process calls fn with each active entity associated with the system and its components,
+// and returns the number of entities processed. The run is measured by the profiler as part of the given phase.
+// It lets packages running their own kind of systems, such as drawers, honor the state of the entities.
+// With WithCrashDump, a panic of fn is recovered and returned by the next Update.
+func (ecs *ECS) Process(s system.System, phase Phase, fn func(id entity.ID, components []component.Component)) int {
+	var current entity.ID
+	defer ecs.recoverCrash(s, phase, &current, &ecs.crashErr)
+
+	ecs.iterating++
+	defer func() {
+		ecs.iterating--
+	}()
+
+	var p probe
+	if ecs.profiler != nil {
+		p = ecs.profiler.begin()
+	}
+
+	visited := 0
+
+	for _, e := range ecs.FilterEntities(s) {
+		c, ok := ecs.componentsRegistry[e.ID()]
+		if !ok || !ecs.IsActive(e.ID()) {
+			continue
+		}
+
+		visited++
+		current = e.ID()
+
+		fn(e.ID(), c)
+	}
+
+	if ecs.profiler != nil {
+		ecs.profiler.end(s, phase, p, visited)
+	}
+
+	return visited
+}
+
+// ProcessEntity calls fn with a single entity on behalf of the system, as Process does, for packages which
+// run a system on entities they collected earlier, e.g. to draw them in another order. The run is measured
+// by the profiler as part of the given phase, without counting the entity again, and with WithCrashDump
+// a panic of fn is recovered and returned by the next Update.
+func (ecs *ECS) ProcessEntity(s system.System, phase Phase, id entity.ID, components []component.Component, fn func(id entity.ID, components []component.Component)) {
+	current := id
+	defer ecs.recoverCrash(s, phase, &current, &ecs.crashErr)
+
+	ecs.iterating++
+	defer func() {
+		ecs.iterating--
+	}()
+
+	var p probe
+	if ecs.profiler != nil {
+		p = ecs.profiler.begin()
+	}
+
+	fn(id, components)
+
+	if ecs.profiler != nil {
+		ecs.profiler.end(s, phase, p, 0)
+	}
+}
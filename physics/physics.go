@@ -0,0 +1,182 @@
+// Package physics binds a physics engine to the world: the RigidBody and Collider components of the entities
+// are synchronized with the bodies of the engine, the engine is stepped once per world step, and the contacts
+// it reports are published as events.
+//
+// The package does not depend on any physics engine: an engine such as cp (Chipmunk) or box2d is plugged in
+// by implementing the Engine interface around it.
+package physics
+
+import (
+	ecs "github.com/jtbonhomme/ebiten-ecs"
+	"github.com/jtbonhomme/ebiten-ecs/component"
+	"github.com/jtbonhomme/ebiten-ecs/entity"
+	"github.com/jtbonhomme/ebiten-ecs/system"
+)
+
+// RigidBody is the component holding the state of the body of an entity.
+// It is updated from the engine after each step. Changes made by systems must be followed by a call to Wake,
+// so that they are pushed to the engine before the next step.
+type RigidBody struct {
+	X               float64
+	Y               float64
+	Angle           float64
+	VelocityX       float64
+	VelocityY       float64
+	AngularVelocity float64
+	Mass            float64
+	// Static bodies do not move, whatever the forces applied to them.
+	Static bool
+	dirty  bool
+}
+
+// Wake marks the body as changed by a system, so that its state is pushed to the engine before the next step.
+func (b *RigidBody) Wake() {
+	b.dirty = true
+}
+
+// Place moves the body to a position of the world and wakes it, e.g. when it is spawned (see spawn.Placer).
+func (b *RigidBody) Place(x, y float64) {
+	b.X, b.Y = x, y
+	b.Wake()
+}
+
+// Shape is the shape of a collider.
+type Shape int
+
+// Shapes of the colliders.
+const (
+	Circle Shape = iota
+	Box
+)
+
+// Collider is the component describing the shape of the body of an entity, centered on its position.
+type Collider struct {
+	Shape      Shape
+	Radius     float64
+	Width      float64
+	Height     float64
+	Friction   float64
+	Elasticity float64
+	// Sensor colliders report contacts without colliding.
+	Sensor bool
+}
+
+// Contact is a contact between the bodies of two entities reported by the engine during a step.
+type Contact struct {
+	A entity.ID
+	B entity.ID
+	// NormalX and NormalY are the normal of the contact, from A to B.
+	NormalX float64
+	NormalY float64
+	// Began is true when the bodies start touching, and false when they stop.
+	Began bool
+}
+
+// Engine is the interface of a physics engine. Bodies are identified by the ID of their entity.
+type Engine interface {
+	// Add creates the body of an entity.
+	Add(id entity.ID, body *RigidBody, collider *Collider) error
+	// Remove destroys the body of an entity.
+	Remove(id entity.ID)
+	// Push sets the state of the body of an entity from its component.
+	Push(id entity.ID, body *RigidBody)
+	// Pull sets the component from the state of the body of an entity.
+	Pull(id entity.ID, body *RigidBody)
+	// Step advances the simulation by dt seconds, and returns the contacts which began or ended during the step.
+	Step(dt float64) []Contact
+}
+
+// Events is the world resource holding the contacts reported during the last step.
+type Events struct {
+	Contacts []Contact
+}
+
+var (
+	bodyType     = component.NewType[RigidBody]()
+	colliderType = component.NewType[Collider]()
+)
+
+// System is the updater synchronizing the bodies of its entities with the engine.
+// It steps the engine once per world step, before synchronizing its entities, so it should run
+// after the systems changing the bodies: use WithFixedTimestep for the world to step at a regular pace.
+type System struct {
+	id     system.ID
+	world  *ecs.ECS
+	engine Engine
+	dt     float64
+	bodies map[entity.ID]*RigidBody
+	events *Events
+}
+
+// NewSystem creates the updater binding the engine to the world, stepping it by dt seconds per world step,
+// and adds the Events resource to the world.
+func NewSystem(world *ecs.ECS, engine Engine, dt float64) *System {
+	events := &Events{}
+	world.SetResource(events)
+
+	return &System{
+		id:     system.AssignID(),
+		world:  world,
+		engine: engine,
+		dt:     dt,
+		bodies: make(map[entity.ID]*RigidBody),
+		events: events,
+	}
+}
+
+// ID returns the unique ID of the system.
+func (s *System) ID() system.ID {
+	return s.id
+}
+
+// BeginStep steps the engine, see step.
+func (s *System) BeginStep() error {
+	s.step()
+	return nil
+}
+
+// Update adds the body of the entity to the engine the first time the entity is seen.
+func (s *System) Update(id entity.ID, c []component.Component, _ map[entity.ID][]component.Component) error {
+	if _, ok := s.bodies[id]; ok {
+		return nil
+	}
+
+	body := bodyType.Get(c)
+	if body == nil {
+		return nil
+	}
+
+	err := s.engine.Add(id, body, colliderType.Get(c))
+	if err != nil {
+		return err
+	}
+
+	s.bodies[id] = body
+	body.dirty = false
+
+	return nil
+}
+
+// step removes the bodies of the unregistered entities, pushes the changed bodies to the engine,
+// steps it, and pulls the state of every body.
+func (s *System) step() {
+	for id, body := range s.bodies {
+		if !s.world.Registered(id) {
+			s.engine.Remove(id)
+			delete(s.bodies, id)
+
+			continue
+		}
+
+		if body.dirty {
+			s.engine.Push(id, body)
+			body.dirty = false
+		}
+	}
+
+	s.events.Contacts = append(s.events.Contacts[:0], s.engine.Step(s.dt)...)
+
+	for id, body := range s.bodies {
+		s.engine.Pull(id, body)
+	}
+}
@@ -0,0 +1,51 @@
+package physics_test
+
+import (
+	"testing"
+
+	"github.com/jtbonhomme/ebiten-ecs/ecstest"
+	"github.com/jtbonhomme/ebiten-ecs/entity"
+	"github.com/jtbonhomme/ebiten-ecs/physics"
+)
+
+// engine is an engine recording the bodies it holds.
+type engine struct {
+	bodies map[entity.ID]struct{}
+}
+
+func (e *engine) Add(id entity.ID, _ *physics.RigidBody, _ *physics.Collider) error {
+	e.bodies[id] = struct{}{}
+	return nil
+}
+
+func (e *engine) Remove(id entity.ID) {
+	delete(e.bodies, id)
+}
+
+func (e *engine) Push(entity.ID, *physics.RigidBody) {}
+
+func (e *engine) Pull(entity.ID, *physics.RigidBody) {}
+
+func (e *engine) Step(float64) []physics.Contact {
+	return nil
+}
+
+func TestLastBodyRemoved(t *testing.T) {
+	world := ecstest.NewWorld(t)
+	eng := &engine{bodies: make(map[entity.ID]struct{})}
+
+	e := world.Spawn(&physics.RigidBody{})
+	world.RegisterUpdater(physics.NewSystem(world, eng, 1.0/60), e)
+	ecstest.AdvanceTicks(t, world, 1)
+
+	if len(eng.bodies) != 1 {
+		t.Fatalf("the engine holds %d bodies, want 1", len(eng.bodies))
+	}
+
+	world.UnregisterEntity(e.ID())
+	ecstest.AdvanceTicks(t, world, 1)
+
+	if len(eng.bodies) != 0 {
+		t.Errorf("the engine holds %d bodies after the last one was unregistered, want none", len(eng.bodies))
+	}
+}
@@ -0,0 +1,119 @@
+package ecs
+
+import (
+	"github.com/jtbonhomme/ebiten-ecs/component"
+	"github.com/jtbonhomme/ebiten-ecs/entity"
+	"github.com/jtbonhomme/ebiten-ecs/system"
+)
+
+// Prefab is a function returning the components of a new entity.
+// It is called once per entity created from the prefab.
+type Prefab func() []component.Component
+
+// Pool is a set of entities created from the same prefab and reused instead of being
+// unregistered and garbage-collected, e.g. for bullets in a shmup.
+// Pooled entities stay registered in the world: released entities are only made inactive.
+// Entities of the pool unregistered from the world, e.g. by Clear, are dropped from the pool.
+type Pool struct {
+	world   *ECS
+	prefab  Prefab
+	systems []system.System
+	free    []entity.Entity
+	inUse   map[entity.ID]struct{}
+	size    int
+}
+
+// Pool creates a pool of size entities made from the prefab and associated with the given systems.
+// All the entities of the pool are created and registered upfront, inactive.
+func (ecs *ECS) Pool(prefab Prefab, size int, systems ...system.System) *Pool {
+	p := &Pool{
+		world:   ecs,
+		prefab:  prefab,
+		systems: systems,
+		free:    make([]entity.Entity, 0, size),
+		inUse:   make(map[entity.ID]struct{}),
+	}
+
+	for i := 0; i < size; i++ {
+		p.free = append(p.free, p.spawn())
+	}
+
+	return p
+}
+
+// spawn creates a new inactive entity from the prefab.
+func (p *Pool) spawn() entity.Entity {
+	e := entity.New()
+	p.world.RegisterComponents(e, p.prefab()...)
+	p.world.SetActive(e.ID(), false)
+
+	for _, s := range p.systems {
+		p.world.Associate(s, e)
+	}
+
+	p.size++
+
+	return e
+}
+
+// Acquire returns an entity of the pool and makes it active.
+// If all the entities of the pool are in use, the pool grows by one entity.
+// The components of a reused entity keep the values they had when it was released.
+func (p *Pool) Acquire() entity.Entity {
+	e, ok := p.pop()
+	if !ok {
+		e = p.spawn()
+	}
+
+	p.inUse[e.ID()] = struct{}{}
+	p.world.SetActive(e.ID(), true)
+
+	return e
+}
+
+// pop takes a free entity, dropping the free entities which were unregistered from the world.
+// It returns false if there is none.
+func (p *Pool) pop() (entity.Entity, bool) {
+	for len(p.free) > 0 {
+		e := p.free[len(p.free)-1]
+		p.free = p.free[:len(p.free)-1]
+
+		if p.world.Registered(e.ID()) {
+			return e, true
+		}
+
+		p.size--
+	}
+
+	return entity.Entity{}, false
+}
+
+// Release makes an acquired entity inactive and gives it back to the pool.
+// Entities which are not in use, either already released or not from the pool, are ignored,
+// as well as the entities unregistered from the world, which are dropped from the pool.
+func (p *Pool) Release(e entity.Entity) {
+	if _, ok := p.inUse[e.ID()]; !ok {
+		return
+	}
+
+	delete(p.inUse, e.ID())
+
+	if !p.world.Registered(e.ID()) {
+		p.size--
+		return
+	}
+
+	p.world.SetActive(e.ID(), false)
+	p.free = append(p.free, e)
+}
+
+// Size returns the total number of entities of the pool.
+func (p *Pool) Size() int {
+	return p.size
+}
+
+// Available returns the number of entities of the pool which are not in use, including the ones unregistered
+// from the world since they were released, which are dropped when met by Acquire.
+func (p *Pool) Available() int {
+	return len(p.free)
+}
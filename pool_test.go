@@ -0,0 +1,50 @@
+package ecs_test
+
+import (
+	"testing"
+
+	ecs "github.com/jtbonhomme/ebiten-ecs"
+	"github.com/jtbonhomme/ebiten-ecs/component"
+)
+
+func newPosition() []component.Component {
+	return component.NewSlice(&position{})
+}
+
+func TestPoolAfterClear(t *testing.T) {
+	world := ecs.New()
+	pool := world.Pool(newPosition, 2)
+	used := pool.Acquire()
+
+	world.Clear(false)
+	pool.Release(used)
+
+	for i := 0; i < 3; i++ {
+		e := pool.Acquire()
+		if len(world.EntityComponents(e.ID())) != 1 || !world.IsActive(e.ID()) {
+			t.Fatalf("acquire %d: the entity %s is not registered and active", i, e.ID())
+		}
+	}
+
+	if n := pool.Size(); n != 3 {
+		t.Errorf("size = %d, want 3", n)
+	}
+}
+
+func TestPoolDoubleRelease(t *testing.T) {
+	world := ecs.New()
+	pool := world.Pool(newPosition, 1)
+	e := pool.Acquire()
+
+	pool.Release(e)
+	pool.Release(e)
+	pool.Release(world.Spawn(&position{}))
+
+	if n := pool.Available(); n != 1 {
+		t.Fatalf("available = %d, want 1", n)
+	}
+
+	if a, b := pool.Acquire(), pool.Acquire(); a == b {
+		t.Errorf("the entity %s was acquired twice", a.ID())
+	}
+}
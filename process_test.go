@@ -0,0 +1,31 @@
+package ecs_test
+
+import (
+	"errors"
+	"testing"
+
+	ecs "github.com/jtbonhomme/ebiten-ecs"
+	"github.com/jtbonhomme/ebiten-ecs/component"
+	"github.com/jtbonhomme/ebiten-ecs/entity"
+)
+
+func TestProcessEntityRecoversCrash(t *testing.T) {
+	world := ecs.New(ecs.WithCrashDump(t.TempDir(), false))
+	e := world.Spawn(&position{})
+	v := &visitor{}
+
+	world.ProcessEntity(v, ecs.PhaseDraw, e.ID(), world.EntityComponents(e.ID()), func(entity.ID, []component.Component) {
+		panic("boom")
+	})
+
+	crash := &ecs.CrashError{}
+
+	err := world.Update()
+	if !errors.As(err, &crash) {
+		t.Fatalf("Update returned %v, want a crash error", err)
+	}
+
+	if crash.Entity != e.ID() || crash.Phase != ecs.PhaseDraw {
+		t.Errorf("crash of entity %s in phase %v, want entity %s in the draw phase", crash.Entity, crash.Phase, e.ID())
+	}
+}
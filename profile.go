@@ -0,0 +1,178 @@
+package ecs
+
+import (
+	"fmt"
+	"runtime/metrics"
+	"time"
+
+	"github.com/jtbonhomme/ebiten-ecs/system"
+)
+
+// allocsMetric is the runtime metric counting the heap allocations of the program.
+const allocsMetric = "/gc/heap/allocs:objects"
+
+// SystemStats holds the measures of a system during the last frame.
+// When the world runs several fixed steps per frame, the update measures add up all the steps.
+type SystemStats struct {
+	ID             system.ID
+	Name           string
+	UpdateDuration time.Duration
+	UpdateEntities int
+	UpdateAllocs   uint64
+	DrawDuration   time.Duration
+	DrawEntities   int
+	DrawAllocs     uint64
+}
+
+// Stats holds the counts of the world, and the measures of its systems during the last frame.
+type Stats struct {
+	// Entities is the number of registered entities, including the inactive and dead ones,
+	// and PeakEntities the highest number of registered entities since the world was created.
+	Entities         int
+	InactiveEntities int
+	DeadEntities     int
+	PeakEntities     int
+	// Components is the number of components per type name (see component.Register), or Go type.
+	Components map[string]int
+	// Updaters is the number of registered updaters, and Drawers the number of systems registered
+	// by rendering packages.
+	Updaters int
+	Drawers  int
+	// Memory is an estimate of the memory used by the components and the stores, in bytes,
+	// and PeakMemory the highest estimate since the world was created.
+	Memory     int64
+	PeakMemory int64
+	Systems    []SystemStats
+}
+
+type profiler struct {
+	stats  map[system.ID]*SystemStats
+	order  []system.ID
+	sample []metrics.Sample
+}
+
+// probe is the state of the program when a system starts running.
+type probe struct {
+	start  time.Time
+	allocs uint64
+}
+
+// EnableProfiling turns on the measure of the duration, the number of visited entities and the number of
+// heap allocations of every system, available through Stats.
+func (ecs *ECS) EnableProfiling() {
+	if ecs.profiler == nil {
+		ecs.profiler = &profiler{
+			stats:  make(map[system.ID]*SystemStats),
+			sample: []metrics.Sample{{Name: allocsMetric}},
+		}
+	}
+}
+
+// DisableProfiling turns off the measure of the systems.
+func (ecs *ECS) DisableProfiling() {
+	ecs.profiler = nil
+}
+
+// Stats returns the counts of the world, and the measures of the last frame, with the systems in the order
+// they first ran. The counts are kept up to date as entities are registered, so that Stats is cheap enough
+// to be called every frame by a debug overlay. The systems measures are only available when profiling is enabled.
+func (ecs *ECS) Stats() Stats {
+	c := &ecs.counters
+
+	c.storeBytes = 0
+	for _, s := range ecs.stores {
+		c.storeBytes += s.memory()
+	}
+
+	c.peakBytes = max(c.peakBytes, c.bytes+c.storeBytes)
+
+	stats := Stats{
+		Entities:         len(ecs.componentsRegistry),
+		InactiveEntities: len(ecs.inactiveEntities),
+		DeadEntities:     len(ecs.dying),
+		PeakEntities:     c.peakEntities,
+		Components:       make(map[string]int, len(c.components)),
+		Updaters:         len(ecs.updaters),
+		Drawers:          max(ecs.systems-len(ecs.updaters), 0),
+		Memory:           c.bytes + c.storeBytes,
+		PeakMemory:       c.peakBytes,
+	}
+
+	for t, n := range c.components {
+		if n > 0 {
+			stats.Components[c.names[t]] = n
+		}
+	}
+
+	if ecs.profiler != nil {
+		stats.Systems = make([]SystemStats, 0, len(ecs.profiler.order))
+		for _, id := range ecs.profiler.order {
+			stats.Systems = append(stats.Systems, *ecs.profiler.stats[id])
+		}
+	}
+
+	return stats
+}
+
+// allocs returns the number of heap allocations since the program started.
+func (p *profiler) allocs() uint64 {
+	metrics.Read(p.sample)
+
+	if p.sample[0].Value.Kind() != metrics.KindUint64 {
+		return 0
+	}
+
+	return p.sample[0].Value.Uint64()
+}
+
+// begin starts measuring a system.
+func (p *profiler) begin() probe {
+	return probe{
+		start:  time.Now(),
+		allocs: p.allocs(),
+	}
+}
+
+// systemStats returns the measures of a system, creating them the first time the system runs.
+func (p *profiler) systemStats(s system.System) *SystemStats {
+	stats, ok := p.stats[s.ID()]
+	if !ok {
+		stats = &SystemStats{
+			ID:   s.ID(),
+			Name: fmt.Sprintf("%T", unwrap(s)),
+		}
+		p.stats[s.ID()] = stats
+		p.order = append(p.order, s.ID())
+	}
+
+	return stats
+}
+
+// end adds the measures of a system which started running at probe pr during the given phase.
+func (p *profiler) end(s system.System, phase Phase, pr probe, visited int) {
+	stats := p.systemStats(s)
+	duration, allocs := time.Since(pr.start), p.allocs()-pr.allocs
+
+	if phase == PhaseDraw {
+		stats.DrawDuration += duration
+		stats.DrawAllocs += allocs
+		stats.DrawEntities += visited
+
+		return
+	}
+
+	stats.UpdateDuration += duration
+	stats.UpdateAllocs += allocs
+	stats.UpdateEntities += visited
+}
+
+// reset clears the measures of a phase at the beginning of a frame.
+func (p *profiler) reset(phase Phase) {
+	for _, stats := range p.stats {
+		if phase == PhaseDraw {
+			stats.DrawDuration, stats.DrawEntities, stats.DrawAllocs = 0, 0, 0
+		} else {
+			stats.UpdateDuration, stats.UpdateEntities, stats.UpdateAllocs = 0, 0, 0
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package ecs
+
+import (
+	"slices"
+
+	"github.com/jtbonhomme/ebiten-ecs/component"
+	"github.com/jtbonhomme/ebiten-ecs/entity"
+)
+
+// query is the part of the typed queries keeping the sorted list of the entities matching them,
+// refreshed whenever entities have been registered or unregistered since the last iteration.
+type query struct {
+	world   *ECS
+	version uint64
+	ids     []entity.ID
+	fresh   bool
+	// iterating counts the iterations in progress, during which ids must not be overwritten
+	iterating int
+}
+
+// refresh lists again the entities having all the given component types, if the world changed.
+func (q *query) refresh(types ...component.TypeID) {
+	if q.fresh && q.version == q.world.structure {
+		return
+	}
+
+	if q.iterating > 0 {
+		q.ids = nil
+	}
+
+	q.ids = q.ids[:0]
+
+	for id, c := range q.world.componentsRegistry {
+		matches := true
+
+		for _, t := range types {
+			if !hasType(c, t) {
+				matches = false
+				break
+			}
+		}
+
+		if matches {
+			q.ids = append(q.ids, id)
+		}
+	}
+
+	slices.Sort(q.ids)
+	q.version, q.fresh = q.world.structure, true
+}
+
+// Query1 iterates over the active entities having a component of type *A, without reflection.
+type Query1[A any] struct {
+	query
+	a component.Type[A]
+}
+
+// NewQuery1 creates a query over the entities of the world having a component of type *A.
+// A query is meant to be created once, e.g. when a system is created, and iterated every step.
+func NewQuery1[A any](world *ECS) *Query1[A] {
+	return &Query1[A]{
+		query: query{world: world},
+		a:     component.NewType[A](),
+	}
+}
+
+// Each calls fn for each active entity of the query, by increasing ID.
+// Entities registered by fn during the iteration are only seen by the next one.
+func (q *Query1[A]) Each(fn func(id entity.ID, a *A)) {
+	q.refresh(q.a.ID())
+
+	q.iterating++
+	defer func() { q.iterating-- }()
+
+	for _, id := range q.ids {
+		// entities unregistered by fn during the iteration are skipped
+		c, ok := q.world.componentsRegistry[id]
+		if !ok || !q.world.IsActive(id) {
+			continue
+		}
+
+		fn(id, q.a.Get(c))
+	}
+}
+
+// Query2 iterates over the active entities having components of types *A and *B, without reflection:
+//
+//	movement := ecs.NewQuery2[Position, Velocity](world)
+//
+//	movement.Each(func(id entity.ID, p *Position, v *Velocity) {
+//		p.X += v.X
+//		p.Y += v.Y
+//	})
+type Query2[A, B any] struct {
+	query
+	a component.Type[A]
+	b component.Type[B]
+}
+
+// NewQuery2 creates a query over the entities of the world having components of types *A and *B.
+// A query is meant to be created once, e.g. when a system is created, and iterated every step.
+func NewQuery2[A, B any](world *ECS) *Query2[A, B] {
+	return &Query2[A, B]{
+		query: query{world: world},
+		a:     component.NewType[A](),
+		b:     component.NewType[B](),
+	}
+}
+
+// Each calls fn for each active entity of the query, by increasing ID.
+func (q *Query2[A, B]) Each(fn func(id entity.ID, a *A, b *B)) {
+	q.refresh(q.a.ID(), q.b.ID())
+
+	q.iterating++
+	defer func() { q.iterating-- }()
+
+	for _, id := range q.ids {
+		// entities unregistered by fn during the iteration are skipped
+		c, ok := q.world.componentsRegistry[id]
+		if !ok || !q.world.IsActive(id) {
+			continue
+		}
+
+		fn(id, q.a.Get(c), q.b.Get(c))
+	}
+}
+
+// Query3 iterates over the active entities having components of types *A, *B and *C, without reflection.
+type Query3[A, B, C any] struct {
+	query
+	a component.Type[A]
+	b component.Type[B]
+	c component.Type[C]
+}
+
+// NewQuery3 creates a query over the entities of the world having components of types *A, *B and *C.
+// A query is meant to be created once, e.g. when a system is created, and iterated every step.
+func NewQuery3[A, B, C any](world *ECS) *Query3[A, B, C] {
+	return &Query3[A, B, C]{
+		query: query{world: world},
+		a:     component.NewType[A](),
+		b:     component.NewType[B](),
+		c:     component.NewType[C](),
+	}
+}
+
+// Each calls fn for each active entity of the query, by increasing ID.
+func (q *Query3[A, B, C]) Each(fn func(id entity.ID, a *A, b *B, c *C)) {
+	q.refresh(q.a.ID(), q.b.ID(), q.c.ID())
+
+	q.iterating++
+	defer func() { q.iterating-- }()
+
+	for _, id := range q.ids {
+		// entities unregistered by fn during the iteration are skipped
+		c, ok := q.world.componentsRegistry[id]
+		if !ok || !q.world.IsActive(id) {
+			continue
+		}
+
+		fn(id, q.a.Get(c), q.b.Get(c), q.c.Get(c))
+	}
+}
@@ -0,0 +1,127 @@
+package ecs
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"math/rand/v2"
+	"reflect"
+
+	"github.com/jtbonhomme/ebiten-ecs/system"
+)
+
+// RandStream returns the random number generator of the stream with the given name, created on first use.
+// Streams are seeded from the seed of the world and their name, so that a system drawing numbers from its own
+// stream does not shift the numbers drawn by the others: adding, removing or reordering a system, or changing
+// how many numbers it draws, keeps the other systems deterministic across versions of a game.
+// Like the generator of the world, streams are reseeded by Replay and their state is part of the world snapshots.
+func (ecs *ECS) RandStream(name string) *rand.Rand {
+	s, ok := ecs.streams[name]
+	if !ok {
+		s = &randStream{pcg: rand.NewPCG(streamSeed(ecs.seed, name))}
+		s.rand = rand.New(s.pcg)
+		ecs.streams[name] = s
+	}
+
+	return s.rand
+}
+
+// RandStreamNamer is implemented by systems which need their own random stream while several systems
+// of the same type run in a world, e.g. one spawner per wave. The name MUST be stable across runs and versions
+// of the game for the stream to be the same.
+type RandStreamNamer interface {
+	RandStreamName() string
+}
+
+// SystemRand returns the random number generator of the stream of a system, see RandStream.
+// The stream is named after the Go type of the system, and the name returned by its RandStreamName method
+// if it implements RandStreamNamer: it does not depend on the order the systems are created in, but
+// the systems of the same type without name share a stream.
+func (ecs *ECS) SystemRand(s system.System) *rand.Rand {
+	return ecs.RandStream(systemStreamName(unwrap(s)))
+}
+
+// systemStreamName returns the name of the random stream of a system.
+func systemStreamName(s system.System) string {
+	t := reflect.TypeOf(s)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	name := "system/" + t.PkgPath() + "." + t.Name()
+
+	if n, ok := s.(RandStreamNamer); ok {
+		name += "/" + n.RandStreamName()
+	}
+
+	return name
+}
+
+// randStream is a named stream of random numbers.
+type randStream struct {
+	pcg  *rand.PCG
+	rand *rand.Rand
+}
+
+// streamSeed returns the seed of the stream with the given name.
+func streamSeed(seed int64, name string) (uint64, uint64) {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(name))
+
+	return uint64(seed), h.Sum64()
+}
+
+// randState is the state of the random number generators of a world, written in the header of the recordings
+// so that a recording started after numbers were drawn replays from the same state.
+type randState struct {
+	Rand    []byte            `json:"rand"`
+	Streams map[string][]byte `json:"streams,omitempty"`
+}
+
+// marshalRand serializes the state of the random number generators of the world.
+func (ecs *ECS) marshalRand() (json.RawMessage, error) {
+	state := randState{Streams: make(map[string][]byte, len(ecs.streams))}
+
+	var err error
+
+	state.Rand, err = ecs.pcg.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	for name, s := range ecs.streams {
+		state.Streams[name], err = s.pcg.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return json.Marshal(state)
+}
+
+// unmarshalRand restores the state of the random number generators of the world serialized by marshalRand.
+// The streams missing from the state are reset to their initial state.
+func (ecs *ECS) unmarshalRand(data json.RawMessage) error {
+	state := randState{}
+
+	err := json.Unmarshal(data, &state)
+	if err != nil {
+		return fmt.Errorf("invalid random number generator state: %w", err)
+	}
+
+	err = ecs.pcg.UnmarshalBinary(state.Rand)
+	if err != nil {
+		return fmt.Errorf("invalid random number generator state: %w", err)
+	}
+
+	for name, b := range state.Streams {
+		ecs.RandStream(name)
+
+		err = ecs.streams[name].pcg.UnmarshalBinary(b)
+		if err != nil {
+			return fmt.Errorf("invalid state of the random stream %q: %w", name, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,53 @@
+package ecs_test
+
+import (
+	"testing"
+
+	ecs "github.com/jtbonhomme/ebiten-ecs"
+)
+
+// spawner is a system of which several instances run in a world, each with its own random stream.
+type spawner struct {
+	visitor
+	name string
+}
+
+func (s *spawner) RandStreamName() string {
+	return s.name
+}
+
+func TestSystemRandIgnoresCreationOrder(t *testing.T) {
+	first := ecs.New(ecs.WithDeterministic(3))
+	v := &visitor{}
+	first.RegisterUpdater(v)
+	want := first.SystemRand(v).Uint64()
+
+	// systems created before, in this world or in another one, do not shift the stream
+	second := ecs.New(ecs.WithDeterministic(3))
+	for i := 0; i < 3; i++ {
+		first.RegisterUpdater(&spawner{name: "other"})
+		second.RegisterUpdater(&spawner{name: "other"})
+	}
+
+	v = &visitor{}
+	second.RegisterUpdater(v)
+
+	if got := second.SystemRand(v).Uint64(); got != want {
+		t.Errorf("SystemRand drew %d after other systems were created, want %d", got, want)
+	}
+}
+
+func TestSystemRandNamedStreams(t *testing.T) {
+	world := ecs.New(ecs.WithDeterministic(3))
+	a, b := &spawner{name: "a"}, &spawner{name: "b"}
+	world.RegisterUpdater(a)
+	world.RegisterUpdater(b)
+
+	if world.SystemRand(a) == world.SystemRand(b) {
+		t.Error("systems of the same type with different names share a stream")
+	}
+
+	if world.SystemRand(a) != world.RandStream("system/github.com/jtbonhomme/ebiten-ecs_test.spawner/a") {
+		t.Error("the stream of a system is not named after its type and name")
+	}
+}
@@ -0,0 +1,182 @@
+package ecs
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+
+	"github.com/jtbonhomme/ebiten-ecs/entity"
+)
+
+// EntityRef is a reference to another entity, held in a field of a component (the target of a homing missile,
+// the parent of an attached entity...). A reference set with SetRef is tracked by the world:
+// when its target is unregistered, the reference is nulled and the holder is notified,
+// so that components never hold dangling entity IDs. The zero EntityRef refers to no entity.
+type EntityRef struct {
+	id entity.ID
+}
+
+// ID returns the ID of the referenced entity, 0 if the reference is null.
+func (r EntityRef) ID() entity.ID {
+	return r.id
+}
+
+// Entity returns the referenced entity, the zero Entity if the reference is null.
+func (r EntityRef) Entity() entity.Entity {
+	return entity.FromID(r.id)
+}
+
+// IsZero returns true if the reference refers to no entity.
+func (r EntityRef) IsZero() bool {
+	return r.id == 0
+}
+
+// MarshalJSON writes the ID of the referenced entity, so that references are exported and saved
+// (see ExportEntities and Save) along with the components holding them.
+func (r EntityRef) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.id)
+}
+
+// UnmarshalJSON reads the ID of the referenced entity. The reference is not tracked: ImportEntities and Load
+// remap it to the new ID of its target and track it.
+func (r *EntityRef) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &r.id)
+}
+
+// RefHandler is a function called when the target of a reference held by an entity is unregistered,
+// after the reference has been nulled.
+type RefHandler func(holder, target entity.ID)
+
+// refLink is a reference tracked by the world.
+type refLink struct {
+	holder  entity.ID
+	ref     *EntityRef
+	handler RefHandler
+}
+
+// SetRef makes a reference, a field of a component of the holder entity, refer to the target entity,
+// and tracks it: when the target is unregistered, the reference is nulled and the handler, if not nil,
+// is called. A zero target nulls the reference. The reference stops being tracked when the holder
+// is unregistered, or when it is set again.
+// The method panics if the holder or the target is not registered.
+func (ecs *ECS) SetRef(holder entity.ID, ref *EntityRef, target entity.ID, handler RefHandler) {
+	if _, ok := ecs.componentsRegistry[holder]; !ok {
+		panic(fmt.Sprintf("the holder %s of the reference MUST be registered", holder))
+	}
+
+	if target != 0 {
+		if _, ok := ecs.componentsRegistry[target]; !ok {
+			panic(fmt.Sprintf("the target %s of the reference MUST be registered", target))
+		}
+	}
+
+	ecs.untrackRef(ref)
+	ref.id = target
+	link := refLink{holder: holder, ref: ref, handler: handler}
+
+	// the references held are kept while the holder is registered, even once nulled,
+	// so that Restore tracks them again if a snapshot brings their target back
+	held := ecs.refsHeld[holder]
+	i := slices.IndexFunc(held, func(l refLink) bool { return l.ref == ref })
+	if i < 0 {
+		ecs.refsHeld[holder] = append(held, link)
+	} else {
+		held[i] = link
+	}
+
+	if target != 0 {
+		ecs.refsTo[target] = append(ecs.refsTo[target], link)
+	}
+}
+
+// untrackRef stops tracking a reference to its current target.
+func (ecs *ECS) untrackRef(ref *EntityRef) {
+	if ref.id == 0 {
+		return
+	}
+
+	links := ecs.refsTo[ref.id]
+	for i, l := range links {
+		if l.ref == ref {
+			ecs.refsTo[ref.id] = append(links[:i], links[i+1:]...)
+			break
+		}
+	}
+
+	if len(ecs.refsTo[ref.id]) == 0 {
+		delete(ecs.refsTo, ref.id)
+	}
+}
+
+// unlinkRefs nulls the references to an unregistered entity, notifying their holders,
+// and stops tracking the references it held.
+func (ecs *ECS) unlinkRefs(id entity.ID) {
+	for _, l := range ecs.refsHeld[id] {
+		ecs.untrackRef(l.ref)
+	}
+
+	delete(ecs.refsHeld, id)
+
+	links, ok := ecs.refsTo[id]
+	if !ok {
+		return
+	}
+
+	delete(ecs.refsTo, id)
+
+	for _, l := range links {
+		l.ref.id = 0
+
+		if l.handler != nil {
+			l.handler(l.holder, id)
+		}
+	}
+}
+
+// retrackRefs tracks again the references held by the registered entities after their values were restored
+// from a snapshot. The references to entities unregistered since then are nulled, without notifying their holders
+// which were notified when the entities were unregistered.
+func (ecs *ECS) retrackRefs() {
+	clear(ecs.refsTo)
+
+	for _, held := range ecs.refsHeld {
+		for _, l := range held {
+			if l.ref.id == 0 {
+				continue
+			}
+
+			if _, ok := ecs.componentsRegistry[l.ref.id]; !ok {
+				l.ref.id = 0
+				continue
+			}
+
+			ecs.refsTo[l.ref.id] = append(ecs.refsTo[l.ref.id], l)
+		}
+	}
+}
+
+// entityRefType is the type of the references, found in the components by visitRefs.
+var entityRefType = reflect.TypeFor[EntityRef]()
+
+// visitRefs calls fn with each reference held by a value, in its exported fields, arrays and slices,
+// e.g. to remap the references of the imported components.
+func visitRefs(v reflect.Value, fn func(ref *EntityRef)) {
+	switch v.Kind() {
+	case reflect.Struct:
+		if v.Type() == entityRefType {
+			fn(v.Addr().Interface().(*EntityRef))
+			return
+		}
+
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				visitRefs(v.Field(i), fn)
+			}
+		}
+	case reflect.Array, reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			visitRefs(v.Index(i), fn)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package ecs_test
+
+import (
+	"bytes"
+	"testing"
+
+	ecs "github.com/jtbonhomme/ebiten-ecs"
+	"github.com/jtbonhomme/ebiten-ecs/component"
+	"github.com/jtbonhomme/ebiten-ecs/entity"
+)
+
+type target struct {
+	Ref ecs.EntityRef
+}
+
+var targetType = component.NewType[target]()
+
+func TestRetargetedRef(t *testing.T) {
+	world := ecs.New()
+	h := &target{}
+	holder := world.Spawn(h)
+	a, b := world.Spawn(&position{}), world.Spawn(&position{})
+
+	notified := []entity.ID{}
+	handler := func(_, id entity.ID) { notified = append(notified, id) }
+
+	world.SetRef(holder.ID(), &h.Ref, a.ID(), handler)
+	world.SetRef(holder.ID(), &h.Ref, b.ID(), handler)
+	world.UnregisterEntity(a.ID())
+
+	if h.Ref.ID() != b.ID() || len(notified) != 0 {
+		t.Fatalf("ref = %s, notified %v, want %s and no notification", h.Ref.ID(), notified, b.ID())
+	}
+
+	world.UnregisterEntity(b.ID())
+
+	if !h.Ref.IsZero() || len(notified) != 1 || notified[0] != b.ID() {
+		t.Errorf("ref = %s, notified %v, want a null ref and %s notified", h.Ref.ID(), notified, b.ID())
+	}
+}
+
+func TestRestoreRefs(t *testing.T) {
+	world := ecs.New()
+	h := &target{}
+	holder := world.Spawn(h)
+	a, b := world.Spawn(&position{}), world.Spawn(&position{})
+
+	world.SetRef(holder.ID(), &h.Ref, a.ID(), nil)
+	toA := world.Snapshot()
+
+	world.SetRef(holder.ID(), &h.Ref, b.ID(), nil)
+	toB := world.Snapshot()
+
+	// the restored ref to a is tracked again
+	world.Restore(toA)
+	world.UnregisterEntity(a.ID())
+
+	if !h.Ref.IsZero() {
+		t.Fatalf("ref = %s after its target was unregistered, want a null ref", h.Ref.ID())
+	}
+
+	world.Restore(toB)
+	world.UnregisterEntity(b.ID())
+
+	if !h.Ref.IsZero() {
+		t.Fatalf("ref = %s after its target was unregistered, want a null ref", h.Ref.ID())
+	}
+
+	// a snapshot does not bring back a ref to an unregistered entity
+	world.Restore(toA)
+
+	if !h.Ref.IsZero() {
+		t.Errorf("ref = %s after restoring a ref to an unregistered entity, want a null ref", h.Ref.ID())
+	}
+}
+
+func init() {
+	component.Register("ecs_test.target", &target{})
+	component.Register("ecs_test.position", &position{})
+}
+
+func TestSaveLoadRefs(t *testing.T) {
+	world := ecs.New()
+	h := &target{}
+	holder := world.Spawn(h)
+	a := world.Spawn(&position{X: 1})
+	outside := &target{}
+	world.Spawn(outside)
+
+	world.SetRef(holder.ID(), &h.Ref, a.ID(), nil)
+	world.SetRef(holder.ID(), &outside.Ref, a.ID(), nil)
+
+	b := &bytes.Buffer{}
+	if err := world.Save(b); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded := ecs.New()
+
+	entities, err := loaded.Load(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lh := targetType.Get(loaded.EntityComponents(entities[0].ID()))
+	if lh.Ref.ID() != entities[1].ID() {
+		t.Fatalf("loaded ref = %s, want the loaded target %s", lh.Ref.ID(), entities[1].ID())
+	}
+
+	// the loaded ref is tracked
+	loaded.UnregisterEntity(entities[1].ID())
+
+	if !lh.Ref.IsZero() {
+		t.Errorf("loaded ref = %s after its target was unregistered, want a null ref", lh.Ref.ID())
+	}
+}
+
+func TestImportRefsToEntitiesNotImported(t *testing.T) {
+	world := ecs.New()
+	h := &target{}
+	holder := world.Spawn(h)
+	a := world.Spawn(&position{})
+
+	world.SetRef(holder.ID(), &h.Ref, a.ID(), nil)
+
+	text, err := world.ExportEntities(holder.ID())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entities, err := world.ImportEntities(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ref := targetType.Get(world.EntityComponents(entities[0].ID())).Ref; !ref.IsZero() {
+		t.Errorf("imported ref = %s, want a null ref", ref.ID())
+	}
+}
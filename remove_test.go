@@ -0,0 +1,206 @@
+package ecs_test
+
+import (
+	"testing"
+
+	ecs "github.com/jtbonhomme/ebiten-ecs"
+	"github.com/jtbonhomme/ebiten-ecs/component"
+	"github.com/jtbonhomme/ebiten-ecs/entity"
+	"github.com/jtbonhomme/ebiten-ecs/system"
+)
+
+const manyEntities = 1000
+
+type position struct {
+	X, Y float64
+}
+
+// visitor is an updater recording the entities it updates, and calling a function on each of them.
+type visitor struct {
+	system.Base
+	visited []entity.ID
+	visit   func(id entity.ID)
+}
+
+func (v *visitor) Update(id entity.ID, _ []component.Component, _ map[entity.ID][]component.Component) error {
+	v.visited = append(v.visited, id)
+	if v.visit != nil {
+		v.visit(id)
+	}
+
+	return nil
+}
+
+// spawn registers n entities associated with the updater, tagged "enemy" and added to the "level" group.
+func spawn(t *testing.T, world *ecs.ECS, v *visitor, n int) []entity.Entity {
+	t.Helper()
+
+	entities := make([]entity.Entity, n)
+	for i := range entities {
+		entities[i] = entity.New()
+		world.RegisterEntity(entities[i], component.New(&position{X: float64(i)}))
+		world.Tag(entities[i], "enemy")
+		world.Group("level").Add(entities[i])
+	}
+
+	world.RegisterUpdater(v, entities...)
+
+	return entities
+}
+
+// requireEmpty fails the test if an entity is still registered, tagged or grouped.
+func requireEmpty(t *testing.T, world *ecs.ECS, v *visitor) {
+	t.Helper()
+
+	if n := len(world.FilterEntities(v)); n != 0 {
+		t.Errorf("%d entities are still associated with the updater", n)
+	}
+
+	if n := len(world.EntitiesWithTag("enemy")); n != 0 {
+		t.Errorf("%d entities are still tagged", n)
+	}
+
+	if n := world.Group("level").Len(); n != 0 {
+		t.Errorf("%d entities are still in the group", n)
+	}
+}
+
+func TestUnregisterWhileRangingOverSystemEntities(t *testing.T) {
+	world := ecs.New()
+	v := &visitor{}
+	entities := spawn(t, world, v, manyEntities)
+
+	visited := 0
+	for _, e := range world.FilterEntities(v) {
+		if e != entities[visited] {
+			t.Fatalf("visited %s at index %d, expected %s", e.ID(), visited, entities[visited].ID())
+		}
+
+		world.UnregisterEntity(e.ID())
+		visited++
+	}
+
+	if visited != manyEntities {
+		t.Errorf("visited %d entities, expected %d", visited, manyEntities)
+	}
+
+	requireEmpty(t, world, v)
+}
+
+func TestUnregisterWhileRangingOverTaggedEntities(t *testing.T) {
+	world := ecs.New()
+	v := &visitor{}
+	spawn(t, world, v, manyEntities)
+
+	visited := 0
+	for _, e := range world.EntitiesWithTag("enemy") {
+		world.UnregisterEntity(e.ID())
+		visited++
+	}
+
+	if visited != manyEntities {
+		t.Errorf("visited %d entities, expected %d", visited, manyEntities)
+	}
+
+	requireEmpty(t, world, v)
+}
+
+func TestUntagWhileRangingOverTaggedEntities(t *testing.T) {
+	world := ecs.New()
+	v := &visitor{}
+	spawn(t, world, v, manyEntities)
+
+	visited := 0
+	for _, e := range world.EntitiesWithTag("enemy") {
+		world.Untag(e.ID(), "enemy")
+		visited++
+	}
+
+	if visited != manyEntities {
+		t.Errorf("visited %d entities, expected %d", visited, manyEntities)
+	}
+
+	if n := len(world.EntitiesWithTag("enemy")); n != 0 {
+		t.Errorf("%d entities are still tagged", n)
+	}
+}
+
+func TestUnregisterWhileRangingOverGroupEntities(t *testing.T) {
+	world := ecs.New()
+	v := &visitor{}
+	spawn(t, world, v, manyEntities)
+
+	visited := 0
+	for _, e := range world.Group("level").Entities() {
+		world.UnregisterEntity(e.ID())
+		visited++
+	}
+
+	if visited != manyEntities {
+		t.Errorf("visited %d entities, expected %d", visited, manyEntities)
+	}
+
+	requireEmpty(t, world, v)
+}
+
+func TestUnregisterDuringUpdate(t *testing.T) {
+	world := ecs.New(ecs.WithDeterministic(1))
+	v := &visitor{}
+	entities := spawn(t, world, v, manyEntities)
+
+	// each visited entity unregisters itself and the entity after it, which must then be skipped
+	v.visit = func(id entity.ID) {
+		world.UnregisterEntity(id)
+		world.UnregisterEntity(id + 1)
+	}
+
+	err := world.Update()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(v.visited) != manyEntities/2 {
+		t.Fatalf("visited %d entities, expected %d", len(v.visited), manyEntities/2)
+	}
+
+	for i, id := range v.visited {
+		if id != entities[2*i].ID() {
+			t.Fatalf("visited %s at index %d, expected %s", id, i, entities[2*i].ID())
+		}
+	}
+
+	requireEmpty(t, world, v)
+}
+
+func TestKeepOrderWhenUnregisteringManyEntities(t *testing.T) {
+	world := ecs.New()
+	v := &visitor{}
+	entities := spawn(t, world, v, manyEntities)
+
+	for i := 0; i < manyEntities; i += 3 {
+		world.UnregisterEntity(entities[i].ID())
+	}
+
+	var expected []entity.Entity
+	for i, e := range entities {
+		if i%3 != 0 {
+			expected = append(expected, e)
+		}
+	}
+
+	for name, got := range map[string][]entity.Entity{
+		"system": world.FilterEntities(v),
+		"tag":    world.EntitiesWithTag("enemy"),
+		"group":  world.Group("level").Entities(),
+	} {
+		if len(got) != len(expected) {
+			t.Fatalf("%s: %d entities, expected %d", name, len(got), len(expected))
+		}
+
+		for i := range got {
+			if got[i] != expected[i] {
+				t.Fatalf("%s: entity %s at index %d, expected %s", name, got[i].ID(), i, expected[i].ID())
+			}
+		}
+	}
+}
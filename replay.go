@@ -0,0 +1,185 @@
+package ecs
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"os"
+
+	"github.com/jtbonhomme/ebiten-ecs/input"
+)
+
+// Rand returns the random number generator of the world, seeded with the seed given to WithDeterministic,
+// or with the seed of the recording being replayed. Systems MUST use it, or a stream of their own
+// (see RandStream), instead of the global math/rand functions for the simulation to be deterministic.
+// Its state is part of the world snapshots. It is also available as the rand.Rand resource.
+func (ecs *ECS) Rand() *rand.Rand {
+	return ecs.rand
+}
+
+// Seed returns the seed of the random number generator of the world.
+func (ecs *ECS) Seed() int64 {
+	return ecs.seed
+}
+
+// reseed resets the random number generator of the world with the given seed. The generators are reseeded
+// in place, so that the systems which kept them before a replay draw the replayed numbers.
+func (ecs *ECS) reseed(seed int64) {
+	ecs.seed = seed
+	ecs.pcg.Seed(uint64(seed), uint64(seed))
+
+	for name, s := range ecs.streams {
+		s.pcg.Seed(streamSeed(seed, name))
+	}
+}
+
+// Input returns the input of the current step, captured from the input devices or read from a replay.
+// Systems MUST use it instead of polling Ebiten for the simulation to be replayable.
+// It is also available as the input.State resource.
+func (ecs *ECS) Input() *input.State {
+	return ecs.input
+}
+
+// Record starts recording the seed of the world, the current state of its random number generators,
+// and the input of every step to w.
+// It returns an error if the header of the recording cannot be written.
+func (ecs *ECS) Record(w io.Writer) error {
+	state, err := ecs.marshalRand()
+	if err != nil {
+		return err
+	}
+
+	r, err := input.NewStateRecorder(w, ecs.seed, state)
+	if err != nil {
+		return err
+	}
+
+	ecs.recorder = r
+
+	return nil
+}
+
+// RecordFile starts recording to a new file at the given path, see Record. The file is closed by StopRecording.
+func (ecs *ECS) RecordFile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	err = ecs.Record(f)
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	ecs.recordFile = f
+
+	return nil
+}
+
+// StopRecording stops recording and flushes the recording, closing the file opened by RecordFile if any.
+func (ecs *ECS) StopRecording() error {
+	if ecs.recorder == nil {
+		return nil
+	}
+
+	r, f := ecs.recorder, ecs.recordFile
+	ecs.recorder, ecs.recordFile = nil, nil
+
+	err := r.Flush()
+
+	if f != nil {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}
+
+	return err
+}
+
+// Replay starts replaying a recording: the random number generators are reseeded with the recorded seed
+// and restored to their recorded state, and the recorded input is used instead of the input devices, one snapshot per step, until the end of
+// the recording. For the replay to reproduce the recorded simulation, the world MUST be in the same state
+// as when the recording started, and be created with WithDeterministic.
+// It returns an error if the header of the recording cannot be read.
+func (ecs *ECS) Replay(r io.Reader) error {
+	p, err := input.NewPlayer(r)
+	if err != nil {
+		return err
+	}
+
+	ecs.reseed(p.Seed())
+
+	if p.State() != nil {
+		err = ecs.unmarshalRand(p.State())
+		if err != nil {
+			return err
+		}
+	}
+
+	ecs.player = p
+
+	return nil
+}
+
+// ReplayFile starts replaying the recording file at the given path, see Replay.
+// The file is read at once, so that the replay does not depend on the file system while it runs.
+func (ecs *ECS) ReplayFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	return ecs.Replay(bytes.NewReader(data))
+}
+
+// SetInputSource sets the function capturing the input of each step.
+// Without a source, the world sees no input, except the one replayed from a recording.
+func (ecs *ECS) SetInputSource(src input.Source) {
+	ecs.inputSource = src
+}
+
+// Replaying returns true while a recording is being replayed.
+func (ecs *ECS) Replaying() bool {
+	return ecs.player != nil
+}
+
+// nextInput captures or replays the input of the next step, and records it if needed.
+// The replay ends with the last recorded step, so that Replaying returns false once it has run.
+func (ecs *ECS) nextInput() error {
+	replayed := false
+
+	if ecs.player != nil {
+		err := ecs.player.Read(&ecs.snapshot)
+
+		switch {
+		case errors.Is(err, io.EOF):
+			ecs.player = nil
+		case err != nil:
+			return err
+		default:
+			replayed = true
+
+			if !ecs.player.More() {
+				ecs.player = nil
+			}
+		}
+	}
+
+	if !replayed {
+		if ecs.inputSource != nil {
+			ecs.inputSource(&ecs.snapshot)
+		} else {
+			ecs.snapshot.Reset()
+		}
+	}
+
+	ecs.input.Next(&ecs.snapshot)
+
+	if ecs.recorder != nil {
+		return ecs.recorder.Write(&ecs.snapshot)
+	}
+
+	return nil
+}
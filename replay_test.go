@@ -0,0 +1,39 @@
+package ecs_test
+
+import (
+	"bytes"
+	"math/rand/v2"
+	"testing"
+
+	ecs "github.com/jtbonhomme/ebiten-ecs"
+)
+
+func TestReplayReseedsKeptGenerators(t *testing.T) {
+	world := ecs.New(ecs.WithDeterministic(7))
+	recording := &bytes.Buffer{}
+
+	if err := world.Record(recording); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []uint64{world.Rand().Uint64(), world.Rand().Uint64()}
+
+	if err := world.StopRecording(); err != nil {
+		t.Fatal(err)
+	}
+
+	replayed := ecs.New(ecs.WithDeterministic(1))
+	kept, resource := replayed.Rand(), ecs.Resource[rand.Rand](replayed)
+
+	if err := replayed.Replay(recording); err != nil {
+		t.Fatal(err)
+	}
+
+	if replayed.Rand() != kept || ecs.Resource[rand.Rand](replayed) != kept {
+		t.Fatal("Replay replaced the random number generator of the world")
+	}
+
+	if got := []uint64{kept.Uint64(), resource.Uint64()}; got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("replayed numbers = %v, want %v", got, want)
+	}
+}
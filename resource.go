@@ -0,0 +1,35 @@
+package ecs
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// SetResource registers a world-level resource, i.e. data that is not tied to any entity
+// such as the score, the game configuration or the camera.
+// There is at most one resource per type: setting a resource replaces any resource of the same type.
+// The method panics if the resource is not a pointer.
+func (ecs *ECS) SetResource(r interface{}) {
+	resourceValue := reflect.ValueOf(r)
+
+	if resourceValue.Kind() != reflect.Ptr {
+		panic(fmt.Sprintf("the resource %q you are trying to set MUST be a pointer", resourceValue.Type().Name()))
+	}
+
+	ecs.resources[resourceValue.Type()] = r
+}
+
+// Resource returns the resource of type *T registered in the world, or nil if there is none.
+func Resource[T any](ecs *ECS) *T {
+	r, ok := ecs.resources[reflect.TypeOf((*T)(nil))]
+	if !ok {
+		return nil
+	}
+
+	return r.(*T)
+}
+
+// RemoveResource removes the resource of type *T from the world, if any.
+func RemoveResource[T any](ecs *ECS) {
+	delete(ecs.resources, reflect.TypeOf((*T)(nil)))
+}
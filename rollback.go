@@ -0,0 +1,105 @@
+package ecs
+
+import (
+	"fmt"
+
+	"github.com/jtbonhomme/ebiten-ecs/input"
+)
+
+// history is a ring buffer of the snapshots and inputs of the last ticks, used to roll back.
+type history struct {
+	snapshots []Snapshot
+	inputs    []input.Snapshot
+}
+
+// newHistory creates a history of the given number of ticks.
+func newHistory(ticks int) *history {
+	return &history{
+		snapshots: make([]Snapshot, ticks),
+		inputs:    make([]input.Snapshot, ticks),
+	}
+}
+
+// slot returns the index of the ring buffer holding the given tick.
+func (h *history) slot(tick uint64) int {
+	return int(tick % uint64(len(h.snapshots)))
+}
+
+// save saves the state of the world and its input at the beginning of the current tick.
+func (h *history) save(ecs *ECS) {
+	i := h.slot(ecs.tick)
+	ecs.SnapshotInto(&h.snapshots[i])
+	h.inputs[i].CopyFrom(&ecs.input.Current)
+}
+
+// has returns true if the history holds the given tick, which must be in the past.
+func (h *history) has(ecs *ECS, tick uint64) bool {
+	return tick < ecs.tick && ecs.tick-tick <= uint64(len(h.snapshots)) && h.snapshots[h.slot(tick)].tick == tick
+}
+
+// Tick returns the number of steps run since the world was created.
+func (ecs *ECS) Tick() uint64 {
+	return ecs.tick
+}
+
+// SetInputAt replaces the input used by a past tick, e.g. when the actual input of a remote player is received
+// after the tick was simulated with a predicted input. It is taken into account by the next Rollback.
+// It returns an error if rollback is not enabled, or if the tick is not in the history anymore.
+func (ecs *ECS) SetInputAt(tick uint64, s *input.Snapshot) error {
+	if ecs.history == nil {
+		return fmt.Errorf("rollback is not enabled")
+	}
+
+	if !ecs.history.has(ecs, tick) {
+		return fmt.Errorf("tick %d is not in the rollback history", tick)
+	}
+
+	ecs.history.inputs[ecs.history.slot(tick)].CopyFrom(s)
+
+	return nil
+}
+
+// Rollback restores the state of the world at the beginning of a past tick, then simulates again all the ticks
+// up to the current one with the inputs of the history, as replaced by SetInputAt.
+// The entities registered or unregistered in the meantime are not rolled back, see Snapshot.
+// It returns an error if rollback is not enabled, if the tick is not in the history anymore,
+// or if an updater fails during the simulation.
+func (ecs *ECS) Rollback(tick uint64) error {
+	h := ecs.history
+	if h == nil {
+		return fmt.Errorf("rollback is not enabled")
+	}
+
+	if !h.has(ecs, tick) {
+		return fmt.Errorf("tick %d is not in the rollback history", tick)
+	}
+
+	current := ecs.tick
+
+	ecs.Restore(&h.snapshots[h.slot(tick)])
+
+	// the input of the previous tick may have been replaced as well: the events of the first step are computed again
+	previous := &ecs.input.Previous
+	if tick > 0 && h.has(ecs, tick-1) {
+		previous = &h.inputs[h.slot(tick-1)]
+	}
+
+	ecs.input.Set(previous, &h.inputs[h.slot(tick)])
+
+	err := ecs.runUpdaters()
+	if err != nil {
+		return err
+	}
+
+	for ecs.tick < current {
+		ecs.input.Next(&h.inputs[h.slot(ecs.tick)])
+		h.save(ecs)
+
+		err := ecs.runUpdaters()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,92 @@
+package ecs_test
+
+import (
+	"testing"
+
+	ecs "github.com/jtbonhomme/ebiten-ecs"
+	"github.com/jtbonhomme/ebiten-ecs/entity"
+	"github.com/jtbonhomme/ebiten-ecs/input"
+)
+
+func TestRollbackKeepsTime(t *testing.T) {
+	world := ecs.New(ecs.WithDeterministic(1), ecs.WithRollback(16))
+	world.RegisterUpdater(&visitor{}, world.Spawn(&position{}))
+
+	for i := 0; i < 10; i++ {
+		if err := world.Update(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := *world.Time()
+
+	if err := world.Rollback(5); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := *world.Time(); got != want {
+		t.Errorf("time after rollback = %+v, want %+v", got, want)
+	}
+}
+
+func TestRestoreTime(t *testing.T) {
+	world := ecs.New(ecs.WithDeterministic(1))
+
+	if err := world.Update(); err != nil {
+		t.Fatal(err)
+	}
+
+	s := world.Snapshot()
+	want := *world.Time()
+
+	for i := 0; i < 3; i++ {
+		if err := world.Update(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	world.Restore(s)
+
+	if got := *world.Time(); got != want {
+		t.Errorf("time after restore = %+v, want %+v", got, want)
+	}
+}
+
+func TestRollbackComputesInputEvents(t *testing.T) {
+	world := ecs.New(ecs.WithDeterministic(1), ecs.WithRollback(16))
+	pressed := make(map[uint64]bool)
+
+	v := &visitor{}
+	v.visit = func(entity.ID) {
+		pressed[world.Tick()] = world.Input().IsKeyJustPressed(1) && len(world.Input().Events) == 1
+	}
+	world.RegisterUpdater(v, world.Spawn(&position{}))
+
+	for i := 0; i < 6; i++ {
+		if err := world.Update(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the key was actually pressed at the step following tick 3, and kept pressed at the next one
+	err := world.SetInputAt(3, &input.Snapshot{Keys: []input.Key{1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = world.SetInputAt(4, &input.Snapshot{Keys: []input.Key{1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = world.Rollback(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for tick, want := range map[uint64]bool{4: true, 5: false, 6: false} {
+		if pressed[tick] != want {
+			t.Errorf("tick %d: key just pressed = %v, want %v", tick, pressed[tick], want)
+		}
+	}
+}
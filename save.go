@@ -0,0 +1,82 @@
+package ecs
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/jtbonhomme/ebiten-ecs/component"
+	"github.com/jtbonhomme/ebiten-ecs/entity"
+)
+
+// savedWorld is the representation of a save file: the entities of the world,
+// and the versions of the component types they were saved with.
+type savedWorld struct {
+	Versions map[string]int   `json:"versions"`
+	Entities []exportedEntity `json:"entities"`
+}
+
+// Save writes all the entities of the world, with their components, names and tags, as a JSON save file.
+// The current version of each component type (see component.SetVersion) is saved along,
+// so that Load upgrades the components saved by an older version of the game.
+// It returns an error if an entity holds a component of an unregistered type.
+func (ecs *ECS) Save(w io.Writer) error {
+	ids := make([]entity.ID, 0, len(ecs.componentsRegistry))
+	for id := range ecs.componentsRegistry {
+		ids = append(ids, id)
+	}
+
+	slices.Sort(ids)
+
+	exported, err := ecs.exportEntities(ids)
+	if err != nil {
+		return err
+	}
+
+	saved := savedWorld{
+		Versions: make(map[string]int),
+		Entities: exported,
+	}
+
+	for _, ee := range exported {
+		for name := range ee.Components {
+			saved.Versions[name] = component.Version(name)
+		}
+	}
+
+	return json.NewEncoder(w).Encode(saved)
+}
+
+// Load creates and registers new entities from a save file written by Save, after migrating the components
+// saved with an older version of their type (see component.RegisterMigration). Components saved without
+// a version, e.g. by ExportEntities, are considered to be of version 0. Loaded entities get new IDs,
+// their names being restored unless already used by another entity, and the references between them remapped
+// (see ImportEntities). It returns the created entities, or an error if the file is invalid, refers to
+// an unregistered component type, or cannot be migrated, in which case no entity is registered.
+func (ecs *ECS) Load(r io.Reader) ([]entity.Entity, error) {
+	saved := savedWorld{}
+
+	err := json.NewDecoder(r).Decode(&saved)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, ee := range saved.Entities {
+		for name, raw := range ee.Components {
+			migrated, err := component.Migrate(name, saved.Versions[name], raw)
+			if err != nil {
+				return nil, fmt.Errorf("entity #%d: %w", i, err)
+			}
+
+			ee.Components[name] = migrated
+		}
+	}
+
+	components, err := decodeEntities(saved.Entities)
+	if err != nil {
+		return nil, err
+	}
+
+	return ecs.registerImported(saved.Entities, components), nil
+}
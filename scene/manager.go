@@ -0,0 +1,177 @@
+package scene
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Manager holds the registered scenes and swaps them.
+// Only the world of the current scene is updated, and drawn unless a transition is being played.
+type Manager struct {
+	scenes         map[string]Scene
+	current        Scene
+	next           Scene
+	nextTransition Transition
+	running        *running
+	from           *ebiten.Image
+	to             *ebiten.Image
+}
+
+// NewManager creates a new scene manager with no registered scene.
+func NewManager() *Manager {
+	return &Manager{
+		scenes: make(map[string]Scene),
+	}
+}
+
+// Register adds a scene to the manager.
+// The method panics if a scene with the same name is already registered.
+func (m *Manager) Register(s Scene) {
+	if _, ok := m.scenes[s.Name()]; ok {
+		panic(fmt.Sprintf("the scene %q you are trying to register is already registered", s.Name()))
+	}
+
+	m.scenes[s.Name()] = s
+}
+
+// SwitchTo requests a change to the scene registered with the given name.
+// The change is applied at the beginning of the next Update, so it is safe to call it from a system.
+// It returns an error if no scene is registered with this name.
+func (m *Manager) SwitchTo(name string) error {
+	s, ok := m.scenes[name]
+	if !ok {
+		return fmt.Errorf("unknown scene %q", name)
+	}
+
+	m.next = s
+	m.nextTransition = nil
+
+	return nil
+}
+
+// SwitchToWith requests a change to the scene registered with the given name, played with the given transition.
+// The outgoing scene is exited once the transition is over.
+// It returns an error if no scene is registered with this name.
+func (m *Manager) SwitchToWith(name string, t Transition) error {
+	err := m.SwitchTo(name)
+	if err != nil {
+		return err
+	}
+
+	m.nextTransition = t
+
+	return nil
+}
+
+// Current returns the current scene, or nil if no scene has been entered yet.
+func (m *Manager) Current() Scene {
+	return m.current
+}
+
+// exit calls the exit hook of the given scene.
+func exit(s Scene) error {
+	err := s.Exit()
+	if err != nil {
+		return fmt.Errorf("exit scene %q: %w", s.Name(), err)
+	}
+
+	return nil
+}
+
+// finishTransition ends the running transition, if any, and exits the outgoing scene.
+func (m *Manager) finishTransition() error {
+	if m.running == nil {
+		return nil
+	}
+
+	from := m.running.from
+	m.running = nil
+
+	return exit(from)
+}
+
+// swap exits the current scene and enters the requested one.
+// When a transition is requested, the current scene is exited at the end of the transition instead.
+// The request is cleared only once the requested scene is entered, so that a failed swap is retried
+// by the next Update: a current scene which was exited before the failure is not exited again.
+func (m *Manager) swap() error {
+	err := m.finishTransition()
+	if err != nil {
+		return err
+	}
+
+	if m.current != nil && m.nextTransition == nil {
+		err = exit(m.current)
+		if err != nil {
+			return err
+		}
+
+		m.current = nil
+	}
+
+	err = m.next.Enter()
+	if err != nil {
+		return fmt.Errorf("enter scene %q: %w", m.next.Name(), err)
+	}
+
+	if m.current != nil {
+		m.running = &running{
+			transition: m.nextTransition,
+			from:       m.current,
+		}
+	}
+
+	m.current, m.next, m.nextTransition = m.next, nil, nil
+
+	return nil
+}
+
+// Update applies any pending scene change, then updates the world of the current scene.
+func (m *Manager) Update() error {
+	if m.next != nil {
+		err := m.swap()
+		if err != nil {
+			return err
+		}
+	}
+
+	if m.running != nil {
+		m.running.elapsed += time.Second / time.Duration(ebiten.TPS())
+		if m.running.progress() >= 1 {
+			err := m.finishTransition()
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if m.current == nil {
+		return nil
+	}
+
+	return m.current.World().Update()
+}
+
+// Draw draws the world of the current scene.
+// While a transition is played, the outgoing and incoming worlds are drawn to offscreen images
+// which are then blended on screen by the transition.
+func (m *Manager) Draw(screen *ebiten.Image) {
+	if m.current == nil {
+		return
+	}
+
+	if m.running == nil {
+		m.current.World().Draw(screen)
+		return
+	}
+
+	m.from = offscreen(m.from, screen)
+	m.to = offscreen(m.to, screen)
+
+	m.running.from.World().Draw(m.from)
+	m.current.World().Draw(m.to)
+
+	m.running.transition.Draw(screen, m.from, m.to, m.running.progress())
+}
@@ -0,0 +1,218 @@
+package scene_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"github.com/jtbonhomme/ebiten-ecs/ebitenecs"
+	"github.com/jtbonhomme/ebiten-ecs/scene"
+)
+
+// journal records the hooks called on the scenes, and makes the next ones fail on demand.
+type journal struct {
+	calls []string
+	fail  map[string]error
+}
+
+// scene creates a scene recording its hooks in the journal.
+func (j *journal) scene(name string) scene.Scene {
+	hook := func(call string) scene.Hook {
+		return func(*ebitenecs.World) error {
+			call += " " + name
+			if err := j.fail[call]; err != nil {
+				delete(j.fail, call)
+				return err
+			}
+
+			j.calls = append(j.calls, call)
+
+			return nil
+		}
+	}
+
+	return scene.New(name, hook("enter"), hook("exit"))
+}
+
+// manager creates a manager with the title and game scenes, the title being entered.
+func (j *journal) manager(t *testing.T) *scene.Manager {
+	t.Helper()
+
+	m := scene.NewManager()
+	m.Register(j.scene("title"))
+	m.Register(j.scene("game"))
+
+	if err := m.SwitchTo("title"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.Update(); err != nil {
+		t.Fatal(err)
+	}
+
+	return m
+}
+
+// check compares the hooks called so far with the expected ones, and the name of the current scene.
+func (j *journal) check(t *testing.T, m *scene.Manager, current string, calls ...string) {
+	t.Helper()
+
+	if len(j.calls) != len(calls) {
+		t.Fatalf("hooks called: %q, want %q", j.calls, calls)
+	}
+
+	for i := range calls {
+		if j.calls[i] != calls[i] {
+			t.Fatalf("hooks called: %q, want %q", j.calls, calls)
+		}
+	}
+
+	name := ""
+	if m.Current() != nil {
+		name = m.Current().Name()
+	}
+
+	if name != current {
+		t.Errorf("current scene is %q, want %q", name, current)
+	}
+}
+
+func TestSwitchTo(t *testing.T) {
+	j := &journal{}
+	m := j.manager(t)
+	j.check(t, m, "title", "enter title")
+
+	if err := m.SwitchTo("game"); err != nil {
+		t.Fatal(err)
+	}
+
+	// the change is applied by the next Update
+	j.check(t, m, "title", "enter title")
+
+	if err := m.Update(); err != nil {
+		t.Fatal(err)
+	}
+
+	j.check(t, m, "game", "enter title", "exit title", "enter game")
+
+	if err := m.SwitchTo("credits"); err == nil {
+		t.Error("switching to an unknown scene succeeded")
+	}
+}
+
+func TestSwitchToWith(t *testing.T) {
+	j := &journal{}
+	m := j.manager(t)
+
+	// the transition lasts two steps
+	fade := scene.Fade(2 * time.Second / time.Duration(ebiten.TPS()))
+
+	if err := m.SwitchToWith("game", fade); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.Update(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the outgoing scene is exited once the transition is over
+	j.check(t, m, "game", "enter title", "enter game")
+
+	if err := m.Update(); err != nil {
+		t.Fatal(err)
+	}
+
+	j.check(t, m, "game", "enter title", "enter game", "exit title")
+}
+
+func TestSwitchToDuringTransition(t *testing.T) {
+	j := &journal{}
+	m := j.manager(t)
+
+	if err := m.SwitchToWith("game", scene.Fade(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.Update(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the running transition is ended, exiting its outgoing scene
+	if err := m.SwitchTo("title"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.Update(); err != nil {
+		t.Fatal(err)
+	}
+
+	j.check(t, m, "title", "enter title", "enter game", "exit title", "exit game", "enter title")
+}
+
+func TestFailedExitIsRetried(t *testing.T) {
+	j := &journal{fail: map[string]error{"exit title": errors.New("cannot save")}}
+	m := j.manager(t)
+
+	if err := m.SwitchTo("game"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.Update(); err == nil {
+		t.Fatal("Update succeeded although the current scene failed to exit")
+	}
+
+	// the request is kept and the current scene is unchanged
+	j.check(t, m, "title", "enter title")
+
+	if err := m.Update(); err != nil {
+		t.Fatal(err)
+	}
+
+	j.check(t, m, "game", "enter title", "exit title", "enter game")
+}
+
+func TestFailedEnterIsRetried(t *testing.T) {
+	j := &journal{fail: map[string]error{"enter game": errors.New("cannot load")}}
+	m := j.manager(t)
+
+	if err := m.SwitchTo("game"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.Update(); err == nil {
+		t.Fatal("Update succeeded although the requested scene failed to enter")
+	}
+
+	// the title was exited, the game is still requested
+	j.check(t, m, "", "enter title", "exit title")
+
+	if err := m.Update(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the title is not exited twice
+	j.check(t, m, "game", "enter title", "exit title", "enter game")
+}
+
+func TestFailedEnterWithTransitionKeepsCurrentScene(t *testing.T) {
+	j := &journal{fail: map[string]error{"enter game": errors.New("cannot load")}}
+	m := j.manager(t)
+
+	if err := m.SwitchToWith("game", scene.Fade(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.Update(); err == nil {
+		t.Fatal("Update succeeded although the requested scene failed to enter")
+	}
+
+	j.check(t, m, "title", "enter title")
+
+	if err := m.Update(); err != nil {
+		t.Fatal(err)
+	}
+
+	j.check(t, m, "game", "enter title", "enter game")
+}
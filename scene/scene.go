@@ -0,0 +1,67 @@
+// Package scene provides a way to split a game into isolated scenes (title screen, gameplay, game over...),
+// each one owning its own ECS world.
+package scene
+
+import (
+	"github.com/jtbonhomme/ebiten-ecs/ebitenecs"
+)
+
+// Scene is an interface that represents a scene of the game.
+// A scene owns an ECS world and is notified when it becomes the current scene (Enter)
+// and when it is replaced by another one (Exit).
+type Scene interface {
+	Name() string
+	World() *ebitenecs.World
+	Enter() error
+	Exit() error
+}
+
+// Hook is a function called when a scene is entered or exited.
+// It receives the world owned by the scene, so it can register or unregister entities and systems.
+type Hook func(world *ebitenecs.World) error
+
+type scene struct {
+	name  string
+	world *ebitenecs.World
+	enter Hook
+	exit  Hook
+}
+
+// New creates a new scene with the given name and a new ECS world.
+// The enter and exit hooks are optional and can be nil.
+func New(name string, enter, exit Hook) Scene {
+	return &scene{
+		name:  name,
+		world: ebitenecs.New(),
+		enter: enter,
+		exit:  exit,
+	}
+}
+
+// Name returns the name of the scene.
+func (s *scene) Name() string {
+	return s.name
+}
+
+// World returns the ECS world owned by the scene.
+func (s *scene) World() *ebitenecs.World {
+	return s.world
+}
+
+// Enter calls the enter hook of the scene, if any.
+func (s *scene) Enter() error {
+	if s.enter == nil {
+		return nil
+	}
+
+	return s.enter(s.world)
+}
+
+// Exit calls the exit hook of the scene, if any.
+func (s *scene) Exit() error {
+	if s.exit == nil {
+		return nil
+	}
+
+	return s.exit(s.world)
+}
@@ -0,0 +1,126 @@
+package scene
+
+import (
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Transition is an interface that represents a visual effect played when switching scenes.
+// The outgoing and incoming scenes are rendered to offscreen images which are given to Draw,
+// along with the progress of the transition, from 0 to 1.
+type Transition interface {
+	Duration() time.Duration
+	Draw(screen, from, to *ebiten.Image, progress float64)
+}
+
+// TransitionFunc is a function drawing a transition step.
+type TransitionFunc func(screen, from, to *ebiten.Image, progress float64)
+
+type transition struct {
+	duration time.Duration
+	draw     TransitionFunc
+}
+
+// NewTransition creates a custom transition of the given duration, drawn by the given function.
+func NewTransition(duration time.Duration, draw TransitionFunc) Transition {
+	return &transition{
+		duration: duration,
+		draw:     draw,
+	}
+}
+
+// Duration returns the duration of the transition.
+func (t *transition) Duration() time.Duration {
+	return t.duration
+}
+
+// Draw draws a step of the transition.
+func (t *transition) Draw(screen, from, to *ebiten.Image, progress float64) {
+	t.draw(screen, from, to, progress)
+}
+
+// Fade creates a transition that cross-fades the outgoing scene into the incoming one.
+func Fade(duration time.Duration) Transition {
+	return NewTransition(duration, func(screen, from, to *ebiten.Image, progress float64) {
+		screen.DrawImage(from, nil)
+
+		op := &ebiten.DrawImageOptions{}
+		op.ColorScale.ScaleAlpha(float32(progress))
+		screen.DrawImage(to, op)
+	})
+}
+
+// Direction is the direction in which a slide transition moves.
+type Direction int
+
+const (
+	Left Direction = iota
+	Right
+	Up
+	Down
+)
+
+// Slide creates a transition where the incoming scene pushes the outgoing one out of the screen
+// in the given direction.
+func Slide(duration time.Duration, direction Direction) Transition {
+	return NewTransition(duration, func(screen, from, to *ebiten.Image, progress float64) {
+		w, h := float64(screen.Bounds().Dx()), float64(screen.Bounds().Dy())
+
+		var dx, dy float64
+		switch direction {
+		case Left:
+			dx = -w
+		case Right:
+			dx = w
+		case Up:
+			dy = -h
+		case Down:
+			dy = h
+		}
+
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Translate(dx*progress, dy*progress)
+		screen.DrawImage(from, op)
+
+		op = &ebiten.DrawImageOptions{}
+		op.GeoM.Translate(dx*(progress-1), dy*(progress-1))
+		screen.DrawImage(to, op)
+	})
+}
+
+// running holds the state of the transition being played by the manager.
+type running struct {
+	transition Transition
+	from       Scene
+	elapsed    time.Duration
+}
+
+// progress returns the progress of the transition, from 0 to 1.
+func (r *running) progress() float64 {
+	if r.transition.Duration() <= 0 {
+		return 1
+	}
+
+	p := float64(r.elapsed) / float64(r.transition.Duration())
+	if p > 1 {
+		return 1
+	}
+
+	return p
+}
+
+// offscreen returns img if it matches the size of screen, or a new image of the right size.
+func offscreen(img, screen *ebiten.Image) *ebiten.Image {
+	w, h := screen.Bounds().Dx(), screen.Bounds().Dy()
+	if img != nil && img.Bounds().Dx() == w && img.Bounds().Dy() == h {
+		img.Clear()
+		return img
+	}
+
+	if img != nil {
+		img.Deallocate()
+	}
+
+	return ebiten.NewImage(w, h)
+}
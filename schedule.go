@@ -0,0 +1,88 @@
+package ecs
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jtbonhomme/ebiten-ecs/system"
+)
+
+// SortUpdaters orders the registered updaters according to the constraints they declare by implementing
+// system.Predecessor and system.Successor. Updaters without constraints between them keep their
+// registration order. Constraints on systems which are not registered as updaters are ignored.
+// It returns an error, and leaves the order unchanged, if the constraints contain a cycle.
+// It is called by Update whenever an updater has been registered since the last sort,
+// but can be called right after registering the systems to detect cycles early.
+func (ecs *ECS) SortUpdaters() error {
+	n := len(ecs.updaters)
+	index := make(map[system.ID]int, n)
+
+	for i, s := range ecs.updaters {
+		index[s.ID()] = i
+	}
+
+	successors := make([][]int, n)
+	predecessors := make([]int, n)
+
+	addEdge := func(from, to int) {
+		successors[from] = append(successors[from], to)
+		predecessors[to]++
+	}
+
+	for i, s := range ecs.updaters {
+		if p, ok := unwrap(s).(system.Predecessor); ok {
+			for _, id := range p.RunBefore() {
+				if j, ok := index[id]; ok {
+					addEdge(i, j)
+				}
+			}
+		}
+
+		if p, ok := unwrap(s).(system.Successor); ok {
+			for _, id := range p.RunAfter() {
+				if j, ok := index[id]; ok {
+					addEdge(j, i)
+				}
+			}
+		}
+	}
+
+	// Kahn's algorithm, always picking the ready updater registered first
+	sorted := make([]system.Updater, 0, n)
+	done := make([]bool, n)
+
+	for len(sorted) < n {
+		next := -1
+
+		for i := 0; i < n; i++ {
+			if !done[i] && predecessors[i] == 0 {
+				next = i
+				break
+			}
+		}
+
+		if next < 0 {
+			cycle := []string{}
+
+			for i := 0; i < n; i++ {
+				if !done[i] {
+					cycle = append(cycle, ecs.updaters[i].ID().String())
+				}
+			}
+
+			return fmt.Errorf("cycle in updaters ordering constraints between systems %s", strings.Join(cycle, ", "))
+		}
+
+		done[next] = true
+		sorted = append(sorted, ecs.updaters[next])
+
+		for _, j := range successors[next] {
+			predecessors[j]--
+		}
+	}
+
+	ecs.updaters = sorted
+	ecs.unsorted = false
+
+	return nil
+}
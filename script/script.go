@@ -0,0 +1,175 @@
+// Package script provides the binding surface between the world and an embedded scripting language (Lua, JavaScript...):
+// a Host exposing spawning and despawning, component get and set, queries and events with plain values only
+// (strings, numbers, booleans, maps and slices), which any script engine can convert to and from its own values.
+// Components are referred to by the names they are registered with, see component.Register.
+package script
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	ecs "github.com/jtbonhomme/ebiten-ecs"
+	"github.com/jtbonhomme/ebiten-ecs/component"
+	"github.com/jtbonhomme/ebiten-ecs/entity"
+)
+
+// Handler is a function handling an event emitted by a script or by the game.
+type Handler func(payload map[string]interface{})
+
+// Host exposes the world to scripts. Each of its methods is meant to be bound to a function of the script engine,
+// e.g. with gopher-lua or goja, so that designers can write behaviors and level scripts without recompiling the game.
+type Host struct {
+	world    *ecs.ECS
+	handlers map[string][]Handler
+}
+
+// NewHost creates the script host of the world.
+func NewHost(world *ecs.ECS) *Host {
+	return &Host{
+		world:    world,
+		handlers: make(map[string][]Handler),
+	}
+}
+
+// Spawn creates an entity with the given components, by component name, and returns its ID.
+// The fields of each component are set from the given values, the other fields keeping their zero value.
+// It returns an error, and creates no entity, if a component name is unknown or a value does not fit its field.
+func (h *Host) Spawn(components map[string]map[string]interface{}) (entity.ID, error) {
+	names := make([]string, 0, len(components))
+	for name := range components {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	data := make([]interface{}, 0, len(names))
+
+	for _, name := range names {
+		d, err := component.NewData(name)
+		if err != nil {
+			return 0, err
+		}
+
+		err = assign(d, components[name])
+		if err != nil {
+			return 0, fmt.Errorf("component %q: %w", name, err)
+		}
+
+		data = append(data, d)
+	}
+
+	return h.world.Spawn(data...).ID(), nil
+}
+
+// Despawn unregisters an entity at the beginning of the next update, so that scripts run by systems
+// do not change the entities of the world while it iterates over them.
+func (h *Host) Despawn(id entity.ID) {
+	h.world.Defer(func(world *ecs.ECS) {
+		world.UnregisterEntity(id)
+	})
+}
+
+// Get returns the fields of the component of an entity with the given name.
+// It returns an error if the entity has no such component.
+func (h *Host) Get(id entity.ID, name string) (map[string]interface{}, error) {
+	d, err := h.find(id, name)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := json.Marshal(d)
+	if err != nil {
+		return nil, fmt.Errorf("component %q: %w", name, err)
+	}
+
+	fields := make(map[string]interface{})
+
+	err = json.Unmarshal(raw, &fields)
+	if err != nil {
+		return nil, fmt.Errorf("component %q: %w", name, err)
+	}
+
+	return fields, nil
+}
+
+// Set sets the given fields of the component of an entity with the given name, leaving the other fields unchanged.
+// It returns an error if the entity has no such component, or if a value does not fit its field.
+func (h *Host) Set(id entity.ID, name string, fields map[string]interface{}) error {
+	d, err := h.find(id, name)
+	if err != nil {
+		return err
+	}
+
+	err = assign(d, fields)
+	if err != nil {
+		return fmt.Errorf("component %q: %w", name, err)
+	}
+
+	return nil
+}
+
+// Query returns the IDs of the entities having all the components with the given names, by increasing ID.
+// It returns an error if a component name is unknown.
+func (h *Host) Query(names ...string) ([]entity.ID, error) {
+	types := make([]component.TypeID, 0, len(names))
+
+	for _, name := range names {
+		d, err := component.NewData(name)
+		if err != nil {
+			return nil, err
+		}
+
+		types = append(types, component.TypeIDOf(d))
+	}
+
+	ids := []entity.ID{}
+
+	h.world.ForEachWith(func(id entity.ID, _ []component.Component) {
+		if h.world.IsActive(id) {
+			ids = append(ids, id)
+		}
+	}, types...)
+
+	return ids, nil
+}
+
+// On adds a handler of the events with the given name, emitted by scripts or by the game.
+func (h *Host) On(event string, handler Handler) {
+	h.handlers[event] = append(h.handlers[event], handler)
+}
+
+// Emit calls the handlers of the event with the given name, in the order they were added.
+func (h *Host) Emit(event string, payload map[string]interface{}) {
+	for _, handler := range h.handlers[event] {
+		handler(payload)
+	}
+}
+
+// find returns the data of the component of an entity with the given name.
+func (h *Host) find(id entity.ID, name string) (interface{}, error) {
+	d, err := component.NewData(name)
+	if err != nil {
+		return nil, err
+	}
+
+	t := component.TypeIDOf(d)
+
+	for _, c := range h.world.EntityComponents(id) {
+		if c.TypeID() == t {
+			return c.Data(), nil
+		}
+	}
+
+	return nil, fmt.Errorf("entity %s has no component %q", id, name)
+}
+
+// assign sets fields of the component data from script values, through their JSON representation.
+func assign(d interface{}, fields map[string]interface{}) error {
+	raw, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(raw, d)
+}
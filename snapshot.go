@@ -0,0 +1,147 @@
+package ecs
+
+import (
+	"math/rand/v2"
+	"reflect"
+	"time"
+
+	"github.com/jtbonhomme/ebiten-ecs/component"
+	"github.com/jtbonhomme/ebiten-ecs/entity"
+	"github.com/jtbonhomme/ebiten-ecs/input"
+)
+
+// Snapshot is a copy of the state of the world at a given tick: the values of the components,
+// the state of the random number generators, the simulated time and the input.
+// Components are copied by value: slices, maps and pointers held by components are shared with the world.
+// The set of entities and the components they hold are not part of the snapshot: restoring a snapshot
+// restores the values of the components of the entities which still exist.
+//
+// Taking a snapshot into a reused Snapshot only copies the components of the types tracked with TrackChanges
+// if they changed since it was last taken, so that saving the state every tick (see WithRollback) only copies
+// what changed: track the types of the large components, and report all their mutations with MarkChanged.
+type Snapshot struct {
+	tick       uint64
+	pcg        rand.PCG
+	streams    map[string]rand.PCG
+	time       Time
+	elapsed    time.Duration
+	input      input.State
+	components map[entity.ID][]reflect.Value
+	version    uint64
+	taken      bool
+}
+
+// Tick returns the tick at which the snapshot was taken.
+func (s *Snapshot) Tick() uint64 {
+	return s.tick
+}
+
+// Snapshot returns a snapshot of the current state of the world.
+func (ecs *ECS) Snapshot() *Snapshot {
+	s := &Snapshot{}
+	ecs.SnapshotInto(s)
+
+	return s
+}
+
+// SnapshotInto takes a snapshot of the current state of the world into s, reusing its memory
+// so that taking a snapshot every tick does not allocate once the world is stable.
+func (ecs *ECS) SnapshotInto(s *Snapshot) {
+	s.tick = ecs.tick
+	s.pcg = *ecs.pcg
+	s.input.CopyFrom(ecs.input)
+	s.time = *ecs.time
+	s.elapsed = ecs.elapsed
+
+	if s.streams == nil {
+		s.streams = make(map[string]rand.PCG, len(ecs.streams))
+	}
+
+	clear(s.streams)
+
+	for name, stream := range ecs.streams {
+		s.streams[name] = *stream.pcg
+	}
+
+	if s.components == nil {
+		s.components = make(map[entity.ID][]reflect.Value, len(ecs.componentsRegistry))
+	}
+
+	for id := range s.components {
+		if _, ok := ecs.componentsRegistry[id]; !ok {
+			delete(s.components, id)
+		}
+	}
+
+	for id, components := range ecs.componentsRegistry {
+		values, ok := s.components[id]
+		if len(values) != len(components) {
+			values = make([]reflect.Value, len(components))
+			ok = false
+		}
+
+		for i, c := range components {
+			v := reflect.ValueOf(c.Data()).Elem()
+			if !values[i].IsValid() || values[i].Type() != v.Type() {
+				values[i] = reflect.New(v.Type()).Elem()
+			} else if ok && s.unchanged(ecs, id, c.TypeID()) {
+				continue
+			}
+
+			values[i].Set(v)
+		}
+
+		s.components[id] = values
+	}
+
+	s.version, s.taken = ecs.changeVersion, true
+}
+
+// unchanged returns true if the component of the given type of an entity is tracked,
+// and did not change since the snapshot was last taken.
+func (s *Snapshot) unchanged(ecs *ECS, id entity.ID, t component.TypeID) bool {
+	versions, ok := ecs.changes[t]
+	return ok && s.taken && versions[id] <= s.version
+}
+
+// Restore restores the state of the world saved in a snapshot.
+// Entities registered after the snapshot was taken keep their current values, and entities
+// unregistered since then are not registered again: the references to them (see SetRef) are nulled.
+func (ecs *ECS) Restore(s *Snapshot) {
+	ecs.tick = s.tick
+	*ecs.pcg = s.pcg
+	ecs.input.CopyFrom(&s.input)
+	ecs.elapsed = s.elapsed
+
+	// the speed of the simulation relative to real time is not part of the simulated state
+	speed := ecs.time.Speed
+	*ecs.time = s.time
+	ecs.time.Speed = speed
+
+	for name, stream := range ecs.streams {
+		if pcg, ok := s.streams[name]; ok {
+			*stream.pcg = pcg
+		} else {
+			// created after the snapshot was taken
+			stream.pcg.Seed(streamSeed(ecs.seed, name))
+		}
+	}
+
+	for id, values := range s.components {
+		components, ok := ecs.componentsRegistry[id]
+		if !ok || len(components) != len(values) {
+			continue
+		}
+
+		for i, c := range components {
+			v := reflect.ValueOf(c.Data()).Elem()
+			if v.Type() == values[i].Type() {
+				v.Set(values[i])
+				// the restored value differs from the one saved in more recent snapshots
+				ecs.MarkChanged(id, c.TypeID())
+			}
+		}
+	}
+
+	ecs.retrackRefs()
+}
@@ -0,0 +1,241 @@
+// Package spawn provides a Spawner component and the system instantiating prefabs over time, wave after wave,
+// in an area of the world, and publishing WaveStarted and WaveCleared events.
+package spawn
+
+import (
+	ecs "github.com/jtbonhomme/ebiten-ecs"
+	"github.com/jtbonhomme/ebiten-ecs/component"
+	"github.com/jtbonhomme/ebiten-ecs/entity"
+	"github.com/jtbonhomme/ebiten-ecs/health"
+	"github.com/jtbonhomme/ebiten-ecs/system"
+)
+
+// Wave is a wave of entities spawned by a spawner.
+type Wave struct {
+	// Count is the number of entities spawned by the wave.
+	Count int
+	// Interval is the number of steps between two spawns of the wave, 0 spawning the whole wave at once.
+	Interval int
+	// Delay is the number of steps between the end of the previous wave, or the creation of the spawner,
+	// and the start of the wave.
+	Delay int
+}
+
+// Area is a box of the world the entities are spawned in, at a random position.
+// An area with no width and no height spawns every entity at the same position.
+type Area struct {
+	X      float64
+	Y      float64
+	Width  float64
+	Height float64
+}
+
+// PlaceFunc places a spawned entity at a position of the world.
+type PlaceFunc func(c []component.Component, x, y float64)
+
+// Spawner is the component of the entities spawning waves of entities. The entities are created from Prefab
+// and associated with Systems, or acquired from Pool if it is not nil. A wave is cleared when all its entities
+// are unregistered, inactive (e.g. released to their pool) or dead.
+type Spawner struct {
+	Prefab  ecs.Prefab
+	Pool    *ecs.Pool
+	Systems []system.System
+	Area    Area
+	Waves   []Wave
+	// Loop starts the waves over once the last one is cleared.
+	Loop bool
+	// Place places the spawned entities, DefaultPlace if nil, e.g. to place them by another component.
+	Place PlaceFunc
+
+	wave    int
+	timer   int
+	spawned int
+	alive   []entity.ID
+	started bool
+	done    bool
+}
+
+// Wave returns the index of the current wave.
+func (s *Spawner) Wave() int {
+	return s.wave
+}
+
+// Done returns true once the last wave is cleared, for spawners which do not loop.
+func (s *Spawner) Done() bool {
+	return s.done
+}
+
+// Alive returns the number of entities of the current wave which are still alive, as of the last step.
+func (s *Spawner) Alive() int {
+	return len(s.alive)
+}
+
+// WaveStarted is the event published when a spawner starts a wave.
+type WaveStarted struct {
+	Spawner entity.ID
+	Wave    int
+}
+
+// WaveCleared is the event published when all the entities of a wave are gone.
+type WaveCleared struct {
+	Spawner entity.ID
+	Wave    int
+}
+
+// Events is the world resource holding the events published by the system during the last step.
+type Events struct {
+	WaveStarted []WaveStarted
+	WaveCleared []WaveCleared
+}
+
+var (
+	spawnerType = component.NewType[Spawner]()
+	healthType  = component.NewType[health.Health]()
+)
+
+// Placer is implemented by the components holding the position of an entity, such as physics.RigidBody
+// or sprite.Sprite, so that the spawner places entities without depending on their packages.
+type Placer interface {
+	Place(x, y float64)
+}
+
+// DefaultPlace places every component of an entity implementing Placer.
+func DefaultPlace(c []component.Component, x, y float64) {
+	for _, comp := range c {
+		if p, ok := comp.Data().(Placer); ok {
+			p.Place(x, y)
+		}
+	}
+}
+
+// System is the updater of the spawners. Positions are drawn from the random number generator of the world,
+// so that the waves are replayed identically.
+type System struct {
+	id     system.ID
+	world  *ecs.ECS
+	events *Events
+}
+
+// NewSystem creates the updater of the spawners, and adds the Events resource to the world.
+func NewSystem(world *ecs.ECS) *System {
+	events := &Events{}
+	world.SetResource(events)
+
+	return &System{
+		id:     system.AssignID(),
+		world:  world,
+		events: events,
+	}
+}
+
+// ID returns the unique ID of the system.
+func (s *System) ID() system.ID {
+	return s.id
+}
+
+// BeginStep clears the events of the previous step.
+func (s *System) BeginStep() error {
+	s.events.WaveStarted = s.events.WaveStarted[:0]
+	s.events.WaveCleared = s.events.WaveCleared[:0]
+
+	return nil
+}
+
+// Update advances the waves of the spawner of the entity.
+func (s *System) Update(id entity.ID, c []component.Component, r map[entity.ID][]component.Component) error {
+	sp := spawnerType.Get(c)
+	if sp == nil || sp.done || len(sp.Waves) == 0 {
+		return nil
+	}
+
+	s.prune(sp, r)
+
+	w := sp.Waves[sp.wave]
+
+	if !sp.started {
+		if sp.timer < w.Delay {
+			sp.timer++
+			return nil
+		}
+
+		sp.started, sp.timer, sp.spawned = true, 0, 0
+		s.events.WaveStarted = append(s.events.WaveStarted, WaveStarted{Spawner: id, Wave: sp.wave})
+	}
+
+	for sp.spawned < w.Count && sp.timer <= 0 {
+		s.spawn(sp)
+		sp.spawned++
+		sp.timer = w.Interval
+	}
+
+	if sp.spawned < w.Count {
+		sp.timer--
+		return nil
+	}
+
+	if len(sp.alive) > 0 {
+		return nil
+	}
+
+	s.events.WaveCleared = append(s.events.WaveCleared, WaveCleared{Spawner: id, Wave: sp.wave})
+	sp.started, sp.timer = false, 0
+	sp.wave++
+
+	if sp.wave == len(sp.Waves) {
+		sp.wave = 0
+		sp.done = !sp.Loop
+	}
+
+	return nil
+}
+
+// prune forgets the entities of the current wave which are gone.
+func (s *System) prune(sp *Spawner, r map[entity.ID][]component.Component) {
+	alive := sp.alive[:0]
+
+	for _, id := range sp.alive {
+		c, ok := r[id]
+		if !ok || !s.world.IsActive(id) {
+			continue
+		}
+
+		if h := healthType.Get(c); h != nil && h.Dead() {
+			continue
+		}
+
+		alive = append(alive, id)
+	}
+
+	sp.alive = alive
+}
+
+// spawn creates or acquires an entity of the current wave, and places it in the area of the spawner.
+func (s *System) spawn(sp *Spawner) {
+	var e entity.Entity
+
+	switch {
+	case sp.Pool != nil:
+		e = sp.Pool.Acquire()
+	case sp.Prefab != nil:
+		e = entity.New()
+		s.world.RegisterComponents(e, sp.Prefab()...)
+
+		for _, sys := range sp.Systems {
+			s.world.Associate(sys, e)
+		}
+	default:
+		return
+	}
+
+	rnd := s.world.Rand()
+	x := sp.Area.X + rnd.Float64()*sp.Area.Width
+	y := sp.Area.Y + rnd.Float64()*sp.Area.Height
+
+	place := sp.Place
+	if place == nil {
+		place = DefaultPlace
+	}
+
+	place(s.world.EntityComponents(e.ID()), x, y)
+	sp.alive = append(sp.alive, e.ID())
+}
@@ -0,0 +1,39 @@
+package spawn_test
+
+import (
+	"testing"
+
+	"github.com/jtbonhomme/ebiten-ecs/component"
+	"github.com/jtbonhomme/ebiten-ecs/ecstest"
+	"github.com/jtbonhomme/ebiten-ecs/physics"
+	"github.com/jtbonhomme/ebiten-ecs/spawn"
+)
+
+func TestSpawnPlacesEntities(t *testing.T) {
+	world := ecstest.NewWorld(t)
+
+	bodies := []*physics.RigidBody{}
+	sp := &spawn.Spawner{
+		Prefab: func() []component.Component {
+			b := &physics.RigidBody{}
+			bodies = append(bodies, b)
+
+			return component.NewSlice(b)
+		},
+		Area:  spawn.Area{X: 10, Y: 20},
+		Waves: []spawn.Wave{{Count: 2}},
+	}
+
+	world.RegisterUpdater(spawn.NewSystem(world), world.Spawn(sp))
+	ecstest.AdvanceTicks(t, world, 1)
+
+	if len(bodies) != 2 {
+		t.Fatalf("%d entities were spawned, want 2", len(bodies))
+	}
+
+	for i, b := range bodies {
+		if b.X != 10 || b.Y != 20 {
+			t.Errorf("entity %d placed at %v,%v, want 10,20", i, b.X, b.Y)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package ecs
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// SetTimeScale sets the speed of the simulation relative to real time: 2 runs it twice as fast, 0.5 in slow motion.
+// With a fixed timestep, the scale applies to the time accumulated between two calls to Update, so that
+// the updaters run more or fewer fixed steps of the same duration, keeping the simulation stable.
+// Without a fixed timestep, it applies to the duration of the steps, and so to the Time resource.
+// A deterministic world does not depend on real time and ignores the scale: use SetFastForward instead.
+// Unlike Time.Scale, which shortens the deltas read by the systems, it also speeds up the systems ignoring them.
+// The function panics if the scale is negative.
+func (ecs *ECS) SetTimeScale(scale float64) {
+	if scale < 0 {
+		panic(fmt.Sprintf("the time scale %v MUST NOT be negative", scale))
+	}
+
+	ecs.timeScale = scale
+	ecs.time.Speed = scale
+}
+
+// TimeScale returns the speed of the simulation relative to real time, 1 by default.
+func (ecs *ECS) TimeScale() float64 {
+	return ecs.timeScale
+}
+
+// SetFastForward makes each call to Update run n times the steps it would run otherwise, e.g. to skip
+// through a long-running simulation while debugging, or for an x2 speed button. n = 1 turns fast-forward off.
+// Steps requested with Step while paused are not repeated.
+// The function panics if n is lower than 1.
+func (ecs *ECS) SetFastForward(n int) {
+	if n < 1 {
+		panic(fmt.Sprintf("the fast-forward factor %d MUST be at least 1", n))
+	}
+
+	ecs.fastForward = n
+}
+
+// FastForward returns the number of times Update runs the steps it would run otherwise, 1 by default.
+func (ecs *ECS) FastForward() int {
+	return ecs.fastForward
+}
+
+// scaled returns a real duration scaled by the time scale.
+func (ecs *ECS) scaled(d time.Duration) time.Duration {
+	return time.Duration(float64(d) * ecs.timeScale)
+}
+
+// maxFixedSteps returns the maximum number of fixed steps run by a call to Update, which grows with
+// the time scale so that a sped up simulation still catches up with real time.
+func (ecs *ECS) maxFixedSteps() int {
+	return int(math.Ceil(float64(MaxFixedSteps) * max(ecs.timeScale, 1)))
+}
+
+// fastStep runs a step, repeated when fast-forwarding. Repeated steps last as long as the first one.
+func (ecs *ECS) fastStep() error {
+	defer func() {
+		ecs.repeating = false
+	}()
+
+	for i := 0; i < ecs.fastForward; i++ {
+		ecs.repeating = i > 0
+
+		err := ecs.step()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,181 @@
+// Package sprite provides a Sprite component and a batched drawer, drawing all the sprites cut from the same
+// texture atlas with a single DrawTriangles call instead of one DrawImage call per sprite.
+package sprite
+
+import (
+	"image"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"github.com/jtbonhomme/ebiten-ecs/component"
+	"github.com/jtbonhomme/ebiten-ecs/system"
+)
+
+// Sprite is the component of the entities drawn as a region of a texture atlas.
+type Sprite struct {
+	Atlas *ebiten.Image
+	// Source is the region of the atlas drawn.
+	Source image.Rectangle
+	// X and Y are the position of the origin of the sprite.
+	X float64
+	Y float64
+	// OriginX and OriginY are the point of the sprite, relative to its top left corner, which is drawn
+	// at its position and which it rotates and scales around.
+	OriginX  float64
+	OriginY  float64
+	ScaleX   float64
+	ScaleY   float64
+	Rotation float64
+	// Color is the color scale applied to the sprite, white (no change) if it is zero.
+	Color ebiten.ColorScale
+}
+
+// Place moves the sprite to a position of the screen or of the world, e.g. when it is spawned (see spawn.Placer).
+func (s *Sprite) Place(x, y float64) {
+	s.X, s.Y = x, y
+}
+
+// spriteType is the handle used to find the Sprite component.
+var spriteType = component.NewType[Sprite]()
+
+// maxQuads is the number of sprites a single DrawTriangles call can draw with 16-bit indices.
+const maxQuads = (math.MaxUint16 + 1) / 4
+
+// batch holds the vertices of the sprites sharing an atlas.
+type batch struct {
+	atlas    *ebiten.Image
+	vertices []ebiten.Vertex
+	indices  []uint16
+}
+
+// Drawer is a batched drawer drawing the Sprite component of its entities. The sprites sharing an atlas are drawn
+// in the order of the entities, atlas by atlas in the order in which the atlases are first met.
+// It can be registered either in the screen pass, or in the world pass to be drawn from the point of view of the camera.
+type Drawer struct {
+	id      system.ID
+	batches []*batch
+	used    int
+	geoM    ebiten.GeoM
+	op      ebiten.DrawTrianglesOptions
+}
+
+// NewDrawer creates a batched drawer of sprites.
+func NewDrawer() *Drawer {
+	return &Drawer{
+		id: system.AssignID(),
+	}
+}
+
+// ID returns the unique ID of the drawer.
+func (d *Drawer) ID() system.ID {
+	return d.id
+}
+
+// Draw adds the sprite of the entity to the batch of its atlas, drawn on Flush.
+func (d *Drawer) Draw(screen *ebiten.Image, c []component.Component) {
+	d.geoM.Reset()
+	d.add(screen, c)
+}
+
+// DrawWorld adds the sprite of the entity to the batch of its atlas, transformed by the camera, drawn on Flush.
+func (d *Drawer) DrawWorld(screen *ebiten.Image, camera ebiten.GeoM, c []component.Component) {
+	d.geoM = camera
+	d.add(screen, c)
+}
+
+// add adds the sprite of the entity to the batch of its atlas, drawing the batch first if it is full.
+func (d *Drawer) add(screen *ebiten.Image, c []component.Component) {
+	s := spriteType.Get(c)
+	if s == nil || s.Atlas == nil {
+		return
+	}
+
+	b := d.batch(s.Atlas)
+	if len(b.vertices)/4 == maxQuads {
+		d.draw(screen, b)
+	}
+
+	var g ebiten.GeoM
+
+	g.Translate(-s.OriginX, -s.OriginY)
+	g.Scale(scale(s.ScaleX), scale(s.ScaleY))
+	g.Rotate(s.Rotation)
+	g.Translate(s.X, s.Y)
+	g.Concat(d.geoM)
+
+	r, gr, bl, a := s.Color.R(), s.Color.G(), s.Color.B(), s.Color.A()
+	w, h := float64(s.Source.Dx()), float64(s.Source.Dy())
+	sx0, sy0 := float32(s.Source.Min.X), float32(s.Source.Min.Y)
+	sx1, sy1 := float32(s.Source.Max.X), float32(s.Source.Max.Y)
+
+	i := uint16(len(b.vertices))
+	for _, corner := range [4][2]float64{{0, 0}, {w, 0}, {0, h}, {w, h}} {
+		x, y := g.Apply(corner[0], corner[1])
+
+		src := [2]float32{sx0, sy0}
+		if corner[0] > 0 {
+			src[0] = sx1
+		}
+
+		if corner[1] > 0 {
+			src[1] = sy1
+		}
+
+		b.vertices = append(b.vertices, ebiten.Vertex{
+			DstX: float32(x), DstY: float32(y),
+			SrcX: src[0], SrcY: src[1],
+			ColorR: r, ColorG: gr, ColorB: bl, ColorA: a,
+		})
+	}
+
+	b.indices = append(b.indices, i, i+1, i+2, i+1, i+3, i+2)
+}
+
+// scale returns the scale factor, a zero scale meaning no scaling.
+func scale(s float64) float64 {
+	if s == 0 {
+		return 1
+	}
+
+	return s
+}
+
+// batch returns the batch of the atlas, reusing the batches of the previous frames.
+func (d *Drawer) batch(atlas *ebiten.Image) *batch {
+	for _, b := range d.batches[:d.used] {
+		if b.atlas == atlas {
+			return b
+		}
+	}
+
+	if d.used == len(d.batches) {
+		d.batches = append(d.batches, &batch{})
+	}
+
+	b := d.batches[d.used]
+	b.atlas = atlas
+	d.used++
+
+	return b
+}
+
+// draw draws a batch on screen and empties it.
+func (d *Drawer) draw(screen *ebiten.Image, b *batch) {
+	if len(b.indices) > 0 {
+		screen.DrawTriangles(b.vertices, b.indices, b.atlas, &d.op)
+	}
+
+	b.vertices = b.vertices[:0]
+	b.indices = b.indices[:0]
+}
+
+// Flush draws the batches of the frame, one DrawTriangles call per atlas.
+func (d *Drawer) Flush(screen *ebiten.Image) {
+	for _, b := range d.batches[:d.used] {
+		d.draw(screen, b)
+		b.atlas = nil
+	}
+
+	d.used = 0
+}
@@ -0,0 +1,38 @@
+package ecs
+
+// Startup is a system run once, at the beginning of the first Update following its registration,
+// e.g. to spawn the initial scene or load the configuration into resources.
+type Startup interface {
+	Start(world *ECS) error
+}
+
+// StartupFunc is a function used as a startup system.
+type StartupFunc func(world *ECS) error
+
+// Start calls the function.
+func (f StartupFunc) Start(world *ECS) error {
+	return f(world)
+}
+
+// RegisterStartup registers startup systems. They run in the order they were registered in,
+// before the updaters and after the functions queued with Defer, and are then discarded.
+// If one of them returns an error, Update returns it, and the following ones run on the next Update.
+func (ecs *ECS) RegisterStartup(s ...Startup) {
+	ecs.startups = append(ecs.startups, s...)
+}
+
+// runStartups runs and discards the pending startup systems.
+func (ecs *ECS) runStartups() error {
+	for len(ecs.startups) > 0 {
+		s := ecs.startups[0]
+		ecs.startups[0] = nil
+		ecs.startups = ecs.startups[1:]
+
+		err := s.Start(ecs)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,123 @@
+package ecs
+
+import (
+	"reflect"
+
+	"github.com/jtbonhomme/ebiten-ecs/entity"
+)
+
+// entityStore is implemented by the component stores attached to the world,
+// so that they forget unregistered entities.
+type entityStore interface {
+	Remove(id entity.ID)
+	Clear()
+	memory() int64
+}
+
+// Store is an opt-in struct-of-arrays storage for one component type: all the values of type T
+// live in a single densely packed slice, so systems iterating over them (e.g. Position += Velocity)
+// walk contiguous memory instead of following one pointer per component.
+// Pointers returned by a store are only valid until the next call to Add or Remove.
+type Store[T any] struct {
+	values   []T
+	entities []entity.ID
+	index    map[entity.ID]int
+}
+
+// NewStore creates a store for the component type T, attached to the world: entities unregistered from
+// the world are removed from the store.
+func NewStore[T any](ecs *ECS) *Store[T] {
+	s := &Store[T]{
+		values:   make([]T, 0, ecs.config.maxEntities),
+		entities: make([]entity.ID, 0, ecs.config.maxEntities),
+		index:    make(map[entity.ID]int, ecs.config.maxEntities),
+	}
+	ecs.stores = append(ecs.stores, s)
+
+	return s
+}
+
+// Add sets the value of the component of an entity, adding it to the store if needed.
+// It returns a pointer to the stored value.
+func (s *Store[T]) Add(id entity.ID, value T) *T {
+	if i, ok := s.index[id]; ok {
+		s.values[i] = value
+		return &s.values[i]
+	}
+
+	s.index[id] = len(s.values)
+	s.values = append(s.values, value)
+	s.entities = append(s.entities, id)
+
+	return &s.values[len(s.values)-1]
+}
+
+// Get returns a pointer to the component of an entity, or nil if the entity has none.
+func (s *Store[T]) Get(id entity.ID) *T {
+	i, ok := s.index[id]
+	if !ok {
+		return nil
+	}
+
+	return &s.values[i]
+}
+
+// Remove removes the component of an entity from the store.
+// The last value of the store is moved to the freed slot, so that values stay densely packed.
+func (s *Store[T]) Remove(id entity.ID) {
+	i, ok := s.index[id]
+	if !ok {
+		return
+	}
+
+	last := len(s.values) - 1
+	s.values[i] = s.values[last]
+	s.entities[i] = s.entities[last]
+	s.index[s.entities[i]] = i
+
+	var zero T
+	s.values[last] = zero
+	s.values = s.values[:last]
+	s.entities = s.entities[:last]
+	delete(s.index, id)
+}
+
+// Clear removes all the components from the store, keeping its allocated memory.
+func (s *Store[T]) Clear() {
+	clear(s.values)
+	s.values = s.values[:0]
+	s.entities = s.entities[:0]
+	clear(s.index)
+}
+
+// Len returns the number of components in the store.
+func (s *Store[T]) Len() int {
+	return len(s.values)
+}
+
+// Values returns the densely packed values of the store. The value at index i belongs
+// to the entity at index i of Entities. The slice can be modified in place but not resized.
+func (s *Store[T]) Values() []T {
+	return s.values
+}
+
+// Entities returns the IDs of the entities of the store, in the same order as Values.
+func (s *Store[T]) Entities() []entity.ID {
+	return s.entities
+}
+
+// Each calls fn for every component of the store, in storage order.
+func (s *Store[T]) Each(fn func(id entity.ID, value *T)) {
+	for i := range s.values {
+		fn(s.entities[i], &s.values[i])
+	}
+}
+
+// memory returns an estimate of the memory allocated by the store, in bytes.
+func (s *Store[T]) memory() int64 {
+	values := int64(cap(s.values)) * int64(reflect.TypeFor[T]().Size())
+	entities := int64(cap(s.entities)) * int64(reflect.TypeFor[entity.ID]().Size())
+
+	// a map entry holds the key, the value and some overhead
+	return values + entities + int64(len(s.index))*3*8
+}
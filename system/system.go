@@ -1,15 +1,17 @@
 package system
 
 import (
+	"fmt"
 	"strconv"
+	"sync/atomic"
+	"time"
 
-	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/jtbonhomme/ebiten-ecs/component"
 	"github.com/jtbonhomme/ebiten-ecs/entity"
 )
 
 var (
-	id ID
+	id atomic.Int64
 )
 
 // ID is a type that represents a unique identifier for a system.
@@ -21,9 +23,16 @@ func (id ID) String() string {
 }
 
 // AssignID is a function that assigns a unique ID to a system.
+// It is safe to call it from several goroutines.
 func AssignID() ID {
-	id++
-	return id
+	return ID(id.Add(1))
+}
+
+// ResetIDs restarts the allocation of the IDs of the systems from 1, so that golden tests get the same IDs
+// whatever the tests run before them. It MUST NOT be called while systems created before are still in use,
+// as their IDs would be assigned again.
+func ResetIDs() {
+	id.Store(0)
 }
 
 // System is an interface that represents a system in the ECS architecture.
@@ -31,14 +40,80 @@ type System interface {
 	ID() ID
 }
 
+// IDSetter is implemented by systems whose ID is assigned when they are registered, see Base.
+type IDSetter interface {
+	SetID(id ID)
+}
+
+// Base is meant to be embedded in system structs, so that they implement ID without an id field
+// and a call to AssignID of their own: the ID is assigned when the system is registered in a world.
+//
+//	type Movement struct {
+//		system.Base
+//	}
+type Base struct {
+	id ID
+}
+
+// ID returns the unique ID of the system, 0 until it is registered.
+func (b *Base) ID() ID {
+	return b.id
+}
+
+// SetID sets the ID of the system.
+func (b *Base) SetID(id ID) {
+	b.id = id
+}
+
+// EnsureID returns the ID of a system being registered, assigning it a new unique one if it is 0
+// and the system implements IDSetter. It panics if the ID is 0 and cannot be assigned, as all such systems
+// would share the same entities.
+func EnsureID(s System) ID {
+	if id := s.ID(); id != 0 {
+		return id
+	}
+
+	setter, ok := s.(IDSetter)
+	if !ok {
+		panic(fmt.Sprintf("the system %T you are trying to register MUST have a non-zero ID: call AssignID or embed Base", s))
+	}
+
+	setter.SetID(AssignID())
+
+	return s.ID()
+}
+
 // Updater is an interface that represents a system that updates entities in the ECS architecture.
 type Updater interface {
 	System
 	Update(entity.ID, []component.Component, map[entity.ID][]component.Component) error
 }
 
-// Drawer is an interface that represents a system that draws entities in the ECS architecture.
-type Drawer interface {
-	System
-	Draw(*ebiten.Image, []component.Component)
+// Predecessor is an interface implemented by updaters that must run before some other updaters.
+type Predecessor interface {
+	RunBefore() []ID
+}
+
+// Successor is an interface implemented by updaters that must run after some other updaters.
+type Successor interface {
+	RunAfter() []ID
+}
+
+// Stepper is an interface implemented by updaters doing some work once per step before updating their entities,
+// such as clearing the events of the previous step. BeginStep is called each step the updater runs,
+// even if it has no entity to update.
+type Stepper interface {
+	BeginStep() error
+}
+
+// Periodic is an interface implemented by updaters which do not need to run every step, such as pathfinding
+// or AI re-planning. They run on the first step, then once every RunEvery steps.
+type Periodic interface {
+	RunEvery() int
+}
+
+// Timed is an interface implemented by updaters which need to run once per period of simulated time
+// rather than every step. They run on the first step, then on the first step of each following period.
+type Timed interface {
+	RunEveryDuration() time.Duration
 }
@@ -0,0 +1,113 @@
+package ecs
+
+import (
+	"github.com/jtbonhomme/ebiten-ecs/entity"
+	"github.com/jtbonhomme/ebiten-ecs/system"
+)
+
+// Tag adds data-less tags ("player", "enemy", "pickup"...) to an entity.
+// Tagging an entity twice with the same tag has no effect.
+func (ecs *ECS) Tag(e entity.Entity, tags ...string) {
+	if len(tags) == 0 {
+		return
+	}
+
+	if _, ok := ecs.entityTags[e.ID()]; !ok {
+		ecs.entityTags[e.ID()] = make(map[string]struct{}, len(tags))
+	}
+
+	for _, tag := range tags {
+		if _, ok := ecs.entityTags[e.ID()][tag]; ok {
+			continue
+		}
+
+		ecs.entityTags[e.ID()][tag] = struct{}{}
+		ecs.taggedEntities[tag] = append(ecs.taggedEntities[tag], e)
+	}
+}
+
+// Untag removes tags from an entity.
+func (ecs *ECS) Untag(id entity.ID, tags ...string) {
+	for _, tag := range tags {
+		if _, ok := ecs.entityTags[id][tag]; !ok {
+			continue
+		}
+
+		delete(ecs.entityTags[id], tag)
+
+		_, shared := ecs.sharedTags[tag]
+
+		var copied bool
+		ecs.taggedEntities[tag], copied = ecs.removeEntity(ecs.taggedEntities[tag], id, shared)
+
+		if copied {
+			delete(ecs.sharedTags, tag)
+		}
+
+		if len(ecs.taggedEntities[tag]) == 0 {
+			delete(ecs.taggedEntities, tag)
+		}
+	}
+
+	if len(ecs.entityTags[id]) == 0 {
+		delete(ecs.entityTags, id)
+	}
+}
+
+// HasTag returns true if the entity has the given tag.
+func (ecs *ECS) HasTag(id entity.ID, tag string) bool {
+	_, ok := ecs.entityTags[id][tag]
+	return ok
+}
+
+// Tags returns the tags of an entity, in no particular order.
+func (ecs *ECS) Tags(id entity.ID) []string {
+	tags := make([]string, 0, len(ecs.entityTags[id]))
+	for tag := range ecs.entityTags[id] {
+		tags = append(tags, tag)
+	}
+
+	return tags
+}
+
+// EntitiesWithTag returns the entities having the given tag, in tagging order.
+// The returned slice must not be modified; it is not affected by the entities untagged or unregistered afterwards,
+// so that entities can be unregistered while ranging over it.
+func (ecs *ECS) EntitiesWithTag(tag string) []entity.Entity {
+	ecs.sharedTags[tag] = struct{}{}
+	return ecs.taggedEntities[tag]
+}
+
+// FilterEntitiesWithTags filters the entities associated with a system, keeping only those having all the given tags.
+func (ecs *ECS) FilterEntitiesWithTags(s system.System, tags ...string) []entity.Entity {
+	return ecs.AppendEntitiesWithTags([]entity.Entity{}, s, tags...)
+}
+
+// AppendEntitiesWithTags appends to dst the entities associated with a system having all the given tags,
+// and returns the extended slice. Passing dst[:0] reuses the buffer of a previous call and avoids
+// allocating every frame.
+func (ecs *ECS) AppendEntitiesWithTags(dst []entity.Entity, s system.System, tags ...string) []entity.Entity {
+	for _, e := range ecs.FilterEntities(s) {
+		if ecs.hasTags(e.ID(), tags) {
+			dst = append(dst, e)
+		}
+	}
+
+	return dst
+}
+
+// hasTags returns true if the entity has all the given tags.
+func (ecs *ECS) hasTags(id entity.ID, tags []string) bool {
+	for _, tag := range tags {
+		if !ecs.HasTag(id, tag) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// untagAll removes all the tags of an entity.
+func (ecs *ECS) untagAll(id entity.ID) {
+	ecs.Untag(id, ecs.Tags(id)...)
+}
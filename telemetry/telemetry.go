@@ -0,0 +1,106 @@
+// Package telemetry exports time-series sampled from an ECS world (player HP, currency, positions...)
+// to CSV, for offline balancing analysis in notebooks.
+package telemetry
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+
+	ecs "github.com/jtbonhomme/ebiten-ecs"
+	"github.com/jtbonhomme/ebiten-ecs/component"
+	"github.com/jtbonhomme/ebiten-ecs/entity"
+)
+
+// Series is a named value sampled from the world once per frame.
+type Series struct {
+	Name   string
+	Sample func(world *ecs.ECS) float64
+}
+
+// Field creates a series sampling a field of the component of type *T of an entity.
+// The sampled value is 0 while the entity does not have such a component.
+func Field[T any](name string, id entity.ID, t component.Type[T], field func(*T) float64) Series {
+	return Series{
+		Name: name,
+		Sample: func(world *ecs.ECS) float64 {
+			c := t.Get(world.EntityComponents(id))
+			if c == nil {
+				return 0
+			}
+
+			return field(c)
+		},
+	}
+}
+
+// Exporter writes one CSV row per sampled frame, with the frame number followed by the value of every series.
+// Rows are buffered and only written to the underlying writer when the buffer is full or on Flush,
+// so that sampling does not cause frame hitches.
+// The exporter is disabled by default, and sampling is a no-op until it is enabled.
+type Exporter struct {
+	world   *ecs.ECS
+	csv     *csv.Writer
+	series  []Series
+	row     []string
+	enabled bool
+	header  bool
+	frame   int
+}
+
+// NewExporter creates a disabled exporter writing the given series sampled from the world to w.
+func NewExporter(world *ecs.ECS, w io.Writer, series ...Series) *Exporter {
+	return &Exporter{
+		world:  world,
+		csv:    csv.NewWriter(w),
+		series: series,
+		row:    make([]string, len(series)+1),
+	}
+}
+
+// SetEnabled enables or disables the exporter, typically from a debug flag.
+func (x *Exporter) SetEnabled(enabled bool) {
+	x.enabled = enabled
+}
+
+// Enabled returns true if the exporter is enabled.
+func (x *Exporter) Enabled() bool {
+	return x.enabled
+}
+
+// Sample samples every series and buffers a new row. It is meant to be called once per frame, after the
+// world Update. The frame counter increases even when the exporter is disabled.
+func (x *Exporter) Sample() error {
+	x.frame++
+
+	if !x.enabled {
+		return nil
+	}
+
+	if !x.header {
+		x.header = true
+		x.row[0] = "frame"
+
+		for i, s := range x.series {
+			x.row[i+1] = s.Name
+		}
+
+		err := x.csv.Write(x.row)
+		if err != nil {
+			return err
+		}
+	}
+
+	x.row[0] = strconv.Itoa(x.frame)
+	for i, s := range x.series {
+		x.row[i+1] = strconv.FormatFloat(s.Sample(x.world), 'g', -1, 64)
+	}
+
+	return x.csv.Write(x.row)
+}
+
+// Flush writes the buffered rows to the underlying writer.
+func (x *Exporter) Flush() error {
+	x.csv.Flush()
+	return x.csv.Error()
+}
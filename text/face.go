@@ -0,0 +1,65 @@
+package text
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+)
+
+// Face is a font face: it measures and draws single lines of text.
+// Any font library can be plugged in by implementing it.
+type Face interface {
+	// Advance returns the width of the line, in pixels.
+	Advance(line string) float64
+	// LineHeight returns the distance between two lines, in pixels.
+	LineHeight() float64
+	// Draw draws the line with its top left corner at the given position.
+	Draw(screen *ebiten.Image, line string, x, y float64, clr color.Color)
+}
+
+// Size of the glyphs of the Ebiten debug font.
+const (
+	debugGlyphWidth  = 6
+	debugGlyphHeight = 16
+)
+
+// debugFace draws text with the monospace font of ebitenutil.DebugPrintAt.
+type debugFace struct {
+	scratch *ebiten.Image
+	op      ebiten.DrawImageOptions
+}
+
+// DebugFace returns a face using the built-in font of ebitenutil.DebugPrintAt, which does not need any font file.
+// Unlike DebugPrintAt, it supports colors. It only supports ASCII characters.
+func DebugFace() Face {
+	return &debugFace{}
+}
+
+// Advance returns the width of the line, in pixels.
+func (f *debugFace) Advance(line string) float64 {
+	return float64(len(line) * debugGlyphWidth)
+}
+
+// LineHeight returns the distance between two lines, in pixels.
+func (f *debugFace) LineHeight() float64 {
+	return debugGlyphHeight
+}
+
+// Draw draws the line with its top left corner at the given position.
+// The line is printed on a scratch image, then drawn tinted with the color.
+func (f *debugFace) Draw(screen *ebiten.Image, line string, x, y float64, clr color.Color) {
+	w := max(len(line)*debugGlyphWidth, 1)
+	if f.scratch == nil || f.scratch.Bounds().Dx() < w {
+		f.scratch = ebiten.NewImage(w, debugGlyphHeight)
+	}
+
+	f.scratch.Clear()
+	ebitenutil.DebugPrintAt(f.scratch, line, 0, 0)
+
+	f.op.GeoM.Reset()
+	f.op.GeoM.Translate(x, y)
+	f.op.ColorScale.Reset()
+	f.op.ColorScale.ScaleWithColor(clr)
+	screen.DrawImage(f.scratch, &f.op)
+}
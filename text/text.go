@@ -0,0 +1,150 @@
+// Package text provides a Text component and a drawer rendering it, with colors, alignment and word wrapping.
+package text
+
+import (
+	"image/color"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"github.com/jtbonhomme/ebiten-ecs/component"
+	"github.com/jtbonhomme/ebiten-ecs/system"
+)
+
+// Align is the horizontal alignment of the lines of a text.
+type Align int
+
+// Alignments of the lines of a text, relative to the position of the text.
+const (
+	AlignLeft Align = iota
+	AlignCenter
+	AlignRight
+)
+
+// Text is the component of the entities displaying a text.
+// The lines of the text are laid out again only when its value, face or width change.
+type Text struct {
+	Value string
+	Face  Face
+	Color color.Color
+	Align Align
+	// X and Y are the position of the text on screen: the top left corner of a left aligned text,
+	// the top center of a centered text and the top right corner of a right aligned text.
+	X float64
+	Y float64
+	// Width is the width at which lines are wrapped, in pixels. Lines are not wrapped if it is 0.
+	Width float64
+
+	lines []string
+	value string
+	face  Face
+	width float64
+}
+
+// textType is the handle used to find the Text component.
+var textType = component.NewType[Text]()
+
+// Lines returns the lines of the text, split on line breaks and wrapped at the width of the text.
+func (t *Text) Lines() []string {
+	if t.lines != nil && t.value == t.Value && t.face == t.Face && t.width == t.Width {
+		return t.lines
+	}
+
+	t.value, t.face, t.width = t.Value, t.Face, t.Width
+	t.lines = t.lines[:0]
+
+	for _, paragraph := range strings.Split(t.Value, "\n") {
+		t.lines = wrap(t.lines, paragraph, t.Face, t.Width)
+	}
+
+	return t.lines
+}
+
+// wrap appends the lines of a paragraph wrapped at the given width to lines.
+// Words longer than the width are not split.
+func wrap(lines []string, paragraph string, face Face, width float64) []string {
+	if width <= 0 || face.Advance(paragraph) <= width {
+		return append(lines, paragraph)
+	}
+
+	line := ""
+
+	for _, word := range strings.Fields(paragraph) {
+		if line == "" {
+			line = word
+			continue
+		}
+
+		if face.Advance(line+" "+word) > width {
+			lines = append(lines, line)
+			line = word
+
+			continue
+		}
+
+		line += " " + word
+	}
+
+	return append(lines, line)
+}
+
+// Size returns the width and height of the text once laid out, in pixels.
+func (t *Text) Size() (float64, float64) {
+	w := 0.0
+	for _, line := range t.Lines() {
+		w = max(w, t.Face.Advance(line))
+	}
+
+	return w, float64(len(t.lines)) * t.Face.LineHeight()
+}
+
+// Draw draws the text on screen.
+func (t *Text) Draw(screen *ebiten.Image) {
+	clr := t.Color
+	if clr == nil {
+		clr = color.White
+	}
+
+	y := t.Y
+
+	for _, line := range t.Lines() {
+		x := t.X
+
+		switch t.Align {
+		case AlignCenter:
+			x -= t.Face.Advance(line) / 2
+		case AlignRight:
+			x -= t.Face.Advance(line)
+		}
+
+		t.Face.Draw(screen, line, x, y, clr)
+		y += t.Face.LineHeight()
+	}
+}
+
+// Drawer is a drawer drawing the Text component of its entities.
+type Drawer struct {
+	id system.ID
+}
+
+// NewDrawer creates a drawer drawing the Text component of its entities.
+func NewDrawer() *Drawer {
+	return &Drawer{
+		id: system.AssignID(),
+	}
+}
+
+// ID returns the unique ID of the drawer.
+func (d *Drawer) ID() system.ID {
+	return d.id
+}
+
+// Draw draws the Text component of the entity, if it has one with a face.
+func (d *Drawer) Draw(screen *ebiten.Image, c []component.Component) {
+	t := textType.Get(c)
+	if t == nil || t.Face == nil {
+		return
+	}
+
+	t.Draw(screen)
+}
@@ -0,0 +1,135 @@
+package tilemap
+
+import (
+	"image"
+	"math"
+)
+
+// Grid is a collision grid, telling which tiles of a map are solid.
+type Grid struct {
+	Width      int
+	Height     int
+	TileWidth  int
+	TileHeight int
+	solid      []bool
+}
+
+// Collision builds the collision grid of the map: a tile is solid if, in any of the given layers
+// (all the tile layers if none is given), it has the boolean property with the given name set to true,
+// such as "solid" or "collides".
+func (m *Map) Collision(property string, layers ...string) *Grid {
+	g := &Grid{
+		Width:      m.Width,
+		Height:     m.Height,
+		TileWidth:  m.TileWidth,
+		TileHeight: m.TileHeight,
+		solid:      make([]bool, m.Width*m.Height),
+	}
+
+	// the property of each tile is looked up once per tile ID, not once per cell
+	solid := make(map[uint32]bool)
+
+	for _, l := range m.Layers {
+		if len(layers) > 0 && !contains(layers, l.Name) {
+			continue
+		}
+
+		for y := 0; y < l.Height && y < g.Height; y++ {
+			for x := 0; x < l.Width && x < g.Width; x++ {
+				gid := l.GID(x, y)
+				if gid == 0 {
+					continue
+				}
+
+				s, ok := solid[gid]
+				if !ok {
+					s = m.TileProperties(gid).Bool(property)
+					solid[gid] = s
+				}
+
+				if s {
+					g.solid[y*g.Width+x] = true
+				}
+			}
+		}
+	}
+
+	return g
+}
+
+// contains returns true if the name is in the list.
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Solid returns true if the tile at the given tile coordinates is solid.
+// Tiles outside of the map are solid, so that entities cannot leave it.
+func (g *Grid) Solid(x, y int) bool {
+	if x < 0 || y < 0 || x >= g.Width || y >= g.Height {
+		return true
+	}
+
+	return g.solid[y*g.Width+x]
+}
+
+// SolidAt returns true if the tile at the given position in pixels is solid.
+func (g *Grid) SolidAt(x, y float64) bool {
+	return g.Solid(floorDiv(x, g.TileWidth), floorDiv(y, g.TileHeight))
+}
+
+// floorDiv returns the index of the tile of the given size containing the position v.
+func floorDiv(v float64, size int) int {
+	return int(math.Floor(v / float64(size)))
+}
+
+// Overlaps returns true if the rectangle, in pixels, overlaps a solid tile.
+func (g *Grid) Overlaps(r image.Rectangle) bool {
+	if r.Empty() {
+		return false
+	}
+
+	x0, y0 := floorDiv(float64(r.Min.X), g.TileWidth), floorDiv(float64(r.Min.Y), g.TileHeight)
+	x1, y1 := floorDiv(float64(r.Max.X-1), g.TileWidth), floorDiv(float64(r.Max.Y-1), g.TileHeight)
+
+	for y := y0; y <= y1; y++ {
+		for x := x0; x <= x1; x++ {
+			if g.Solid(x, y) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Rectangles returns the solid tiles as rectangles in pixels, merging the solid tiles of each row
+// which are next to each other, so that a physics engine gets a few large bodies instead of one body per tile.
+func (g *Grid) Rectangles() []image.Rectangle {
+	rects := []image.Rectangle{}
+
+	for y := 0; y < g.Height; y++ {
+		for x := 0; x < g.Width; x++ {
+			if !g.solid[y*g.Width+x] {
+				continue
+			}
+
+			start := x
+			for x < g.Width && g.solid[y*g.Width+x] {
+				x++
+			}
+
+			rects = append(rects, image.Rect(
+				start*g.TileWidth, y*g.TileHeight,
+				x*g.TileWidth, (y+1)*g.TileHeight,
+			))
+		}
+	}
+
+	return rects
+}
@@ -0,0 +1,42 @@
+package tilemap
+
+import (
+	ecs "github.com/jtbonhomme/ebiten-ecs"
+	"github.com/jtbonhomme/ebiten-ecs/component"
+	"github.com/jtbonhomme/ebiten-ecs/entity"
+)
+
+// Factory returns the components of the entity created for an object of the map.
+// Returning no component skips the object.
+type Factory func(layer *ObjectGroup, o *Object) []component.Component
+
+// Spawn creates an entity for each object of the object layers of the map, with the components returned
+// by the factory, and tags it with the type of the object when it has one.
+// It returns the created entities, in the order of the objects in the map.
+func (m *Map) Spawn(world *ecs.ECS, factory Factory) []entity.Entity {
+	entities := []entity.Entity{}
+
+	for i := range m.ObjectGroups {
+		layer := &m.ObjectGroups[i]
+
+		for j := range layer.Objects {
+			o := &layer.Objects[j]
+
+			components := factory(layer, o)
+			if len(components) == 0 {
+				continue
+			}
+
+			e := entity.New()
+			world.RegisterComponents(e, components...)
+
+			if o.Kind() != "" {
+				world.Tag(e, o.Kind())
+			}
+
+			entities = append(entities, e)
+		}
+	}
+
+	return entities
+}
@@ -0,0 +1,228 @@
+package tilemap
+
+import (
+	"fmt"
+	"image"
+	_ "image/png" // tileset images are usually PNG files
+	"math"
+	"os"
+	"path/filepath"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"github.com/jtbonhomme/ebiten-ecs/component"
+	"github.com/jtbonhomme/ebiten-ecs/system"
+)
+
+// ChunkSize is the width and height, in tiles, of the chunks the tile layers are rendered by.
+const ChunkSize = 16
+
+// ImageLoader loads the image of a tileset from its path.
+type ImageLoader func(path string) (*ebiten.Image, error)
+
+// LoadImage is the default image loader, decoding an image file with the registered image formats.
+func LoadImage(path string) (*ebiten.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("decode image %s: %w", path, err)
+	}
+
+	return ebiten.NewImageFromImage(img), nil
+}
+
+// Renderer renders the tile layers of a map. Each layer is rendered by chunks of ChunkSize x ChunkSize tiles,
+// each chunk being drawn once to an image of its own the first time it becomes visible,
+// so that a frame only draws the few chunk images overlapping the screen instead of every tile.
+type Renderer struct {
+	m      *Map
+	images map[*Tileset]*ebiten.Image
+	chunks map[*Layer][]*ebiten.Image
+	op     ebiten.DrawImageOptions
+}
+
+// NewRenderer creates a renderer for the map, loading the tileset images with the given loader
+// (LoadImage if nil), from the directory of the map.
+func NewRenderer(m *Map, load ImageLoader) (*Renderer, error) {
+	if load == nil {
+		load = LoadImage
+	}
+
+	r := &Renderer{
+		m:      m,
+		images: make(map[*Tileset]*ebiten.Image, len(m.Tilesets)),
+		chunks: make(map[*Layer][]*ebiten.Image, len(m.Layers)),
+	}
+
+	for _, ts := range m.Tilesets {
+		img, err := load(filepath.Join(m.dir, ts.Image.Source))
+		if err != nil {
+			return nil, fmt.Errorf("tileset %q: %w", ts.Name, err)
+		}
+
+		r.images[ts] = img
+	}
+
+	for _, l := range m.Layers {
+		r.chunks[l] = make([]*ebiten.Image, chunksOf(l.Width)*chunksOf(l.Height))
+	}
+
+	return r, nil
+}
+
+// chunksOf returns the number of chunks needed to cover n tiles.
+func chunksOf(n int) int {
+	return (n + ChunkSize - 1) / ChunkSize
+}
+
+// Draw draws the visible tile layers of the map on screen, the top left corner of the screen being
+// at position (x, y) of the map, in pixels. Only the chunks overlapping the screen are drawn.
+func (r *Renderer) Draw(screen *ebiten.Image, x, y float64) {
+	for _, l := range r.m.Layers {
+		if l.IsVisible() {
+			r.DrawLayer(screen, l, x, y)
+		}
+	}
+}
+
+// DrawLayer draws a tile layer of the map on screen, the top left corner of the screen being
+// at position (x, y) of the map, in pixels. Only the chunks overlapping the screen are drawn.
+func (r *Renderer) DrawLayer(screen *ebiten.Image, l *Layer, x, y float64) {
+	chunks, ok := r.chunks[l]
+	if !ok {
+		panic(fmt.Sprintf("the layer %q you are trying to draw does not belong to the map of the renderer", l.Name))
+	}
+
+	cw, ch := float64(ChunkSize*r.m.TileWidth), float64(ChunkSize*r.m.TileHeight)
+	x, y = x-l.OffsetX, y-l.OffsetY
+	size := screen.Bounds().Size()
+
+	cols, rows := chunksOf(l.Width), chunksOf(l.Height)
+	x0, y0 := max(int(math.Floor(x/cw)), 0), max(int(math.Floor(y/ch)), 0)
+	x1, y1 := min(int(math.Floor((x+float64(size.X))/cw)), cols-1), min(int(math.Floor((y+float64(size.Y))/ch)), rows-1)
+
+	for cy := y0; cy <= y1; cy++ {
+		for cx := x0; cx <= x1; cx++ {
+			i := cy*cols + cx
+			if chunks[i] == nil {
+				chunks[i] = r.renderChunk(l, cx, cy)
+			}
+
+			r.op.GeoM.Reset()
+			r.op.GeoM.Translate(float64(cx)*cw-x, float64(cy)*ch-y)
+			screen.DrawImage(chunks[i], &r.op)
+		}
+	}
+}
+
+// renderChunk draws the tiles of a chunk of a layer to a new image.
+func (r *Renderer) renderChunk(l *Layer, cx, cy int) *ebiten.Image {
+	tw, th := r.m.TileWidth, r.m.TileHeight
+	img := ebiten.NewImage(ChunkSize*tw, ChunkSize*th)
+
+	for ty := 0; ty < ChunkSize; ty++ {
+		for tx := 0; tx < ChunkSize; tx++ {
+			x, y := cx*ChunkSize+tx, cy*ChunkSize+ty
+			if x >= l.Width || y >= l.Height {
+				continue
+			}
+
+			raw := l.Tiles[y*l.Width+x]
+			if raw&gidMask == 0 {
+				continue
+			}
+
+			r.drawTile(img, raw, tx*tw, ty*th)
+		}
+	}
+
+	return img
+}
+
+// drawTile draws the tile with the given global ID, including its flip flags, at the given position.
+func (r *Renderer) drawTile(dst *ebiten.Image, raw uint32, x, y int) {
+	ts, id := r.m.Tileset(raw)
+	if ts == nil || ts.Columns == 0 {
+		return
+	}
+
+	src, ok := r.images[ts]
+	if !ok {
+		return
+	}
+
+	col, row := int(id)%ts.Columns, int(id)/ts.Columns
+	sx, sy := ts.Margin+col*(ts.TileWidth+ts.Spacing), ts.Margin+row*(ts.TileHeight+ts.Spacing)
+	tile := src.SubImage(image.Rect(sx, sy, sx+ts.TileWidth, sy+ts.TileHeight)).(*ebiten.Image)
+
+	w, h := float64(ts.TileWidth), float64(ts.TileHeight)
+
+	var op ebiten.DrawImageOptions
+
+	if raw&FlippedDiagonally != 0 {
+		// swap the x and y axes
+		op.GeoM.SetElement(0, 0, 0)
+		op.GeoM.SetElement(0, 1, 1)
+		op.GeoM.SetElement(1, 0, 1)
+		op.GeoM.SetElement(1, 1, 0)
+		w, h = h, w
+	}
+
+	if raw&FlippedHorizontally != 0 {
+		op.GeoM.Scale(-1, 1)
+		op.GeoM.Translate(w, 0)
+	}
+
+	if raw&FlippedVertically != 0 {
+		op.GeoM.Scale(1, -1)
+		op.GeoM.Translate(0, h)
+	}
+
+	// tiles larger than the map tiles are aligned on the bottom of their cell, as in Tiled
+	op.GeoM.Translate(float64(x), float64(y+r.m.TileHeight)-h)
+	dst.DrawImage(tile, &op)
+}
+
+// Camera is the component holding the position of the map drawn at the top left corner of the screen, in pixels.
+type Camera struct {
+	X float64
+	Y float64
+}
+
+// cameraType is the handle used to find the Camera component.
+var cameraType = component.NewType[Camera]()
+
+// Drawer is a drawer rendering the map from the point of view of the Camera component of its entity.
+type Drawer struct {
+	id       system.ID
+	renderer *Renderer
+}
+
+// NewDrawer creates a drawer rendering the map with the given renderer.
+// It is registered as an ebitenecs drawer with the entity holding the camera.
+func NewDrawer(renderer *Renderer) *Drawer {
+	return &Drawer{
+		id:       system.AssignID(),
+		renderer: renderer,
+	}
+}
+
+// ID returns the unique ID of the drawer.
+func (d *Drawer) ID() system.ID {
+	return d.id
+}
+
+// Draw draws the map from the point of view of the camera of the entity.
+func (d *Drawer) Draw(screen *ebiten.Image, c []component.Component) {
+	camera := cameraType.Get(c)
+	if camera == nil {
+		return
+	}
+
+	d.renderer.Draw(screen, camera.X, camera.Y)
+}
@@ -0,0 +1,357 @@
+// Package tilemap loads maps made with the Tiled editor (https://www.mapeditor.org/, TMX format),
+// creates entities for their object layers, builds collision grids from tile properties,
+// and renders their tile layers by chunks, culled to the visible part of the map.
+package tilemap
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Flags stored in the high bits of a global tile ID.
+const (
+	FlippedHorizontally uint32 = 0x80000000
+	FlippedVertically   uint32 = 0x40000000
+	FlippedDiagonally   uint32 = 0x20000000
+
+	gidMask = ^(FlippedHorizontally | FlippedVertically | FlippedDiagonally)
+)
+
+// Property is a custom property set on a map, a tile or an object in Tiled.
+type Property struct {
+	Name  string `xml:"name,attr"`
+	Type  string `xml:"type,attr"`
+	Value string `xml:"value,attr"`
+}
+
+// Properties is a list of custom properties.
+type Properties []Property
+
+// Get returns the value of the property with the given name, and false if there is none.
+func (p Properties) Get(name string) (string, bool) {
+	for _, prop := range p {
+		if prop.Name == name {
+			return prop.Value, true
+		}
+	}
+
+	return "", false
+}
+
+// Bool returns true if the property with the given name is set to true.
+func (p Properties) Bool(name string) bool {
+	v, _ := p.Get(name)
+	b, _ := strconv.ParseBool(v)
+
+	return b
+}
+
+// Image is the image of a tileset.
+type Image struct {
+	Source string `xml:"source,attr"`
+	Width  int    `xml:"width,attr"`
+	Height int    `xml:"height,attr"`
+}
+
+// Tile holds the properties of a tile of a tileset.
+type Tile struct {
+	ID         uint32     `xml:"id,attr"`
+	Type       string     `xml:"type,attr"`
+	Properties Properties `xml:"properties>property"`
+}
+
+// Tileset is a set of tiles cut from a single image.
+type Tileset struct {
+	FirstGID   uint32 `xml:"firstgid,attr"`
+	Source     string `xml:"source,attr"`
+	Name       string `xml:"name,attr"`
+	TileWidth  int    `xml:"tilewidth,attr"`
+	TileHeight int    `xml:"tileheight,attr"`
+	TileCount  int    `xml:"tilecount,attr"`
+	Columns    int    `xml:"columns,attr"`
+	Spacing    int    `xml:"spacing,attr"`
+	Margin     int    `xml:"margin,attr"`
+	Image      Image  `xml:"image"`
+	Tiles      []Tile `xml:"tile"`
+}
+
+// Tile returns the tile of the tileset with the given local ID, or nil if it has no properties.
+func (ts *Tileset) Tile(id uint32) *Tile {
+	for i := range ts.Tiles {
+		if ts.Tiles[i].ID == id {
+			return &ts.Tiles[i]
+		}
+	}
+
+	return nil
+}
+
+// data is the raw content of a tile layer.
+type data struct {
+	Encoding    string `xml:"encoding,attr"`
+	Compression string `xml:"compression,attr"`
+	Content     string `xml:",chardata"`
+}
+
+// Layer is a tile layer. Its tiles are global tile IDs, row by row, 0 meaning no tile.
+type Layer struct {
+	ID         int        `xml:"id,attr"`
+	Name       string     `xml:"name,attr"`
+	Width      int        `xml:"width,attr"`
+	Height     int        `xml:"height,attr"`
+	Visible    *bool      `xml:"visible,attr"`
+	OffsetX    float64    `xml:"offsetx,attr"`
+	OffsetY    float64    `xml:"offsety,attr"`
+	Properties Properties `xml:"properties>property"`
+	Data       data       `xml:"data"`
+	Tiles      []uint32   `xml:"-"`
+}
+
+// IsVisible returns true if the layer is visible.
+func (l *Layer) IsVisible() bool {
+	return l.Visible == nil || *l.Visible
+}
+
+// GID returns the global tile ID at the given tile coordinates, without its flip flags.
+// It returns 0 outside of the layer.
+func (l *Layer) GID(x, y int) uint32 {
+	if x < 0 || y < 0 || x >= l.Width || y >= l.Height {
+		return 0
+	}
+
+	return l.Tiles[y*l.Width+x] & gidMask
+}
+
+// Object is a shape or a tile placed on an object layer.
+type Object struct {
+	ID         int        `xml:"id,attr"`
+	Name       string     `xml:"name,attr"`
+	Type       string     `xml:"type,attr"`
+	Class      string     `xml:"class,attr"`
+	X          float64    `xml:"x,attr"`
+	Y          float64    `xml:"y,attr"`
+	Width      float64    `xml:"width,attr"`
+	Height     float64    `xml:"height,attr"`
+	Rotation   float64    `xml:"rotation,attr"`
+	GID        uint32     `xml:"gid,attr"`
+	Properties Properties `xml:"properties>property"`
+}
+
+// Kind returns the type of the object, named class since Tiled 1.9.
+func (o *Object) Kind() string {
+	if o.Class != "" {
+		return o.Class
+	}
+
+	return o.Type
+}
+
+// ObjectGroup is an object layer.
+type ObjectGroup struct {
+	ID         int        `xml:"id,attr"`
+	Name       string     `xml:"name,attr"`
+	Properties Properties `xml:"properties>property"`
+	Objects    []Object   `xml:"object"`
+}
+
+// Map is a Tiled map. Only finite orthogonal maps are supported.
+type Map struct {
+	Orientation  string        `xml:"orientation,attr"`
+	Width        int           `xml:"width,attr"`
+	Height       int           `xml:"height,attr"`
+	TileWidth    int           `xml:"tilewidth,attr"`
+	TileHeight   int           `xml:"tileheight,attr"`
+	Infinite     bool          `xml:"infinite,attr"`
+	Properties   Properties    `xml:"properties>property"`
+	Tilesets     []*Tileset    `xml:"tileset"`
+	Layers       []*Layer      `xml:"layer"`
+	ObjectGroups []ObjectGroup `xml:"objectgroup"`
+
+	// dir is the directory of the map file, from which the paths of tilesets and images are resolved.
+	dir string
+}
+
+// Load reads a TMX map from r. External tilesets are read from the directory dir.
+// It returns an error if the map cannot be decoded or is not supported.
+func Load(r io.Reader, dir string) (*Map, error) {
+	m := &Map{dir: dir}
+
+	err := xml.NewDecoder(r).Decode(m)
+	if err != nil {
+		return nil, fmt.Errorf("decode map: %w", err)
+	}
+
+	if m.Orientation != "orthogonal" {
+		return nil, fmt.Errorf("unsupported map orientation %q", m.Orientation)
+	}
+
+	if m.Infinite {
+		return nil, fmt.Errorf("infinite maps are not supported")
+	}
+
+	for i, ts := range m.Tilesets {
+		if ts.Source == "" {
+			continue
+		}
+
+		ext, err := loadTileset(filepath.Join(dir, ts.Source))
+		if err != nil {
+			return nil, err
+		}
+
+		ext.FirstGID = ts.FirstGID
+		ext.Image.Source = filepath.Join(filepath.Dir(ts.Source), ext.Image.Source)
+		m.Tilesets[i] = ext
+	}
+
+	for _, l := range m.Layers {
+		l.Tiles, err = l.Data.decode(l.Width * l.Height)
+		if err != nil {
+			return nil, fmt.Errorf("layer %q: %w", l.Name, err)
+		}
+
+		l.Data = data{}
+	}
+
+	return m, nil
+}
+
+// LoadFile reads a TMX map from a file.
+func LoadFile(path string) (*Map, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return Load(f, filepath.Dir(path))
+}
+
+// loadTileset reads an external TSX tileset.
+func loadTileset(path string) (*Tileset, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	ts := &Tileset{}
+
+	err = xml.NewDecoder(f).Decode(ts)
+	if err != nil {
+		return nil, fmt.Errorf("decode tileset %s: %w", path, err)
+	}
+
+	return ts, nil
+}
+
+// decode returns the n global tile IDs of a layer.
+func (d data) decode(n int) ([]uint32, error) {
+	switch d.Encoding {
+	case "csv":
+		tiles := make([]uint32, 0, n)
+
+		for _, field := range strings.Split(d.Content, ",") {
+			gid, err := strconv.ParseUint(strings.TrimSpace(field), 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("decode csv tile: %w", err)
+			}
+
+			tiles = append(tiles, uint32(gid))
+		}
+
+		if len(tiles) != n {
+			return nil, fmt.Errorf("%d tiles instead of %d", len(tiles), n)
+		}
+
+		return tiles, nil
+	case "base64":
+		raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(d.Content))
+		if err != nil {
+			return nil, fmt.Errorf("decode base64 tiles: %w", err)
+		}
+
+		var r io.Reader = bytes.NewReader(raw)
+
+		switch d.Compression {
+		case "":
+		case "zlib":
+			r, err = zlib.NewReader(r)
+		case "gzip":
+			r, err = gzip.NewReader(r)
+		default:
+			return nil, fmt.Errorf("unsupported compression %q", d.Compression)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("decompress tiles: %w", err)
+		}
+
+		tiles := make([]uint32, n)
+
+		err = binary.Read(r, binary.LittleEndian, tiles)
+		if err != nil {
+			return nil, fmt.Errorf("read tiles: %w", err)
+		}
+
+		return tiles, nil
+	default:
+		return nil, fmt.Errorf("unsupported encoding %q, use csv or base64", d.Encoding)
+	}
+}
+
+// Tileset returns the tileset holding the tile with the given global ID, and the local ID of the tile.
+// It returns nil if there is none.
+func (m *Map) Tileset(gid uint32) (*Tileset, uint32) {
+	gid &= gidMask
+
+	var found *Tileset
+
+	for _, ts := range m.Tilesets {
+		if ts.FirstGID <= gid && (found == nil || ts.FirstGID > found.FirstGID) {
+			found = ts
+		}
+	}
+
+	if found == nil {
+		return nil, 0
+	}
+
+	return found, gid - found.FirstGID
+}
+
+// TileProperties returns the properties of the tile with the given global ID, or nil if it has none.
+func (m *Map) TileProperties(gid uint32) Properties {
+	ts, id := m.Tileset(gid)
+	if ts == nil {
+		return nil
+	}
+
+	t := ts.Tile(id)
+	if t == nil {
+		return nil
+	}
+
+	return t.Properties
+}
+
+// Layer returns the tile layer with the given name, or nil if there is none.
+func (m *Map) Layer(name string) *Layer {
+	for _, l := range m.Layers {
+		if l.Name == name {
+			return l
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"image/color"
+
+	"github.com/jtbonhomme/ebiten-ecs/entity"
+)
+
+// ButtonState is the state of a button.
+type ButtonState int
+
+// States of a button.
+const (
+	Idle ButtonState = iota
+	Hovered
+	Pressed
+	Disabled
+)
+
+// Button is the component of the clickable widgets. The label of a button is drawn from its Label component,
+// and its background with the color of its state.
+type Button struct {
+	IdleColor     color.Color
+	HoveredColor  color.Color
+	PressedColor  color.Color
+	DisabledColor color.Color
+	Disabled      bool
+	// OnClick, if set, is called when the button is clicked, from the UI updater.
+	OnClick func(id entity.ID)
+	state   ButtonState
+	clicked bool
+}
+
+// State returns the state of the button at the current step.
+func (b *Button) State() ButtonState {
+	if b.Disabled {
+		return Disabled
+	}
+
+	return b.state
+}
+
+// Clicked returns true if the button has been clicked at the current step: the mouse button has been released
+// over the button after being pressed over it.
+func (b *Button) Clicked() bool {
+	return b.clicked
+}
+
+// color returns the background color of the button in its current state.
+func (b *Button) color() color.Color {
+	switch b.State() {
+	case Hovered:
+		return b.HoveredColor
+	case Pressed:
+		return b.PressedColor
+	case Disabled:
+		return b.DisabledColor
+	default:
+		return b.IdleColor
+	}
+}
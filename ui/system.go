@@ -0,0 +1,147 @@
+package ui
+
+import (
+	"image"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+
+	ecs "github.com/jtbonhomme/ebiten-ecs"
+	"github.com/jtbonhomme/ebiten-ecs/component"
+	"github.com/jtbonhomme/ebiten-ecs/entity"
+	"github.com/jtbonhomme/ebiten-ecs/input"
+	"github.com/jtbonhomme/ebiten-ecs/system"
+	"github.com/jtbonhomme/ebiten-ecs/text"
+)
+
+// MouseButtonLeft is the mouse button clicking the buttons, with the same value as ebiten.MouseButtonLeft.
+const MouseButtonLeft input.MouseButton = 0
+
+var (
+	widgetType = component.NewType[Widget]()
+	panelType  = component.NewType[Panel]()
+	labelType  = component.NewType[Label]()
+	buttonType = component.NewType[Button]()
+)
+
+// System is the updater hit-testing the buttons against the input of the world.
+// Buttons are hit-tested against their bounds computed by the last draw.
+type System struct {
+	id    system.ID
+	world *ecs.ECS
+}
+
+// NewSystem creates the updater handling the buttons of the world.
+func NewSystem(world *ecs.ECS) *System {
+	return &System{
+		id:    system.AssignID(),
+		world: world,
+	}
+}
+
+// ID returns the unique ID of the system.
+func (s *System) ID() system.ID {
+	return s.id
+}
+
+// Update updates the state of the button of the entity, and calls its OnClick callback when it is clicked.
+func (s *System) Update(id entity.ID, c []component.Component, _ map[entity.ID][]component.Component) error {
+	w, b := widgetType.Get(c), buttonType.Get(c)
+	if w == nil || b == nil {
+		return nil
+	}
+
+	b.clicked = false
+
+	if w.Hidden || b.Disabled {
+		b.state = Idle
+		return nil
+	}
+
+	in := s.world.Input()
+	inside := image.Pt(in.CursorPosition()).In(w.Bounds())
+
+	switch {
+	case inside && in.IsMouseButtonJustPressed(MouseButtonLeft):
+		b.state = Pressed
+	case b.state == Pressed && in.IsMouseButtonPressed(MouseButtonLeft):
+		// stay pressed until the mouse button is released, even outside of the button
+	case b.state == Pressed:
+		b.clicked = inside
+		b.state = Idle
+	default:
+		b.state = Idle
+	}
+
+	if b.state == Idle && inside {
+		b.state = Hovered
+	}
+
+	if b.clicked && b.OnClick != nil {
+		b.OnClick(id)
+	}
+
+	return nil
+}
+
+// Drawer draws the widgets of its entities in screen space: their panel or button background, then their label.
+// It is meant to be registered at the Layer z-index.
+type Drawer struct {
+	id system.ID
+}
+
+// NewDrawer creates the drawer of the widgets.
+func NewDrawer() *Drawer {
+	return &Drawer{
+		id: system.AssignID(),
+	}
+}
+
+// ID returns the unique ID of the drawer.
+func (d *Drawer) ID() system.ID {
+	return d.id
+}
+
+// Draw lays the widget of the entity out on the screen, and draws it.
+func (d *Drawer) Draw(screen *ebiten.Image, c []component.Component) {
+	w := widgetType.Get(c)
+	if w == nil || w.Hidden {
+		return
+	}
+
+	size := screen.Bounds().Size()
+	r := w.Layout(size.X, size.Y)
+	x, y, width, height := float32(r.Min.X), float32(r.Min.Y), float32(r.Dx()), float32(r.Dy())
+
+	if p := panelType.Get(c); p != nil {
+		if p.Color != nil {
+			vector.DrawFilledRect(screen, x, y, width, height, p.Color, false)
+		}
+
+		if p.BorderColor != nil && p.BorderWidth > 0 {
+			vector.StrokeRect(screen, x, y, width, height, p.BorderWidth, p.BorderColor, false)
+		}
+	}
+
+	if b := buttonType.Get(c); b != nil {
+		if clr := b.color(); clr != nil {
+			vector.DrawFilledRect(screen, x, y, width, height, clr, false)
+		}
+	}
+
+	if l := labelType.Get(c); l != nil && l.Face != nil {
+		l.text.Value, l.text.Face, l.text.Color, l.text.Align = l.Text, l.Face, l.Color, l.Align
+		l.text.Y = float64(r.Min.Y) + (float64(r.Dy())-l.Face.LineHeight())/2
+
+		switch l.Align {
+		case text.AlignCenter:
+			l.text.X = float64(r.Min.X) + float64(r.Dx())/2
+		case text.AlignRight:
+			l.text.X = float64(r.Max.X)
+		default:
+			l.text.X = float64(r.Min.X)
+		}
+
+		l.text.Draw(screen)
+	}
+}
@@ -0,0 +1,78 @@
+// Package ui provides retained UI widgets as components (Panel, Label, Button), laid out in screen space
+// relative to an anchor, hit-tested by an updater and drawn by a drawer registered on the UI layer.
+package ui
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/jtbonhomme/ebiten-ecs/text"
+)
+
+// Layer is the z-index the UI drawer is meant to be registered at, above the game drawers.
+const Layer = 1 << 20
+
+// Anchor is the point of the screen a widget is positioned from.
+type Anchor int
+
+// Anchors of the widgets: the widget is placed at its offset from the anchor point of the screen,
+// aligned on the same point of the widget, e.g. the bottom right corner of a widget anchored
+// to BottomRight is at its offset from the bottom right corner of the screen.
+const (
+	TopLeft Anchor = iota
+	Top
+	TopRight
+	Left
+	Center
+	Right
+	BottomLeft
+	Bottom
+	BottomRight
+)
+
+// Widget is the component holding the layout of a UI element.
+type Widget struct {
+	Anchor Anchor
+	// X and Y are the offset of the widget from its anchor, in pixels. Positive values move it right and down.
+	X      int
+	Y      int
+	Width  int
+	Height int
+	// Hidden widgets are neither drawn nor hit-tested.
+	Hidden bool
+	bounds image.Rectangle
+}
+
+// Layout computes the bounds of the widget on a screen of the given size.
+func (w *Widget) Layout(screenWidth, screenHeight int) image.Rectangle {
+	col, row := int(w.Anchor)%3, int(w.Anchor)/3
+
+	x := (screenWidth-w.Width)*col/2 + w.X
+	y := (screenHeight-w.Height)*row/2 + w.Y
+
+	w.bounds = image.Rect(x, y, x+w.Width, y+w.Height)
+
+	return w.bounds
+}
+
+// Bounds returns the bounds of the widget on screen, as computed by the last layout.
+func (w *Widget) Bounds() image.Rectangle {
+	return w.bounds
+}
+
+// Panel is the component of the widgets drawn as a filled rectangle, with an optional border.
+type Panel struct {
+	Color       color.Color
+	BorderColor color.Color
+	BorderWidth float32
+}
+
+// Label is the component of the widgets displaying a text, aligned horizontally in the widget
+// and centered vertically.
+type Label struct {
+	Text  string
+	Face  text.Face
+	Color color.Color
+	Align text.Align
+	text  text.Text
+}
@@ -0,0 +1,43 @@
+package ecs
+
+import (
+	"github.com/jtbonhomme/ebiten-ecs/component"
+	"github.com/jtbonhomme/ebiten-ecs/entity"
+	"github.com/jtbonhomme/ebiten-ecs/system"
+)
+
+// WorldUpdater is an updater receiving the world instead of the raw components registry, so that it can
+// run queries, read resources, publish events and defer structural changes (see Defer) through the world API.
+// It is registered with RegisterWorldUpdater, and can implement the same optional interfaces as other updaters.
+type WorldUpdater interface {
+	system.System
+	UpdateWorld(world *ECS, id entity.ID, c []component.Component) error
+}
+
+// worldUpdater adapts a WorldUpdater to the system.Updater interface.
+type worldUpdater struct {
+	WorldUpdater
+	world *ECS
+}
+
+// Update calls the world updater with the world.
+func (u *worldUpdater) Update(id entity.ID, c []component.Component, _ map[entity.ID][]component.Component) error {
+	return u.UpdateWorld(u.world, id, c)
+}
+
+// RegisterWorldUpdater registers a world updater with the entities it updates, like RegisterUpdater.
+func (ecs *ECS) RegisterWorldUpdater(s WorldUpdater, e ...entity.Entity) system.ID {
+	system.EnsureID(s)
+
+	return ecs.RegisterUpdater(&worldUpdater{WorldUpdater: s, world: ecs}, e...)
+}
+
+// unwrap returns the system registered by the user, which may be wrapped to satisfy system.Updater,
+// so that its optional interfaces can be checked.
+func unwrap(s system.System) system.System {
+	if u, ok := s.(*worldUpdater); ok {
+		return u.WorldUpdater
+	}
+
+	return s
+}
@@ -0,0 +1,295 @@
+// Package wsbridge forwards events of a world over WebSockets, in both directions, so that a companion
+// web dashboard or a simple client/server game can exchange events without custom plumbing.
+// The world has no central event bus: the game forwards the events it wants to share with Send,
+// and the events received from the peers are dispatched to the handlers added with On, from the game loop.
+// Events are sent as JSON text messages {"kind": ..., "data": ...}, easy to handle from a browser.
+// The package has no dependency: it implements the subset of RFC 6455 it needs (unfragmented text messages
+// are sent, fragmented and binary messages are accepted, no extension, no TLS).
+//
+//	// server
+//	bridge := wsbridge.New()
+//	bridge.On("chat", func(peer wsbridge.PeerID, data json.RawMessage) { ... })
+//	http.Handle("/events", bridge)
+//
+//	// client
+//	bridge, err := wsbridge.DialBridge("ws://localhost:8080/events")
+//
+//	// in the game loop, on both sides
+//	err := bridge.Send("chat", msg)
+//	bridge.Poll()
+package wsbridge
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+// PeerID is the identifier of a peer of a bridge, unique for the lifetime of the bridge.
+type PeerID int
+
+// Handler is a function called with the data of an event received from a peer.
+type Handler func(peer PeerID, data json.RawMessage)
+
+// envelope is the message carrying an event.
+type envelope struct {
+	Kind string          `json:"kind"`
+	Data json.RawMessage `json:"data,omitempty"`
+}
+
+// Bridge exchanges events with the peers connected to it. Send, SendTo and Poll MUST be called from
+// the game loop; peers can connect from any goroutine.
+type Bridge struct {
+	handlers map[string][]Handler
+	// forwarded is the set of kinds of events sent to the peers, nil to send all of them
+	forwarded map[string]struct{}
+
+	mutex  sync.Mutex
+	peers  map[PeerID]*Conn
+	nextID PeerID
+
+	onConnect    func(PeerID)
+	onDisconnect func(PeerID, error)
+}
+
+// New creates a bridge without peer. It is an http.Handler accepting WebSocket connections as peers.
+func New() *Bridge {
+	return &Bridge{
+		handlers: make(map[string][]Handler),
+		peers:    make(map[PeerID]*Conn),
+	}
+}
+
+// DialBridge creates a bridge connected to the WebSocket server at the given ws:// URL.
+func DialBridge(url string) (*Bridge, error) {
+	c, err := Dial(url)
+	if err != nil {
+		return nil, err
+	}
+
+	b := New()
+	b.Add(c)
+
+	return b, nil
+}
+
+// ServeHTTP upgrades the request to a WebSocket connection and adds it as a peer.
+func (b *Bridge) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c, err := Upgrade(w, r)
+	if err != nil {
+		return
+	}
+
+	b.Add(c)
+}
+
+// Add adds a connection as a peer of the bridge, and returns its ID.
+func (b *Bridge) Add(c *Conn) PeerID {
+	b.mutex.Lock()
+	b.nextID++
+	id := b.nextID
+	b.peers[id] = c
+	onConnect := b.onConnect
+	b.mutex.Unlock()
+
+	if onConnect != nil {
+		onConnect(id)
+	}
+
+	return id
+}
+
+// OnConnect sets the function called when a peer is added. It is called from the goroutine adding the peer,
+// the goroutine serving the HTTP request for the peers connected with ServeHTTP.
+func (b *Bridge) OnConnect(f func(peer PeerID)) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.onConnect = f
+}
+
+// OnDisconnect sets the function called from Poll, Send or SendTo when the connection to a peer is lost.
+// The error is io.EOF if the peer closed it.
+func (b *Bridge) OnDisconnect(f func(peer PeerID, err error)) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.onDisconnect = f
+}
+
+// Forward restricts the events sent to the peers to the given kinds; the other events passed to Send and SendTo
+// are dropped, so that the game can send all its events and select the ones shared with a dashboard in one place.
+// Without kinds, all the events are sent again.
+func (b *Bridge) Forward(kinds ...string) {
+	if len(kinds) == 0 {
+		b.forwarded = nil
+		return
+	}
+
+	b.forwarded = make(map[string]struct{}, len(kinds))
+	for _, k := range kinds {
+		b.forwarded[k] = struct{}{}
+	}
+}
+
+// On adds a handler called by Poll for each event of the given kind received from a peer.
+// The events without handler are dropped.
+func (b *Bridge) On(kind string, handler Handler) {
+	b.handlers[kind] = append(b.handlers[kind], handler)
+}
+
+// Send sends an event to all the peers. Its data is serialized as JSON.
+func (b *Bridge) Send(kind string, data interface{}) error {
+	msg, ok, err := b.encode(kind, data)
+	if !ok || err != nil {
+		return err
+	}
+
+	for _, id := range b.Peers() {
+		b.write(id, msg)
+	}
+
+	return nil
+}
+
+// SendTo sends an event to a single peer. Its data is serialized as JSON.
+// The method returns an error if the peer is unknown or its connection was lost.
+func (b *Bridge) SendTo(peer PeerID, kind string, data interface{}) error {
+	msg, ok, err := b.encode(kind, data)
+	if !ok || err != nil {
+		return err
+	}
+
+	return b.write(peer, msg)
+}
+
+// Poll dispatches the events received from the peers since the last call to their handlers,
+// and removes the peers whose connection was lost.
+func (b *Bridge) Poll() {
+	for _, id := range b.Peers() {
+		c := b.conn(id)
+		if c == nil {
+			continue
+		}
+
+		for {
+			data, ok, err := c.Receive()
+			if err != nil {
+				b.drop(id, err)
+				break
+			}
+
+			if !ok {
+				break
+			}
+
+			var e envelope
+			if json.Unmarshal(data, &e) != nil {
+				// not an event, ignored
+				continue
+			}
+
+			for _, h := range b.handlers[e.Kind] {
+				h(id, e.Data)
+			}
+		}
+	}
+}
+
+// Peers returns the IDs of the connected peers, in the order they connected.
+func (b *Bridge) Peers() []PeerID {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	ids := make([]PeerID, 0, len(b.peers))
+	for id := range b.peers {
+		ids = append(ids, id)
+	}
+
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	return ids
+}
+
+// Close closes the connections to all the peers.
+func (b *Bridge) Close() error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	var err error
+
+	for id, c := range b.peers {
+		if e := c.Close(); e != nil && err == nil {
+			err = e
+		}
+
+		delete(b.peers, id)
+	}
+
+	return err
+}
+
+// encode serializes an event, returning false if its kind is not forwarded.
+func (b *Bridge) encode(kind string, data interface{}) ([]byte, bool, error) {
+	if b.forwarded != nil {
+		if _, ok := b.forwarded[kind]; !ok {
+			return nil, false, nil
+		}
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, false, fmt.Errorf("wsbridge: cannot serialize event %q: %w", kind, err)
+	}
+
+	msg, err := json.Marshal(envelope{Kind: kind, Data: raw})
+	if err != nil {
+		return nil, false, err
+	}
+
+	return msg, true, nil
+}
+
+// write sends a message to a peer, removing it if its connection was lost.
+func (b *Bridge) write(id PeerID, msg []byte) error {
+	c := b.conn(id)
+	if c == nil {
+		return fmt.Errorf("wsbridge: unknown peer %d", id)
+	}
+
+	err := c.Send(msg)
+	if err != nil {
+		b.drop(id, err)
+	}
+
+	return err
+}
+
+// conn returns the connection to a peer, nil if it is unknown.
+func (b *Bridge) conn(id PeerID) *Conn {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	return b.peers[id]
+}
+
+// drop removes a peer whose connection was lost.
+func (b *Bridge) drop(id PeerID, err error) {
+	b.mutex.Lock()
+	c, ok := b.peers[id]
+	delete(b.peers, id)
+	onDisconnect := b.onDisconnect
+	b.mutex.Unlock()
+
+	if !ok {
+		return
+	}
+
+	c.conn.Close()
+
+	if onDisconnect != nil {
+		onDisconnect(id, err)
+	}
+}
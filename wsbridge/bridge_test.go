@@ -0,0 +1,191 @@
+package wsbridge_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jtbonhomme/ebiten-ecs/wsbridge"
+)
+
+// connect starts a server bridge and connects a client bridge to it.
+func connect(t *testing.T, server *wsbridge.Bridge) *wsbridge.Bridge {
+	t.Helper()
+
+	srv := httptest.NewServer(server)
+	t.Cleanup(srv.Close)
+
+	client, err := wsbridge.DialBridge("ws://" + strings.TrimPrefix(srv.URL, "http://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		client.Close()
+		server.Close()
+	})
+
+	poll(t, server, func() bool { return len(server.Peers()) == 1 })
+
+	return client
+}
+
+// poll polls a bridge until the condition is true.
+func poll(t *testing.T, b *wsbridge.Bridge, cond func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not reached")
+		}
+
+		time.Sleep(time.Millisecond)
+		b.Poll()
+	}
+}
+
+func TestBridgeOn(t *testing.T) {
+	server := wsbridge.New()
+	client := connect(t, server)
+
+	var got []string
+
+	server.On("chat", func(peer wsbridge.PeerID, data json.RawMessage) {
+		var msg string
+		if err := json.Unmarshal(data, &msg); err != nil {
+			t.Error(err)
+		}
+
+		if peer != server.Peers()[0] {
+			t.Errorf("event received from peer %d, want %d", peer, server.Peers()[0])
+		}
+
+		got = append(got, msg)
+	})
+
+	for _, msg := range []string{"hello", "world"} {
+		if err := client.Send("chat", msg); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// without handler, the event is dropped
+	if err := client.Send("unknown", 1); err != nil {
+		t.Fatal(err)
+	}
+
+	poll(t, server, func() bool { return len(got) == 2 })
+
+	if got[0] != "hello" || got[1] != "world" {
+		t.Errorf("received %q, want [hello world]", got)
+	}
+
+	// and in the other direction
+	var answer string
+
+	client.On("chat", func(_ wsbridge.PeerID, data json.RawMessage) {
+		_ = json.Unmarshal(data, &answer)
+	})
+
+	if err := server.SendTo(server.Peers()[0], "chat", "welcome"); err != nil {
+		t.Fatal(err)
+	}
+
+	poll(t, client, func() bool { return answer == "welcome" })
+}
+
+func TestBridgeForward(t *testing.T) {
+	server := wsbridge.New()
+	client := connect(t, server)
+
+	var got []string
+
+	for _, kind := range []string{"a", "b", "c"} {
+		server.On(kind, func(wsbridge.PeerID, json.RawMessage) {
+			got = append(got, kind)
+		})
+	}
+
+	client.Forward("a", "c")
+
+	for _, kind := range []string{"a", "b", "c"} {
+		if err := client.Send(kind, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// without kinds, all the events are sent again
+	client.Forward()
+
+	if err := client.Send("b", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	poll(t, server, func() bool { return len(got) == 3 })
+
+	if got[0] != "a" || got[1] != "c" || got[2] != "b" {
+		t.Errorf("received %q, want [a c b]", got)
+	}
+}
+
+func TestBridgeOnDisconnect(t *testing.T) {
+	server := wsbridge.New()
+
+	var (
+		mutex     sync.Mutex
+		connected []wsbridge.PeerID
+	)
+
+	// called from the goroutine serving the HTTP request
+	server.OnConnect(func(peer wsbridge.PeerID) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		connected = append(connected, peer)
+	})
+
+	var (
+		disconnected []wsbridge.PeerID
+		reason       error
+	)
+
+	server.OnDisconnect(func(peer wsbridge.PeerID, err error) {
+		disconnected = append(disconnected, peer)
+		reason = err
+	})
+
+	client := connect(t, server)
+	peer := server.Peers()[0]
+
+	mutex.Lock()
+	if len(connected) != 1 || connected[0] != peer {
+		t.Errorf("OnConnect called with %v, want [%d]", connected, peer)
+	}
+	mutex.Unlock()
+
+	client.Close()
+
+	poll(t, server, func() bool { return len(disconnected) > 0 })
+
+	if len(disconnected) != 1 || disconnected[0] != peer {
+		t.Errorf("OnDisconnect called with %v, want [%d]", disconnected, peer)
+	}
+
+	if reason != io.EOF {
+		t.Errorf("disconnection error is %v, want %v", reason, io.EOF)
+	}
+
+	if len(server.Peers()) != 0 {
+		t.Errorf("peers are %v after disconnection, want none", server.Peers())
+	}
+
+	if err := server.SendTo(peer, "chat", "hello?"); err == nil {
+		t.Error("sending to a disconnected peer succeeded")
+	}
+}
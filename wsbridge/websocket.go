@@ -0,0 +1,427 @@
+package wsbridge
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// MaxMessageSize is the size above which received messages are rejected, closing the connection.
+const MaxMessageSize = 1 << 20
+
+// queueSize is the number of received messages buffered by a connection until they are read.
+const queueSize = 256
+
+// acceptGUID is the GUID of the WebSocket handshake, see RFC 6455.
+const acceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// WebSocket opcodes.
+const (
+	opContinuation = 0x0
+	opText         = 0x1
+	opBinary       = 0x2
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xA
+)
+
+// Close status codes, see RFC 6455 section 7.4.1.
+const (
+	closeProtocolError = 1002
+	closeTooLarge      = 1009
+)
+
+var (
+	errNotWebSocket = errors.New("wsbridge: not a WebSocket handshake")
+	errVersion      = errors.New("wsbridge: unsupported WebSocket version")
+	errTooLarge     = errors.New("wsbridge: message too large")
+	errProtocol     = errors.New("wsbridge: protocol error")
+)
+
+// Conn is a WebSocket connection exchanging text messages. It implements netsync.Transport, so that
+// netsync snapshots can be sent over WebSockets as well. Received messages are read by a goroutine
+// and buffered until Receive is called.
+type Conn struct {
+	conn   net.Conn
+	r      *bufio.Reader
+	client bool
+
+	writeMutex sync.Mutex
+	in         chan []byte
+
+	errMutex sync.Mutex
+	err      error
+}
+
+// Upgrade upgrades an HTTP request to a WebSocket connection. It answers with an error and returns it
+// if the request is not a WebSocket handshake of version 13.
+func Upgrade(w http.ResponseWriter, r *http.Request) (*Conn, error) {
+	if !headerContains(r.Header, "Connection", "upgrade") || !headerContains(r.Header, "Upgrade", "websocket") {
+		http.Error(w, errNotWebSocket.Error(), http.StatusBadRequest)
+		return nil, errNotWebSocket
+	}
+
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if key == "" {
+		http.Error(w, errNotWebSocket.Error(), http.StatusBadRequest)
+		return nil, errNotWebSocket
+	}
+
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		http.Error(w, errVersion.Error(), http.StatusUpgradeRequired)
+
+		return nil, errVersion
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "wsbridge: connection cannot be hijacked", http.StatusInternalServerError)
+		return nil, errors.New("wsbridge: connection cannot be hijacked")
+	}
+
+	conn, rw, err := hijacker.Hijack()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = fmt.Fprintf(rw, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Accept: %s\r\n\r\n", acceptKey(key))
+	if err == nil {
+		err = rw.Flush()
+	}
+
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return newConn(conn, rw.Reader, false), nil
+}
+
+// Dial opens a WebSocket connection to the given ws:// URL.
+func Dial(rawURL string) (*Conn, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.Scheme != "ws" {
+		return nil, fmt.Errorf("wsbridge: unsupported scheme %q", u.Scheme)
+	}
+
+	host := u.Host
+	if u.Port() == "" {
+		host = net.JoinHostPort(u.Hostname(), "80")
+	}
+
+	conn, err := net.Dial("tcp", host)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, 16)
+	_, _ = rand.Read(nonce)
+	key := base64.StdEncoding.EncodeToString(nonce)
+
+	req := &http.Request{
+		Method:     http.MethodGet,
+		URL:        u,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header: http.Header{
+			"Upgrade":               {"websocket"},
+			"Connection":            {"Upgrade"},
+			"Sec-WebSocket-Key":     {key},
+			"Sec-WebSocket-Version": {"13"},
+		},
+		Host: u.Host,
+	}
+
+	err = req.Write(conn)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	r := bufio.NewReader(conn)
+
+	resp, err := http.ReadResponse(r, req)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusSwitchingProtocols || resp.Header.Get("Sec-WebSocket-Accept") != acceptKey(key) {
+		conn.Close()
+		return nil, fmt.Errorf("wsbridge: handshake failed: %s", resp.Status)
+	}
+
+	return newConn(conn, r, true), nil
+}
+
+// newConn creates a connection and starts reading its messages.
+func newConn(conn net.Conn, r *bufio.Reader, client bool) *Conn {
+	c := &Conn{
+		conn:   conn,
+		r:      r,
+		client: client,
+		in:     make(chan []byte, queueSize),
+	}
+
+	go c.readLoop()
+
+	return c
+}
+
+// Send sends a text message.
+func (c *Conn) Send(data []byte) error {
+	if err := c.Err(); err != nil {
+		return err
+	}
+
+	return c.writeFrame(opText, data)
+}
+
+// Receive returns the next received message, or false if there is none for now.
+// Once the connection is closed and all its messages are read, it returns the error which closed it,
+// io.EOF if the other end closed it.
+func (c *Conn) Receive() ([]byte, bool, error) {
+	select {
+	case data, ok := <-c.in:
+		if !ok {
+			return nil, false, c.Err()
+		}
+
+		return data, true, nil
+	default:
+		return nil, false, nil
+	}
+}
+
+// Err returns the error which closed the connection, or nil while it is open.
+func (c *Conn) Err() error {
+	c.errMutex.Lock()
+	defer c.errMutex.Unlock()
+
+	return c.err
+}
+
+// Close closes the connection, sending a close frame to the other end.
+func (c *Conn) Close() error {
+	_ = c.writeFrame(opClose, nil)
+	c.fail(net.ErrClosed)
+
+	return c.conn.Close()
+}
+
+// fail records the first error which closed the connection.
+func (c *Conn) fail(err error) {
+	c.errMutex.Lock()
+	defer c.errMutex.Unlock()
+
+	if c.err == nil {
+		c.err = err
+	}
+}
+
+// readLoop reads the messages of the connection until it is closed, answering pings.
+// A frame breaking the protocol or a message larger than MaxMessageSize closes the connection with the status
+// telling why.
+func (c *Conn) readLoop() {
+	defer close(c.in)
+	defer c.conn.Close()
+
+	var message []byte
+
+	fragmented := false
+
+	for {
+		fin, op, payload, err := c.readFrame()
+		if err != nil {
+			c.abort(err)
+			return
+		}
+
+		switch op {
+		case opPing:
+			_ = c.writeFrame(opPong, payload)
+		case opPong:
+		case opClose:
+			_ = c.writeFrame(opClose, nil)
+			c.fail(io.EOF)
+
+			return
+		case opText, opBinary, opContinuation:
+			// a continuation frame continues a fragmented message, and only a continuation frame does
+			if (op == opContinuation) != fragmented {
+				c.abort(errProtocol)
+				return
+			}
+
+			if len(message)+len(payload) > MaxMessageSize {
+				c.abort(errTooLarge)
+				return
+			}
+
+			message = append(message, payload...)
+			fragmented = !fin
+
+			if fin {
+				c.in <- message
+				message = nil
+			}
+		default:
+			c.abort(errProtocol)
+			return
+		}
+	}
+}
+
+// abort closes the connection after an error, telling the other end why when the error is its fault.
+func (c *Conn) abort(err error) {
+	switch {
+	case errors.Is(err, errTooLarge):
+		_ = c.writeFrame(opClose, binary.BigEndian.AppendUint16(nil, closeTooLarge))
+	case errors.Is(err, errProtocol):
+		_ = c.writeFrame(opClose, binary.BigEndian.AppendUint16(nil, closeProtocolError))
+	}
+
+	c.fail(err)
+}
+
+// readFrame reads a frame, unmasking its payload. It returns an error wrapping errProtocol
+// if the frame is masked or not as it should be.
+func (c *Conn) readFrame() (bool, byte, []byte, error) {
+	var head [2]byte
+
+	_, err := io.ReadFull(c.r, head[:])
+	if err != nil {
+		return false, 0, nil, err
+	}
+
+	fin, op := head[0]&0x80 != 0, head[0]&0x0F
+	masked := head[1]&0x80 != 0
+	length := uint64(head[1] & 0x7F)
+
+	// the frames sent by clients MUST be masked, and the frames sent by servers MUST NOT, see RFC 6455 section 5.1
+	if masked == c.client {
+		return false, 0, nil, fmt.Errorf("%w: unexpected masking", errProtocol)
+	}
+
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(c.r, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(c.r, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+
+	if length > MaxMessageSize {
+		return false, 0, nil, errTooLarge
+	}
+
+	var mask [4]byte
+	if masked {
+		if _, err := io.ReadFull(c.r, mask[:]); err != nil {
+			return false, 0, nil, err
+		}
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(c.r, payload); err != nil {
+		return false, 0, nil, err
+	}
+
+	if masked {
+		for i := range payload {
+			payload[i] ^= mask[i%4]
+		}
+	}
+
+	return fin, op, payload, nil
+}
+
+// writeFrame writes a single frame, masked when sent by a client.
+func (c *Conn) writeFrame(op byte, payload []byte) error {
+	frame := make([]byte, 0, 14+len(payload))
+	frame = append(frame, 0x80|op)
+
+	maskBit := byte(0)
+	if c.client {
+		maskBit = 0x80
+	}
+
+	switch n := len(payload); {
+	case n < 126:
+		frame = append(frame, maskBit|byte(n))
+	case n <= 0xFFFF:
+		frame = append(frame, maskBit|126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(n))
+	default:
+		frame = append(frame, maskBit|127)
+		frame = binary.BigEndian.AppendUint64(frame, uint64(n))
+	}
+
+	if c.client {
+		var mask [4]byte
+		_, _ = rand.Read(mask[:])
+		frame = append(frame, mask[:]...)
+
+		start := len(frame)
+		frame = append(frame, payload...)
+
+		for i := start; i < len(frame); i++ {
+			frame[i] ^= mask[(i-start)%4]
+		}
+	} else {
+		frame = append(frame, payload...)
+	}
+
+	c.writeMutex.Lock()
+	defer c.writeMutex.Unlock()
+
+	_, err := c.conn.Write(frame)
+
+	return err
+}
+
+// acceptKey returns the value of the Sec-WebSocket-Accept header answering the given key.
+func acceptKey(key string) string {
+	h := sha1.Sum([]byte(key + acceptGUID))
+	return base64.StdEncoding.EncodeToString(h[:])
+}
+
+// headerContains returns true if a comma-separated header contains the given token, ignoring case.
+func headerContains(h http.Header, name, token string) bool {
+	for _, v := range h.Values(name) {
+		for _, t := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
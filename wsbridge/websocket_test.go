@@ -0,0 +1,447 @@
+package wsbridge
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// serve starts a WebSocket server sending its connections to the returned channel.
+func serve(t *testing.T) (*httptest.Server, <-chan *Conn) {
+	t.Helper()
+
+	conns := make(chan *Conn, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, err := Upgrade(w, r)
+		if err == nil {
+			conns <- c
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, conns
+}
+
+// wsURL returns the ws:// URL of a test server.
+func wsURL(srv *httptest.Server) string {
+	return "ws://" + strings.TrimPrefix(srv.URL, "http://")
+}
+
+// accept returns the connection accepted by a test server.
+func accept(t *testing.T, conns <-chan *Conn) *Conn {
+	t.Helper()
+
+	select {
+	case c := <-conns:
+		t.Cleanup(func() { c.Close() })
+		return c
+	case <-time.After(5 * time.Second):
+		t.Fatal("no connection accepted")
+		return nil
+	}
+}
+
+// handshake sends a handshake of the given version over a raw TCP connection and returns the response.
+func handshake(t *testing.T, srv *httptest.Server, version string) (net.Conn, *bufio.Reader, *http.Response) {
+	t.Helper()
+
+	nc, err := net.Dial("tcp", srv.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { nc.Close() })
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+	req.Header.Set("Sec-WebSocket-Version", version)
+
+	if err := req.Write(nc); err != nil {
+		t.Fatal(err)
+	}
+
+	r := bufio.NewReader(nc)
+
+	resp, err := http.ReadResponse(r, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp.Body.Close()
+
+	return nc, r, resp
+}
+
+// raw opens a client connection whose frames are written and read by the test, without reading goroutine.
+func raw(t *testing.T, srv *httptest.Server) *Conn {
+	t.Helper()
+
+	nc, r, resp := handshake(t, srv, "13")
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("handshake failed: %s", resp.Status)
+	}
+
+	_ = nc.SetDeadline(time.Now().Add(5 * time.Second))
+
+	return &Conn{conn: nc, r: r, client: true}
+}
+
+// frame encodes a frame, masked with a fixed key if requested.
+func frame(fin bool, op byte, payload []byte, masked bool) []byte {
+	b := op
+	if fin {
+		b |= 0x80
+	}
+
+	out := []byte{b}
+
+	maskBit := byte(0)
+	if masked {
+		maskBit = 0x80
+	}
+
+	switch n := len(payload); {
+	case n < 126:
+		out = append(out, maskBit|byte(n))
+	case n <= 0xFFFF:
+		out = append(out, maskBit|126)
+		out = binary.BigEndian.AppendUint16(out, uint16(n))
+	default:
+		out = append(out, maskBit|127)
+		out = binary.BigEndian.AppendUint64(out, uint64(n))
+	}
+
+	if !masked {
+		return append(out, payload...)
+	}
+
+	mask := []byte{1, 2, 3, 4}
+	out = append(out, mask...)
+
+	for i, c := range payload {
+		out = append(out, c^mask[i%4])
+	}
+
+	return out
+}
+
+// receive waits for the next message of a connection, or the error which closed it.
+func receive(t *testing.T, c *Conn) ([]byte, error) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+
+	for time.Now().Before(deadline) {
+		data, ok, err := c.Receive()
+		if ok || err != nil {
+			return data, err
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	t.Fatal("no message received")
+
+	return nil, nil
+}
+
+// closeStatus reads frames until a close frame, and returns its status code.
+func closeStatus(t *testing.T, c *Conn) uint16 {
+	t.Helper()
+
+	for {
+		_, op, payload, err := c.readFrame()
+		if err != nil {
+			t.Fatalf("no close frame: %v", err)
+		}
+
+		if op != opClose {
+			continue
+		}
+
+		if len(payload) < 2 {
+			return 0
+		}
+
+		return binary.BigEndian.Uint16(payload)
+	}
+}
+
+func TestHandshake(t *testing.T) {
+	srv, conns := serve(t)
+
+	client, err := Dial(wsURL(srv))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer client.Close()
+
+	server := accept(t, conns)
+
+	if err := client.Send([]byte("ping?")); err != nil {
+		t.Fatal(err)
+	}
+
+	if data, err := receive(t, server); err != nil || string(data) != "ping?" {
+		t.Errorf("server received %q, %v, want %q", data, err, "ping?")
+	}
+
+	if err := server.Send([]byte("pong!")); err != nil {
+		t.Fatal(err)
+	}
+
+	if data, err := receive(t, client); err != nil || string(data) != "pong!" {
+		t.Errorf("client received %q, %v, want %q", data, err, "pong!")
+	}
+}
+
+func TestUpgradeRejectsPlainRequests(t *testing.T) {
+	srv, _ := serve(t)
+
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("status is %s, want %d", resp.Status, http.StatusBadRequest)
+	}
+}
+
+func TestUpgradeRejectsOtherVersions(t *testing.T) {
+	srv, _ := serve(t)
+
+	_, _, resp := handshake(t, srv, "8")
+
+	if resp.StatusCode != http.StatusUpgradeRequired {
+		t.Errorf("status is %s, want %d", resp.Status, http.StatusUpgradeRequired)
+	}
+
+	if v := resp.Header.Get("Sec-WebSocket-Version"); v != "13" {
+		t.Errorf("Sec-WebSocket-Version is %q, want 13", v)
+	}
+}
+
+func TestMessageLengths(t *testing.T) {
+	srv, conns := serve(t)
+
+	client, err := Dial(wsURL(srv))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer client.Close()
+
+	server := accept(t, conns)
+
+	// 7-bit, 16-bit and 64-bit lengths, and their limits
+	for _, n := range []int{0, 125, 126, 1000, 0xFFFF, 0x10000, 70000} {
+		msg := bytes.Repeat([]byte{'x'}, n)
+
+		if err := client.Send(msg); err != nil {
+			t.Fatal(err)
+		}
+
+		if data, err := receive(t, server); err != nil || !bytes.Equal(data, msg) {
+			t.Errorf("server received %d bytes, %v, want %d", len(data), err, n)
+		}
+
+		if err := server.Send(msg); err != nil {
+			t.Fatal(err)
+		}
+
+		if data, err := receive(t, client); err != nil || !bytes.Equal(data, msg) {
+			t.Errorf("client received %d bytes, %v, want %d", len(data), err, n)
+		}
+	}
+}
+
+func TestFragmentedMessage(t *testing.T) {
+	srv, conns := serve(t)
+	client := raw(t, srv)
+	server := accept(t, conns)
+
+	// a control frame can be sent between the fragments of a message
+	frames := [][]byte{
+		frame(false, opText, []byte("hel"), true),
+		frame(true, opPing, []byte("p"), true),
+		frame(false, opContinuation, []byte("lo "), true),
+		frame(true, opContinuation, []byte("world"), true),
+	}
+
+	for _, f := range frames {
+		if _, err := client.conn.Write(f); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if data, err := receive(t, server); err != nil || string(data) != "hello world" {
+		t.Errorf("server received %q, %v, want %q", data, err, "hello world")
+	}
+
+	if _, ok, _ := server.Receive(); ok {
+		t.Error("the fragments were received as several messages")
+	}
+}
+
+func TestContinuationWithoutMessage(t *testing.T) {
+	srv, conns := serve(t)
+	client := raw(t, srv)
+	server := accept(t, conns)
+
+	if _, err := client.conn.Write(frame(true, opContinuation, []byte("lo"), true)); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := receive(t, server); !errors.Is(err, errProtocol) {
+		t.Errorf("error is %v, want %v", err, errProtocol)
+	}
+
+	if code := closeStatus(t, client); code != closeProtocolError {
+		t.Errorf("close status is %d, want %d", code, closeProtocolError)
+	}
+}
+
+func TestPingPong(t *testing.T) {
+	srv, conns := serve(t)
+	client := raw(t, srv)
+	accept(t, conns)
+
+	if _, err := client.conn.Write(frame(true, opPing, []byte("are you there?"), true)); err != nil {
+		t.Fatal(err)
+	}
+
+	_, op, payload, err := client.readFrame()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if op != opPong || string(payload) != "are you there?" {
+		t.Errorf("answer is opcode %d %q, want a pong with the ping payload", op, payload)
+	}
+}
+
+func TestClose(t *testing.T) {
+	srv, conns := serve(t)
+
+	client, err := Dial(wsURL(srv))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := accept(t, conns)
+
+	if err := client.Send([]byte("bye")); err != nil {
+		t.Fatal(err)
+	}
+
+	client.Close()
+
+	// the messages received before the close frame are still read
+	if data, err := receive(t, server); err != nil || string(data) != "bye" {
+		t.Errorf("server received %q, %v, want %q", data, err, "bye")
+	}
+
+	if _, err := receive(t, server); err != io.EOF {
+		t.Errorf("error is %v, want %v", err, io.EOF)
+	}
+
+	if err := client.Send([]byte("again")); !errors.Is(err, net.ErrClosed) {
+		t.Errorf("sending on a closed connection returned %v, want %v", err, net.ErrClosed)
+	}
+}
+
+func TestCloseIsEchoed(t *testing.T) {
+	srv, conns := serve(t)
+	client := raw(t, srv)
+	server := accept(t, conns)
+
+	if _, err := client.conn.Write(frame(true, opClose, nil, true)); err != nil {
+		t.Fatal(err)
+	}
+
+	_, op, _, err := client.readFrame()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if op != opClose {
+		t.Errorf("answer is opcode %d, want a close frame", op)
+	}
+
+	if _, err := receive(t, server); err != io.EOF {
+		t.Errorf("error is %v, want %v", err, io.EOF)
+	}
+}
+
+func TestOversizedMessage(t *testing.T) {
+	srv, conns := serve(t)
+	client := raw(t, srv)
+	server := accept(t, conns)
+
+	// the frame is rejected from its header, before its payload is sent
+	if _, err := client.conn.Write(frame(true, opText, make([]byte, MaxMessageSize+1), true)[:14]); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := receive(t, server); !errors.Is(err, errTooLarge) {
+		t.Errorf("error is %v, want %v", err, errTooLarge)
+	}
+
+	if code := closeStatus(t, client); code != closeTooLarge {
+		t.Errorf("close status is %d, want %d", code, closeTooLarge)
+	}
+}
+
+func TestOversizedFragmentedMessage(t *testing.T) {
+	srv, conns := serve(t)
+	client := raw(t, srv)
+	server := accept(t, conns)
+
+	half := make([]byte, MaxMessageSize/2+1)
+
+	for _, f := range [][]byte{frame(false, opBinary, half, true), frame(true, opContinuation, half, true)} {
+		if _, err := client.conn.Write(f); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := receive(t, server); !errors.Is(err, errTooLarge) {
+		t.Errorf("error is %v, want %v", err, errTooLarge)
+	}
+}
+
+func TestUnmaskedClientFrame(t *testing.T) {
+	srv, conns := serve(t)
+	client := raw(t, srv)
+	server := accept(t, conns)
+
+	if _, err := client.conn.Write(frame(true, opText, []byte("hello"), false)); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := receive(t, server); !errors.Is(err, errProtocol) {
+		t.Errorf("error is %v, want %v", err, errProtocol)
+	}
+
+	if code := closeStatus(t, client); code != closeProtocolError {
+		t.Errorf("close status is %d, want %d", code, closeProtocolError)
+	}
+}